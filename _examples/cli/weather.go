@@ -27,12 +27,15 @@
 //          go run weather.go -w here -u f -l ru          # fahrenheit, Russian
 //          go run weather.go -w Dublin -u c -l fi        # celcius, Finnish
 //          go run weather.go -w "Las Vegas" -u k -l es   # kelvin, Spanish
+//          go run weather.go -w Dublin -u c -l en -a     # screen reader friendly
+//          go run weather.go -w Dublin -u c -l en -c     # colored temperatures
 package main
 
 import (
 	"encoding/json"
 	"flag"
-	owm "github.com/briandowns/openweathermap" // "owm" for easier use
+	"fmt"
+	owm "github.com/jbaradwaj103/openweathermap2" // "owm" for easier use
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -47,9 +50,9 @@ const URL = "http://ip-api.com/json"
 // template used for output
 const weatherTemplate = `Current weather for {{.Name}}:
     Conditions: {{range .Weather}} {{.Description}} {{end}}
-    Now:         {{.Main.Temp}} {{.Unit}}
-    High:        {{.Main.TempMax}} {{.Unit}}
-    Low:         {{.Main.TempMin}} {{.Unit}}
+    Now:         {{temp .Main.Temp}} {{.Unit}}
+    High:        {{temp .Main.TempMax}} {{.Unit}}
+    Low:         {{temp .Main.TempMin}} {{.Unit}}
 `
 
 const forecastTemplate = `Weather Forecast for {{.City.Name}}:
@@ -68,6 +71,8 @@ var (
 	unitFlag  = flag.String("u", "", "Unit of measure to display temps in")
 	langFlag  = flag.String("l", "", "Language to display temps in")
 	whenFlag  = flag.String("t", "current", "current | forecast")
+	a11yFlag  = flag.Bool("a", false, "Accessible output without symbols, for screen readers")
+	colorFlag = flag.Bool("c", false, "Color temperatures by band (honors NO_COLOR)")
 )
 
 // Data will hold the result of the query to get the IP
@@ -131,6 +136,26 @@ func getForecast5(location, units, lang string) (*owm.Forecast5WeatherData, erro
 	return forecast, err
 }
 
+// renderCurrent displays the current conditions either with the
+// weather template or, when requested, as screen reader friendly text.
+func renderCurrent(w *owm.CurrentWeatherData) error {
+	if *a11yFlag {
+		_, err := fmt.Fprintln(os.Stdout, w.AccessibleText())
+		return err
+	}
+	theme := owm.NewTheme(owm.DefaultPalette)
+	theme.Disabled = theme.Disabled || !*colorFlag
+	funcs := template.FuncMap{
+		"temp": func(v float64) string { return theme.Temperature(v, w.Unit) },
+	}
+	tmpl, err := template.New("weather").Funcs(funcs).Parse(weatherTemplate)
+	if err != nil {
+		return err
+	}
+	// Render the template and display
+	return tmpl.Execute(os.Stdout, w)
+}
+
 func main() {
 	flag.Parse()
 
@@ -150,14 +175,7 @@ func main() {
 		if err != nil {
 			log.Fatalln(err)
 		}
-		tmpl, err := template.New("weather").Parse(weatherTemplate)
-		if err != nil {
-			log.Fatalln(err)
-		}
-
-		// Render the template and display
-		err = tmpl.Execute(os.Stdout, w)
-		if err != nil {
+		if err := renderCurrent(w); err != nil {
 			log.Fatalln(err)
 		}
 		os.Exit(0)
@@ -169,12 +187,7 @@ func main() {
 		if err != nil {
 			log.Fatalln(err)
 		}
-		tmpl, err := template.New("weather").Parse(weatherTemplate)
-		if err != nil {
-			log.Fatalln(err)
-		}
-		// Render the template and display
-		if err := tmpl.Execute(os.Stdout, w); err != nil {
+		if err := renderCurrent(w); err != nil {
 			log.Fatalln(err)
 		}
 	} else { //forecast
@@ -13,7 +13,7 @@ import (
 	"fmt"
 	"html/template"
 
-	owm "github.com/briandowns/openweathermap"
+	owm "github.com/jbaradwaj103/openweathermap2"
 	//	"io/ioutil"
 
 	"net/http"
@@ -94,9 +94,27 @@ func hereHandler(w http.ResponseWriter, r *http.Request) {
 	t.Execute(w, wd)
 }
 
+// widgetHandler serves the current conditions for the "q" query parameter
+// in the compact JSON shape used by embeddable weather widgets.
+func widgetHandler(w http.ResponseWriter, r *http.Request) {
+	wd, err := getCurrent(r.URL.Query().Get("q"), "C", "en")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	b, err := wd.WidgetJSON()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
 // Run the app
 func main() {
 	http.HandleFunc("/here", hereHandler)
+	http.HandleFunc("/widget", widgetHandler)
 	// Make sure we can serve our icon files once retrieved
 	http.HandleFunc("/static/", func(w http.ResponseWriter, r *http.Request) {
 		http.ServeFile(w, r, r.URL.Path[1:])
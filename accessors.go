@@ -0,0 +1,167 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import "time"
+
+// firstCondition returns the first entry of the weather conditions, if
+// there is one.
+func firstCondition(ws []Weather) (Weather, bool) {
+	if len(ws) == 0 {
+		return Weather{}, false
+	}
+	return ws[0], true
+}
+
+// LastHour returns the rain volume for the last hour in mm and whether
+// the API reported any.
+func (r Rain) LastHour() (float64, bool) { return r.OneH, r.OneH > 0 }
+
+// LastThreeHours returns the rain volume for the last 3 hours in mm and
+// whether the API reported any.
+func (r Rain) LastThreeHours() (float64, bool) { return r.ThreeH, r.ThreeH > 0 }
+
+// LastHour returns the snow volume for the last hour in mm and whether
+// the API reported any.
+func (s Snow) LastHour() (float64, bool) { return s.OneH, s.OneH > 0 }
+
+// LastThreeHours returns the snow volume for the last 3 hours in mm and
+// whether the API reported any.
+func (s Snow) LastThreeHours() (float64, bool) { return s.ThreeH, s.ThreeH > 0 }
+
+// FirstCondition returns the primary weather condition and whether the
+// response contained one. It's safe to call on a nil pointer.
+func (w *CurrentWeatherData) FirstCondition() (Weather, bool) {
+	if w == nil {
+		return Weather{}, false
+	}
+	return firstCondition(w.Weather)
+}
+
+// RainLastHour returns the rain volume for the last hour in mm and
+// whether the API reported any. It's safe to call on a nil pointer.
+func (w *CurrentWeatherData) RainLastHour() (float64, bool) {
+	if w == nil {
+		return 0, false
+	}
+	return w.Rain.LastHour()
+}
+
+// RainLastThreeHours returns the rain volume for the last 3 hours in mm
+// and whether the API reported any. It's safe to call on a nil pointer.
+func (w *CurrentWeatherData) RainLastThreeHours() (float64, bool) {
+	if w == nil {
+		return 0, false
+	}
+	return w.Rain.LastThreeHours()
+}
+
+// SnowLastHour returns the snow volume for the last hour in mm and
+// whether the API reported any. It's safe to call on a nil pointer.
+func (w *CurrentWeatherData) SnowLastHour() (float64, bool) {
+	if w == nil {
+		return 0, false
+	}
+	return w.Snow.LastHour()
+}
+
+// SnowLastThreeHours returns the snow volume for the last 3 hours in mm
+// and whether the API reported any. It's safe to call on a nil pointer.
+func (w *CurrentWeatherData) SnowLastThreeHours() (float64, bool) {
+	if w == nil {
+		return 0, false
+	}
+	return w.Snow.LastThreeHours()
+}
+
+// FirstCondition returns the primary weather condition of the forecast
+// entry and whether there is one.
+func (f Forecast5WeatherList) FirstCondition() (Weather, bool) { return firstCondition(f.Weather) }
+
+// FirstCondition returns the primary weather condition of the forecast
+// entry and whether there is one.
+func (f Forecast16WeatherList) FirstCondition() (Weather, bool) { return firstCondition(f.Weather) }
+
+// FirstCondition returns the primary weather condition of the history
+// entry and whether there is one.
+func (h WeatherHistory) FirstCondition() (Weather, bool) { return firstCondition(h.Weather) }
+
+// FirstCondition returns the primary current weather condition and
+// whether there is one.
+func (o OneCallCurrentData) FirstCondition() (Weather, bool) { return firstCondition(o.Weather) }
+
+// FirstCondition returns the primary weather condition for the hour and
+// whether there is one.
+func (o OneCallHourlyData) FirstCondition() (Weather, bool) { return firstCondition(o.Weather) }
+
+// FirstCondition returns the primary weather condition for the day and
+// whether there is one.
+func (o OneCallDailyData) FirstCondition() (Weather, bool) { return firstCondition(o.Weather) }
+
+// unixTime converts a Unix timestamp of the API to a time in loc.
+func unixTime(sec int, loc *time.Location) time.Time { return time.Unix(int64(sec), 0).In(loc) }
+
+// SunriseTime returns the sunrise in UTC, as Sys doesn't carry the city's
+// timezone; CurrentWeatherData.SunriseTime returns it in local time.
+func (s Sys) SunriseTime() time.Time { return unixTime(s.Sunrise, time.UTC) }
+
+// SunsetTime returns the sunset in UTC, as Sys doesn't carry the city's
+// timezone; CurrentWeatherData.SunsetTime returns it in local time.
+func (s Sys) SunsetTime() time.Time { return unixTime(s.Sunset, time.UTC) }
+
+// Location returns the city's timezone as a fixed offset from UTC. It's
+// safe to call on a nil pointer, which returns UTC.
+func (w *CurrentWeatherData) Location() *time.Location {
+	if w == nil {
+		return time.UTC
+	}
+	return time.FixedZone(w.Name, w.Timezone)
+}
+
+// Time returns when the data was calculated, in the city's timezone. It's
+// safe to call on a nil pointer, which returns the zero time.
+func (w *CurrentWeatherData) Time() time.Time {
+	if w == nil {
+		return time.Time{}
+	}
+	return unixTime(w.Dt, w.Location())
+}
+
+// SunriseTime returns the sunrise in the city's timezone. It's safe to
+// call on a nil pointer, which returns the zero time.
+func (w *CurrentWeatherData) SunriseTime() time.Time {
+	if w == nil {
+		return time.Time{}
+	}
+	return unixTime(w.Sys.Sunrise, w.Location())
+}
+
+// SunsetTime returns the sunset in the city's timezone. It's safe to call
+// on a nil pointer, which returns the zero time.
+func (w *CurrentWeatherData) SunsetTime() time.Time {
+	if w == nil {
+		return time.Time{}
+	}
+	return unixTime(w.Sys.Sunset, w.Location())
+}
+
+// Location returns the city's timezone as a fixed offset from UTC.
+func (c City) Location() *time.Location { return time.FixedZone(c.Name, c.Timezone) }
+
+// SunriseTime returns the sunrise in the city's timezone.
+func (c City) SunriseTime() time.Time { return unixTime(c.Sunrise, c.Location()) }
+
+// SunsetTime returns the sunset in the city's timezone.
+func (c City) SunsetTime() time.Time { return unixTime(c.Sunset, c.Location()) }
@@ -0,0 +1,92 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"testing"
+	"time"
+)
+
+// TestFirstCondition will verify missing weather blocks don't panic.
+func TestFirstCondition(t *testing.T) {
+	var w *CurrentWeatherData
+	if _, ok := w.FirstCondition(); ok {
+		t.Error("expected no condition on nil data")
+	}
+
+	w = &CurrentWeatherData{}
+	if _, ok := w.FirstCondition(); ok {
+		t.Error("expected no condition on empty data")
+	}
+
+	w.Weather = []Weather{{ID: 500, Main: "Rain"}, {ID: 701, Main: "Mist"}}
+	if c, ok := w.FirstCondition(); !ok || c.ID != 500 {
+		t.Errorf("expected condition 500, got %v", c)
+	}
+
+	if _, ok := (OneCallHourlyData{}).FirstCondition(); ok {
+		t.Error("expected no condition on empty hourly data")
+	}
+}
+
+// TestPrecipitationAccessors will verify missing rain and snow blocks are
+// reported as absent.
+func TestPrecipitationAccessors(t *testing.T) {
+	var w *CurrentWeatherData
+	if _, ok := w.RainLastHour(); ok {
+		t.Error("expected no rain on nil data")
+	}
+
+	w = &CurrentWeatherData{Rain: Rain{OneH: 0.4}}
+	if v, ok := w.RainLastHour(); !ok || v != 0.4 {
+		t.Errorf("expected 0.4mm of rain, got %v", v)
+	}
+	if _, ok := w.RainLastThreeHours(); ok {
+		t.Error("expected no 3h rain")
+	}
+	if _, ok := w.SnowLastHour(); ok {
+		t.Error("expected no snow")
+	}
+
+	w.Snow = Snow{ThreeH: 2}
+	if v, ok := w.SnowLastThreeHours(); !ok || v != 2 {
+		t.Errorf("expected 2mm of snow, got %v", v)
+	}
+}
+
+// TestTimeAccessors will verify timestamps are converted to the city's
+// local time.
+func TestTimeAccessors(t *testing.T) {
+	var w *CurrentWeatherData
+	if !w.Time().IsZero() || w.Location() != time.UTC {
+		t.Error("expected the zero time in UTC on nil data")
+	}
+
+	w = &CurrentWeatherData{Name: "Tokyo", Dt: 1700000000, Timezone: 9 * 3600, Sys: Sys{Sunrise: 1699997000, Sunset: 1700034800}}
+	if got := w.Time().Format("2006-01-02 15:04 -0700"); got != "2023-11-15 07:13 +0900" {
+		t.Errorf("unexpected local time %s", got)
+	}
+	if got := w.SunriseTime().Format("15:04"); got != "06:23" {
+		t.Errorf("unexpected sunrise %s", got)
+	}
+	if !w.SunsetTime().Equal(w.Sys.SunsetTime()) || w.Sys.SunsetTime().Location() != time.UTC {
+		t.Error("expected the sunset to be the same instant in UTC on Sys")
+	}
+
+	c := City{Name: "Oslo", Timezone: 3600, Sunset: 1700034800}
+	if got := c.SunsetTime().Format("15:04"); got != "08:53" {
+		t.Errorf("unexpected sunset %s", got)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+// MarineCategory is a small craft advisory style rating of conditions on
+// the water, in increasing order of danger.
+type MarineCategory int
+
+// Marine categories loosely follow the US National Weather Service
+// coastal products.
+const (
+	MarineSafe MarineCategory = iota
+	MarineCaution
+	MarineSmallCraftAdvisory
+	MarineGaleWarning
+	MarineStormWarning
+)
+
+var marineCategoryNames = [...]string{"safe", "caution", "small craft advisory", "gale warning", "storm warning"}
+
+func (c MarineCategory) String() string {
+	if c < 0 || int(c) >= len(marineCategoryNames) {
+		return "unknown"
+	}
+	return marineCategoryNames[c]
+}
+
+// MarineThresholds holds the limits used to rate conditions. Wind limits
+// are in knots and apply to the sustained wind or, scaled by GustFactor,
+// the gusts. Visibility is in meters.
+type MarineThresholds struct {
+	CautionWind  float64
+	AdvisoryWind float64
+	GaleWind     float64
+	StormWind    float64
+	// GustFactor relates gusts to sustained wind: a gust counts like a
+	// sustained wind of gust / GustFactor.
+	GustFactor float64
+	// MinVisibility below which at least an advisory is raised.
+	MinVisibility int
+}
+
+// DefaultMarineThresholds uses the NWS wind ranges: an advisory from 22
+// knots, a gale warning from 34 and a storm warning from 48, with
+// visibility under one nautical mile also warranting an advisory.
+var DefaultMarineThresholds = MarineThresholds{
+	CautionWind:   15,
+	AdvisoryWind:  22,
+	GaleWind:      34,
+	StormWind:     48,
+	GustFactor:    1.3,
+	MinVisibility: 1852,
+}
+
+// MarineAdvisory is the rating of conditions along with the reasons that
+// raised it.
+type MarineAdvisory struct {
+	Category MarineCategory
+	Reasons  []string
+}
+
+// raise lifts the category to c, recording the reason.
+func (a *MarineAdvisory) raise(c MarineCategory, reason string) {
+	if c > a.Category {
+		a.Category = c
+	}
+	a.Reasons = append(a.Reasons, reason)
+}
+
+// toKnots converts a wind speed in the given OWM unit system to knots.
+func toKnots(v float64, unit Unit) float64 {
+	return Speed{Value: v, Unit: unit}.ToKnots()
+}
+
+// windCategory rates a sustained wind in knots.
+func (t MarineThresholds) windCategory(knots float64) MarineCategory {
+	switch {
+	case knots >= t.StormWind:
+		return MarineStormWarning
+	case knots >= t.GaleWind:
+		return MarineGaleWarning
+	case knots >= t.AdvisoryWind:
+		return MarineSmallCraftAdvisory
+	case knots >= t.CautionWind:
+		return MarineCaution
+	}
+	return MarineSafe
+}
+
+// Marine rates the wind, gusts, visibility and thunderstorm risk of the
+// given conditions. Wind speeds are in the OWM unit system named by unit
+// and conditionID is the OWM condition code. A zero visibility is taken
+// as not reported.
+func Marine(wind Wind, visibility, conditionID int, unit Unit, t MarineThresholds) MarineAdvisory {
+	var a MarineAdvisory
+
+	if c := t.windCategory(toKnots(wind.Speed, unit)); c > MarineSafe {
+		a.raise(c, "wind")
+	}
+	if t.GustFactor > 0 {
+		if c := t.windCategory(toKnots(wind.Gust, unit) / t.GustFactor); c > MarineSafe {
+			a.raise(c, "gusts")
+		}
+	}
+	if visibility > 0 && visibility < t.MinVisibility {
+		a.raise(MarineSmallCraftAdvisory, "visibility")
+	}
+	if conditionID >= 200 && conditionID < 300 {
+		a.raise(MarineSmallCraftAdvisory, "thunderstorm")
+	}
+	return a
+}
+
+// Marine rates the current conditions for small craft.
+func (w *CurrentWeatherData) Marine(t MarineThresholds) MarineAdvisory {
+	var condition int
+	if c, ok := w.FirstCondition(); ok {
+		condition = c.ID
+	}
+	return Marine(w.Wind, w.Visibility, condition, w.Unit, t)
+}
@@ -0,0 +1,51 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"testing"
+)
+
+// TestMarine will verify wind, gusts, visibility and thunderstorms raise
+// the expected categories.
+func TestMarine(t *testing.T) {
+	tests := []struct {
+		name     string
+		w        *CurrentWeatherData
+		expected MarineCategory
+		reasons  int
+	}{
+		{"calm", &CurrentWeatherData{Unit: "metric", Wind: Wind{Speed: 3}, Visibility: 10000}, MarineSafe, 0},
+		{"breezy", &CurrentWeatherData{Unit: "metric", Wind: Wind{Speed: 8}}, MarineCaution, 1},
+		{"advisory", &CurrentWeatherData{Unit: "imperial", Wind: Wind{Speed: 28}}, MarineSmallCraftAdvisory, 1},
+		{"gusty", &CurrentWeatherData{Unit: "metric", Wind: Wind{Speed: 9, Gust: 25}}, MarineGaleWarning, 2},
+		{"storm", &CurrentWeatherData{Unit: "metric", Wind: Wind{Speed: 26}}, MarineStormWarning, 1},
+		{"fog", &CurrentWeatherData{Unit: "metric", Visibility: 800}, MarineSmallCraftAdvisory, 1},
+		{"thunderstorm", &CurrentWeatherData{Unit: "metric", Weather: []Weather{{ID: 211}}}, MarineSmallCraftAdvisory, 1},
+	}
+
+	for _, tt := range tests {
+		a := tt.w.Marine(DefaultMarineThresholds)
+		if a.Category != tt.expected || len(a.Reasons) != tt.reasons {
+			t.Errorf("%s: expected %s with %d reasons, got %s %v", tt.name, tt.expected, tt.reasons, a.Category, a.Reasons)
+		}
+	}
+
+	strict := DefaultMarineThresholds
+	strict.CautionWind = 5
+	if a := (&CurrentWeatherData{Unit: "metric", Wind: Wind{Speed: 3}}).Marine(strict); a.Category != MarineCaution {
+		t.Errorf("expected custom thresholds to apply, got %s", a.Category)
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errInvalidRule = errors.New("invalid alert rule")
+
+// alertSeverityWords maps words found in alert event names, such as the
+// NWS "Warning", "Watch" and "Advisory" or the Met Office colors, to the
+// severity they imply. The most severe matching word wins.
+var alertSeverityWords = map[string]ConditionSeverity{
+	"emergency": SeverityExtreme,
+	"extreme":   SeverityExtreme,
+	"red":       SeverityExtreme,
+	"warning":   SeveritySevere,
+	"amber":     SeveritySevere,
+	"orange":    SeveritySevere,
+	"watch":     SeverityModerate,
+	"yellow":    SeverityModerate,
+	"advisory":  SeverityMinor,
+	"statement": SeverityMinor,
+	"outlook":   SeverityMinor,
+}
+
+// Severity estimates how severe the alert is from the words of its event
+// name, as OWM doesn't grade alerts. Alerts without a telling word are
+// SeverityMinor.
+func (a OneCallAlertData) Severity() ConditionSeverity {
+	severity := SeverityMinor
+	for _, w := range strings.FieldsFunc(strings.ToLower(a.Event), func(r rune) bool {
+		return r == ' ' || r == '-' || r == '/' || r == ','
+	}) {
+		if s, ok := alertSeverityWords[w]; ok && s > severity {
+			severity = s
+		}
+	}
+	return severity
+}
+
+// AlertRule decides which alerts are routed to a handler.
+type AlertRule struct {
+	Name  string `json:"name"`
+	Route string `json:"route"` // handler receiving the matched alerts
+	// MinSeverity is the least severe alert matched.
+	MinSeverity ConditionSeverity `json:"min_severity"`
+	// Keywords, if any, must match the event or a tag of the alert and
+	// Exclude must not, ignoring case.
+	Keywords []string `json:"keywords,omitempty"`
+	Exclude  []string `json:"exclude,omitempty"`
+	// QuietFrom and QuietUntil, as "22:00" and "07:00", hold alerts back
+	// during the night unless they're at least QuietBypass severe. Empty
+	// disables quiet hours and a zero QuietBypass holds every alert back.
+	QuietFrom   string            `json:"quiet_from,omitempty"`
+	QuietUntil  string            `json:"quiet_until,omitempty"`
+	QuietBypass ConditionSeverity `json:"quiet_bypass,omitempty"`
+
+	quietFrom, quietUntil int // minutes past midnight
+}
+
+// compile validates the rule and parses its quiet hours.
+func (r *AlertRule) compile() error {
+	if r.Route == "" {
+		return fmt.Errorf("%w: %q has no route", errInvalidRule, r.Name)
+	}
+	if (r.QuietFrom == "") != (r.QuietUntil == "") {
+		return fmt.Errorf("%w: %q needs both ends of its quiet hours", errInvalidRule, r.Name)
+	}
+	if r.QuietFrom == "" {
+		return nil
+	}
+	for _, q := range []struct {
+		s string
+		m *int
+	}{{r.QuietFrom, &r.quietFrom}, {r.QuietUntil, &r.quietUntil}} {
+		t, err := time.Parse("15:04", q.s)
+		if err != nil {
+			return fmt.Errorf("%w: %q has quiet hour %q", errInvalidRule, r.Name, q.s)
+		}
+		*q.m = t.Hour()*60 + t.Minute()
+	}
+	return nil
+}
+
+// Matches reports whether the alert should be routed at now, which is in
+// the time zone of the quiet hours.
+func (r AlertRule) Matches(a OneCallAlertData, now time.Time) bool {
+	severity := a.Severity()
+	if severity < r.MinSeverity {
+		return false
+	}
+	if len(r.Keywords) > 0 && !alertMentions(a, r.Keywords) {
+		return false
+	}
+	if alertMentions(a, r.Exclude) {
+		return false
+	}
+	if r.quiet(now) && (r.QuietBypass == SeverityNone || severity < r.QuietBypass) {
+		return false
+	}
+	return true
+}
+
+// quiet reports whether now falls in the rule's quiet hours, which may
+// span midnight.
+func (r AlertRule) quiet(now time.Time) bool {
+	if r.QuietFrom == "" {
+		return false
+	}
+	m := now.Hour()*60 + now.Minute()
+	if r.quietFrom <= r.quietUntil {
+		return m >= r.quietFrom && m < r.quietUntil
+	}
+	return m >= r.quietFrom || m < r.quietUntil
+}
+
+// alertMentions reports whether the event or a tag of the alert contains
+// one of the words.
+func alertMentions(a OneCallAlertData, words []string) bool {
+	texts := append([]string{a.Event}, a.Tags...)
+	for _, w := range words {
+		for _, t := range texts {
+			if strings.Contains(strings.ToLower(t), strings.ToLower(w)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LoadAlertRules reads rules from a JSON config file holding an array of
+// rules, e.g.
+//
+//	[{"name": "storms", "route": "pager", "min_severity": "severe",
+//	  "keywords": ["wind", "thunderstorm"],
+//	  "quiet_from": "22:00", "quiet_until": "07:00", "quiet_bypass": "extreme"}]
+func LoadAlertRules(r io.Reader) ([]AlertRule, error) {
+	var rules []AlertRule
+	if err := json.NewDecoder(r).Decode(&rules); err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// AlertRouter hands alerts matching its rules to the handlers of their
+// routes. It's safe for concurrent use.
+type AlertRouter struct {
+	mu       sync.RWMutex
+	rules    []AlertRule
+	handlers map[string]func(OneCallAlertData) error
+	loc      *time.Location
+	now      func() time.Time
+}
+
+// NewAlertRouter returns a router applying the rules in order, with quiet
+// hours in loc.
+func NewAlertRouter(loc *time.Location, rules ...AlertRule) (*AlertRouter, error) {
+	r := &AlertRouter{
+		handlers: make(map[string]func(OneCallAlertData) error),
+		loc:      loc,
+		now:      time.Now,
+	}
+	for _, rule := range rules {
+		if err := rule.compile(); err != nil {
+			return nil, err
+		}
+		r.rules = append(r.rules, rule)
+	}
+	return r, nil
+}
+
+// Handle registers the handler of a route, replacing any previous one.
+func (r *AlertRouter) Handle(route string, fn func(OneCallAlertData) error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[route] = fn
+}
+
+// Route hands the alert to the handler of every route with a matching
+// rule, once per route, and returns the routes it was sent to. Routes
+// without a handler are skipped. Handlers are called without holding the
+// router's lock, so they may register handlers in turn.
+func (r *AlertRouter) Route(a OneCallAlertData, now time.Time) ([]string, error) {
+	type target struct {
+		route string
+		fn    func(OneCallAlertData) error
+	}
+	r.mu.RLock()
+	now = now.In(r.loc)
+	var targets []target
+	seen := make(map[string]bool)
+	for _, rule := range r.rules {
+		if seen[rule.Route] || !rule.Matches(a, now) {
+			continue
+		}
+		seen[rule.Route] = true
+		if fn, ok := r.handlers[rule.Route]; ok {
+			targets = append(targets, target{rule.Route, fn})
+		}
+	}
+	r.mu.RUnlock()
+
+	var routed []string
+	for _, t := range targets {
+		if err := t.fn(a); err != nil {
+			return routed, fmt.Errorf("%s route: %w", t.route, err)
+		}
+		routed = append(routed, t.route)
+	}
+	return routed, nil
+}
+
+// Subscribe routes the alerts started or updated on the bus, e.g. by an
+// AlertTracker, so repeats aren't routed again. Failures are dropped as
+// there is no request to report them to.
+func (r *AlertRouter) Subscribe(b *Bus) {
+	b.Subscribe(func(e Event) {
+		switch e := e.(type) {
+		case AlertStarted:
+			r.Route(e.Alert, r.now())
+		case AlertUpdated:
+			r.Route(e.Alert, r.now())
+		}
+	})
+}
+
+// PublishAlert returns a handler publishing the alerts it receives as
+// JSON to subject.
+func PublishAlert(p Publisher, subject string) func(OneCallAlertData) error {
+	return func(a OneCallAlertData) error {
+		b, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		return p.Publish(subject, b)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestAlertSeverity will verify the severity is inferred from the event.
+func TestAlertSeverity(t *testing.T) {
+	for event, expected := range map[string]ConditionSeverity{
+		"Excessive Heat Warning":          SeveritySevere,
+		"Winter Storm Watch":              SeverityModerate,
+		"Yellow Warning - Wind":           SeveritySevere,
+		"Red Extreme heat":                SeverityExtreme,
+		"Small Craft Advisory":            SeverityMinor,
+		"Hydrological State Bulletin":     SeverityMinor,
+		"Extreme Fire Danger / Emergency": SeverityExtreme,
+	} {
+		if s := (OneCallAlertData{Event: event}).Severity(); s != expected {
+			t.Errorf("%s: expected %v, got %v", event, expected, s)
+		}
+	}
+}
+
+// TestAlertRouter will verify alerts are routed by severity, keywords and
+// quiet hours.
+func TestAlertRouter(t *testing.T) {
+	rules, err := LoadAlertRules(strings.NewReader(`[
+		{"name": "storms", "route": "pager", "min_severity": "severe", "keywords": ["wind", "storm"],
+		 "quiet_from": "22:00", "quiet_until": "07:00", "quiet_bypass": "extreme"},
+		{"name": "everything", "route": "feed", "exclude": ["test"]},
+		{"name": "duplicate", "route": "feed", "keywords": ["wind"]}
+	]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	router, err := NewAlertRouter(time.UTC, rules...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string][]string{}
+	for _, route := range []string{"pager", "feed"} {
+		route := route
+		router.Handle(route, func(a OneCallAlertData) error {
+			got[route] = append(got[route], a.Event)
+			return nil
+		})
+	}
+
+	day := time.Date(2023, 11, 14, 12, 0, 0, 0, time.UTC)
+	night := time.Date(2023, 11, 14, 23, 30, 0, 0, time.UTC)
+	cases := []struct {
+		event    string
+		at       time.Time
+		expected []string
+	}{
+		{"Wind Warning", day, []string{"pager", "feed"}},
+		{"Wind Advisory", day, []string{"feed"}},
+		{"Wind Warning", night, []string{"feed"}},
+		{"Storm Emergency", night, []string{"pager", "feed"}},
+		{"Test Message", day, nil},
+	}
+	for _, c := range cases {
+		routed, err := router.Route(OneCallAlertData{Event: c.event}, c.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(routed, c.expected) {
+			t.Errorf("%s at %v: expected %v, got %v", c.event, c.at, c.expected, routed)
+		}
+	}
+	if len(got["feed"]) != 4 || len(got["pager"]) != 2 {
+		t.Errorf("unexpected deliveries %v", got)
+	}
+}
+
+// TestAlertRouterSubscribe will verify tracked alerts are routed and
+// published once.
+func TestAlertRouterSubscribe(t *testing.T) {
+	router, err := NewAlertRouter(time.UTC, AlertRule{Name: "all", Route: "bus"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := memoryPublisher{}
+	router.Handle("bus", PublishAlert(pub, "alerts"))
+
+	bus := NewBus()
+	router.Subscribe(bus)
+	tracker := NewAlertTracker(bus)
+	alert := OneCallAlertData{SenderName: "NWS", Event: "Flood Watch", Start: 1, End: 2000000000}
+	tracker.Observe([]OneCallAlertData{alert}, time.Unix(100, 0))
+	tracker.Observe([]OneCallAlertData{alert}, time.Unix(200, 0))
+
+	if len(pub["alerts"]) != 1 || !strings.Contains(pub["alerts"][0], `"event":"Flood Watch"`) {
+		t.Errorf("unexpected messages %v", pub)
+	}
+}
+
+// TestInvalidAlertRules will verify broken rules are rejected.
+func TestInvalidAlertRules(t *testing.T) {
+	for _, r := range []AlertRule{
+		{Name: "no route"},
+		{Name: "half quiet", Route: "x", QuietFrom: "22:00"},
+		{Name: "bad hour", Route: "x", QuietFrom: "25:00", QuietUntil: "07:00"},
+	} {
+		if _, err := NewAlertRouter(time.UTC, r); err == nil {
+			t.Errorf("%s: expected an error", r.Name)
+		}
+	}
+	if _, err := LoadAlertRules(strings.NewReader(`[{"route": "x", "min_severity": "dire"}]`)); err == nil {
+		t.Error("expected an error for an unknown severity")
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// AlertTracker follows the alerts of a location across polls. Alerts
+// from the same sender for the same event with overlapping time windows
+// are the same alert, so a notification is only due when one starts,
+// changes or expires. It's safe for concurrent use.
+type AlertTracker struct {
+	mu     sync.Mutex
+	bus    *Bus
+	active []OneCallAlertData
+	now    func() time.Time
+}
+
+// NewAlertTracker returns a tracker publishing the lifecycle events of
+// the alerts on b, which may be nil to only use the returned events.
+func NewAlertTracker(b *Bus) *AlertTracker {
+	return &AlertTracker{bus: b, now: time.Now}
+}
+
+// Observe records the alerts of a poll at now and returns the lifecycle
+// events they caused, which are also published on the tracker's bus.
+func (t *AlertTracker) Observe(alerts []OneCallAlertData, now time.Time) []Event {
+	t.mu.Lock()
+	var events []Event
+	var active []OneCallAlertData
+	previous := append([]OneCallAlertData(nil), t.active...)
+
+	for _, a := range alerts {
+		if i := sameAlert(active, a); i >= 0 {
+			continue // repeated within the poll
+		}
+		i := sameAlert(previous, a)
+		if int64(a.End) <= now.Unix() {
+			if i >= 0 {
+				events = append(events, AlertExpired{Alert: a})
+				previous = append(previous[:i], previous[i+1:]...)
+			}
+			continue
+		}
+		if i < 0 {
+			events = append(events, AlertStarted{Alert: a})
+		} else {
+			if !reflect.DeepEqual(previous[i], a) {
+				events = append(events, AlertUpdated{Alert: a, Previous: previous[i]})
+			}
+			previous = append(previous[:i], previous[i+1:]...)
+		}
+		active = append(active, a)
+	}
+	for _, a := range previous {
+		events = append(events, AlertExpired{Alert: a})
+	}
+	t.active = active
+	t.mu.Unlock()
+
+	for _, e := range events {
+		t.bus.publish(e)
+	}
+	return events
+}
+
+// Active returns the alerts currently in effect.
+func (t *AlertTracker) Active() []OneCallAlertData {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]OneCallAlertData(nil), t.active...)
+}
+
+// Stage returns a pipeline stage observing the alerts of every one call
+// result.
+func (t *AlertTracker) Stage() Stage {
+	return Stage{Name: "alerts", Run: func(result interface{}) error {
+		if w, ok := result.(*OneCallData); ok {
+			t.Observe(w.Alerts, t.now())
+		}
+		return nil
+	}}
+}
+
+// sameAlert returns the index of the alert in alerts that a is a repeat
+// of, or -1.
+func sameAlert(alerts []OneCallAlertData, a OneCallAlertData) int {
+	for i, b := range alerts {
+		if a.SenderName == b.SenderName && a.Event == b.Event && a.Start <= b.End && b.Start <= a.End {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,77 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestAlertTracker will verify alerts are deduplicated across polls and
+// their lifecycle is reported once.
+func TestAlertTracker(t *testing.T) {
+	bus := NewBus()
+	var published []Event
+	bus.Subscribe(func(e Event) { published = append(published, e) })
+	tracker := NewAlertTracker(bus)
+
+	now := time.Unix(1700000000, 0)
+	wind := OneCallAlertData{SenderName: "Met Office", Event: "Wind", Start: 1699990000, End: 1700020000, Description: "Gales"}
+	fog := OneCallAlertData{SenderName: "Met Office", Event: "Fog", Start: 1699990000, End: 1700005000}
+
+	events := tracker.Observe([]OneCallAlertData{wind, fog, wind}, now)
+	if !reflect.DeepEqual(events, []Event{AlertStarted{Alert: wind}, AlertStarted{Alert: fog}}) {
+		t.Errorf("unexpected events %v", events)
+	}
+	if events := tracker.Observe([]OneCallAlertData{wind, fog}, now.Add(10*time.Minute)); len(events) != 0 {
+		t.Errorf("expected repeats to be silent, got %v", events)
+	}
+
+	// The wind warning is extended and the fog one lapses.
+	extended := wind
+	extended.End = 1700030000
+	events = tracker.Observe([]OneCallAlertData{extended, fog}, now.Add(2*time.Hour))
+	expected := []Event{AlertUpdated{Alert: extended, Previous: wind}, AlertExpired{Alert: fog}}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("expected %v, got %v", expected, events)
+	}
+	if active := tracker.Active(); !reflect.DeepEqual(active, []OneCallAlertData{extended}) {
+		t.Errorf("unexpected active alerts %v", active)
+	}
+
+	// Dropped from the feed before its end.
+	events = tracker.Observe(nil, now.Add(3*time.Hour))
+	if !reflect.DeepEqual(events, []Event{AlertExpired{Alert: extended}}) {
+		t.Errorf("unexpected events %v", events)
+	}
+	if len(published) != 5 {
+		t.Errorf("expected every event to be published, got %d", len(published))
+	}
+}
+
+// TestAlertTrackerStage will verify the stage observes one call results.
+func TestAlertTrackerStage(t *testing.T) {
+	tracker := NewAlertTracker(nil)
+	tracker.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	w := &OneCallData{Alerts: []OneCallAlertData{{Event: "Heat", Start: 1699990000, End: 1700020000}}}
+	if err := tracker.Stage().Run(w); err != nil {
+		t.Fatal(err)
+	}
+	if len(tracker.Active()) != 1 {
+		t.Errorf("expected the alert to be tracked")
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// DateConverter labels a date in an alternate calendar, such as the Hijri
+// calendar, for the given language.
+type DateConverter interface {
+	Label(t time.Time, lang string) string
+}
+
+// DateConverterFunc adapts a function to a DateConverter.
+type DateConverterFunc func(t time.Time, lang string) string
+
+// Label calls f(t, lang).
+func (f DateConverterFunc) Label(t time.Time, lang string) string { return f(t, lang) }
+
+// Built in converters for the Solar Hijri calendar used in Iran and
+// Afghanistan, and the tabular Islamic calendar. The tabular calendar is
+// arithmetic and may differ by a day from calendars based on moon
+// sighting or Umm al-Qura.
+var (
+	SolarHijri   DateConverter = DateConverterFunc(solarHijriLabel)
+	TabularHijri DateConverter = DateConverterFunc(tabularHijriLabel)
+)
+
+// DateConverterFor returns the alternate calendar expected by speakers of
+// the language, or nil if there is none.
+func DateConverterFor(lang string) DateConverter {
+	switch strings.ToUpper(lang) {
+	case "FA":
+		return SolarHijri
+	case "AR":
+		return TabularHijri
+	}
+	return nil
+}
+
+// WithDateConverter labels the dates of rendered daily summaries in the
+// converter's calendar as well.
+func WithDateConverter(c DateConverter) Option {
+	return func(s *Settings) error {
+		if c == nil {
+			return errInvalidOption
+		}
+		s.dates = c
+		return nil
+	}
+}
+
+var (
+	solarHijriMonths = map[string][12]string{
+		"EN": {"Farvardin", "Ordibehesht", "Khordad", "Tir", "Mordad", "Shahrivar", "Mehr", "Aban", "Azar", "Dey", "Bahman", "Esfand"},
+		"FA": {"فروردین", "اردیبهشت", "خرداد", "تیر", "مرداد", "شهریور", "مهر", "آبان", "آذر", "دی", "بهمن", "اسفند"},
+	}
+	hijriMonths = map[string][12]string{
+		"EN": {"Muharram", "Safar", "Rabi al-Awwal", "Rabi al-Thani", "Jumada al-Awwal", "Jumada al-Thani", "Rajab", "Shaban", "Ramadan", "Shawwal", "Dhu al-Qadah", "Dhu al-Hijjah"},
+		"AR": {"محرم", "صفر", "ربيع الأول", "ربيع الآخر", "جمادى الأولى", "جمادى الآخرة", "رجب", "شعبان", "رمضان", "شوال", "ذو القعدة", "ذو الحجة"},
+	}
+)
+
+// monthLabel formats a day, month and year with the month names of the
+// language, falling back to English.
+func monthLabel(names map[string][12]string, lang string, y, m, d int) string {
+	months, ok := names[strings.ToUpper(lang)]
+	if !ok {
+		months = names["EN"]
+	}
+	return fmt.Sprintf("%d %s %d", d, months[m-1], y)
+}
+
+// julianDay returns the Julian day number of the Gregorian date.
+func julianDay(y, m, d int) int {
+	a := (14 - m) / 12
+	y = y + 4800 - a
+	m = m + 12*a - 3
+	return d + (153*m+2)/5 + 365*y + y/4 - y/100 + y/400 - 32045
+}
+
+// tabularHijriLabel converts t following the arithmetic Islamic calendar.
+func tabularHijriLabel(t time.Time, lang string) string {
+	l := julianDay(t.Year(), int(t.Month()), t.Day()) - 1948440 + 10632
+	n := (l - 1) / 10631
+	l = l - 10631*n + 354
+	j := ((10985-l)/5316)*((50*l)/17719) + (l/5670)*((43*l)/15238)
+	l = l - ((30-j)/15)*((17719*j)/50) - (j/16)*((15238*j)/43) + 29
+	m := (24 * l) / 709
+	d := l - (709*m)/24
+	y := 30*n + j - 30
+	return monthLabel(hijriMonths, lang, y, m, d)
+}
+
+// solarHijriBreaks holds the years the 33 year leap cycles of the Solar
+// Hijri calendar are realigned with the equinox.
+var solarHijriBreaks = []int{-61, 9, 38, 199, 426, 686, 756, 818, 1111, 1181, 1210, 1635, 2060, 2097, 2192, 2262, 2324, 2394, 2456, 3178}
+
+// nowruz returns the March day of the Gregorian year gy the Solar Hijri
+// year starting in it begins on, and how many years ago its last leap
+// year was, where 1 means the year before.
+func nowruz(gy int) (march, leap int) {
+	jy := gy - 621
+	leapJ, jp, jump := -14, solarHijriBreaks[0], 0
+	for _, jm := range solarHijriBreaks[1:] {
+		jump = jm - jp
+		if jy < jm {
+			break
+		}
+		leapJ += jump/33*8 + jump%33/4
+		jp = jm
+	}
+	n := jy - jp
+	leapJ += n/33*8 + (n%33+3)/4
+	if jump%33 == 4 && jump-n == 4 {
+		leapJ++
+	}
+	leapG := gy/4 - (gy/100+1)*3/4 - 150
+	march = 20 + leapJ - leapG
+	if jump-n < 6 {
+		n = n - jump + (jump+4)/33*33
+	}
+	leap = ((n+1)%33 - 1) % 4
+	if leap == -1 {
+		leap = 4
+	}
+	return march, leap
+}
+
+// solarHijriLabel converts t to the Solar Hijri calendar.
+func solarHijriLabel(t time.Time, lang string) string {
+	gy := t.Year()
+	march, leap := nowruz(gy)
+	jy := gy - 621
+	k := julianDay(gy, int(t.Month()), t.Day()) - julianDay(gy, 3, march)
+	if k >= 0 && k <= 185 {
+		return monthLabel(solarHijriMonths, lang, jy, 1+k/31, k%31+1)
+	}
+	if k >= 0 {
+		k -= 186
+	} else {
+		jy--
+		k += 179
+		if leap == 1 {
+			k++
+		}
+	}
+	return monthLabel(solarHijriMonths, lang, jy, 7+k/30, k%30+1)
+}
+
+// DailySummaries renders each day of the forecast as a short line, e.g.
+// "Thu 16 Nov: light rain, 3°C–12°C, 40%", with the day's chance of
+// precipitation last. With WithDateConverter the date is followed by its
+// label in the alternate calendar. Right to left languages are laid out
+// as in CurrentWeatherData.Summary.
+func (w *OneCallData) DailySummaries() []string {
+	symbols, ok := summarySymbols[w.Unit]
+	if !ok {
+		symbols = summarySymbols[Standard]
+	}
+	loc := time.FixedZone(w.Timezone, w.TimezoneOffset)
+	lang := w.Lang
+	degrees := func(v float64) string { return fmt.Sprintf("%d%s", int(math.Round(v)), symbols[0]) }
+
+	lines := make([]string, 0, len(w.Daily))
+	for _, d := range w.Daily {
+		day := time.Unix(int64(d.Dt), 0).In(loc)
+		date := IsolateLTR(day.Format("Mon 2 Jan"), lang)
+		if w.Settings != nil && w.dates != nil {
+			date += " (" + w.dates.Label(day, lang) + ")"
+		}
+
+		parts := make([]string, 0, 3)
+		if len(d.Weather) > 0 && d.Weather[0].Description != "" {
+			parts = append(parts, d.Weather[0].Description)
+		}
+		parts = append(parts,
+			IsolateLTR(degrees(d.Temp.Min)+"–"+degrees(d.Temp.Max), lang),
+			IsolateLTR(fmt.Sprintf("%d%%", int(math.Round(d.Pop*100))), lang),
+		)
+		lines = append(lines, summaryLine(date, parts, lang))
+	}
+	return lines
+}
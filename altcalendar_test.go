@@ -0,0 +1,83 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDateConverters will verify the built in calendars against known
+// dates, including the turn of the Solar Hijri year.
+func TestDateConverters(t *testing.T) {
+	tests := []struct {
+		date string
+		c    DateConverter
+		lang string
+		want string
+	}{
+		{"2023-11-16", SolarHijri, "EN", "25 Aban 1402"},
+		{"2023-09-22", SolarHijri, "EN", "31 Shahrivar 1402"},
+		{"2023-09-23", SolarHijri, "EN", "1 Mehr 1402"},
+		{"2025-03-20", SolarHijri, "EN", "30 Esfand 1403"},
+		{"2025-03-21", SolarHijri, "EN", "1 Farvardin 1404"},
+		{"2024-03-20", SolarHijri, "FA", "1 فروردین 1403"},
+		{"2023-07-19", TabularHijri, "EN", "1 Muharram 1445"},
+		{"2024-04-10", TabularHijri, "DE", "1 Shawwal 1445"},
+		{"2023-03-23", TabularHijri, "AR", "1 رمضان 1444"},
+	}
+	for _, tt := range tests {
+		day, _ := time.Parse("2006-01-02", tt.date)
+		if got := tt.c.Label(day, tt.lang); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.date, tt.want, got)
+		}
+	}
+	day := time.Date(2023, 11, 16, 0, 0, 0, 0, time.UTC)
+	if c := DateConverterFor("fa"); c == nil || c.Label(day, "EN") != "25 Aban 1402" {
+		t.Error("expected the Solar Hijri calendar for Farsi")
+	}
+	if DateConverterFor("EN") != nil {
+		t.Error("expected no alternate calendar for English")
+	}
+}
+
+// TestDailySummaries will verify daily lines are labeled in the
+// configured alternate calendar.
+func TestDailySummaries(t *testing.T) {
+	w, err := NewOneCall("C", "EN", "key", nil, WithDateConverter(TabularHijri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.TimezoneOffset = 3 * 3600
+	w.Daily = []OneCallDailyData{{
+		Dt:      int(time.Date(2024, 4, 10, 9, 0, 0, 0, time.UTC).Unix()),
+		Temp:    Temperature{Min: 12.4, Max: 24.6},
+		Pop:     0.4,
+		Weather: []Weather{{Description: "light rain"}},
+	}}
+	want := "Wed 10 Apr (1 Shawwal 1445): light rain, 12°C–25°C, 40%"
+	if got := w.DailySummaries(); len(got) != 1 || got[0] != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	w.dates = nil
+	if got := w.DailySummaries()[0]; strings.Contains(got, "(") {
+		t.Errorf("expected no alternate date, got %q", got)
+	}
+	if _, err := NewOneCall("C", "EN", "key", nil, WithDateConverter(nil)); err != errInvalidOption {
+		t.Errorf("expected %v, got %v", errInvalidOption, err)
+	}
+}
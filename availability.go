@@ -0,0 +1,139 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// EndpointAvailability is the availability and latency of an endpoint
+// over a report's period. Requests that failed without a response, other
+// than ones canceled by the caller, and 5xx responses count as failures;
+// 4xx responses are the caller's errors and count as available.
+type EndpointAvailability struct {
+	Endpoint     Endpoint
+	Requests     int
+	Failures     int
+	Availability float64 // the fraction of requests that didn't fail
+	P50          time.Duration
+	P90          time.Duration
+	P99          time.Duration
+}
+
+// availabilitySample is the outcome of one request.
+type availabilitySample struct {
+	endpoint Endpoint
+	at       time.Time
+	duration time.Duration
+	failed   bool
+}
+
+// Availability records the outcome of requests published on event buses
+// to report the upstream reliability of each endpoint, e.g. to document
+// an SLA. It's safe for concurrent use.
+type Availability struct {
+	mu      sync.Mutex
+	samples []availabilitySample
+	now     func() time.Time
+}
+
+// NewAvailability returns an availability recorder without samples.
+func NewAvailability() *Availability { return &Availability{now: time.Now} }
+
+// Subscribe records every request finished on the bus. Requests served
+// from a cache don't reach the API and aren't recorded.
+func (a *Availability) Subscribe(b *Bus) {
+	b.Subscribe(func(e Event) {
+		if e, ok := e.(RequestFinished); ok {
+			a.Record(e, a.now())
+		}
+	})
+}
+
+// Record adds the outcome of a request finished at the given time.
+func (a *Availability) Record(e RequestFinished, at time.Time) {
+	failed := e.StatusCode >= http.StatusInternalServerError
+	if e.Err != nil && !errors.Is(e.Err, context.Canceled) {
+		failed = true
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.samples = append(a.samples, availabilitySample{endpoint: e.Endpoint, at: at, duration: e.Duration, failed: failed})
+}
+
+// Prune drops the samples recorded before the given time, bounding the
+// memory used by a long running recorder.
+func (a *Availability) Prune(before time.Time) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	kept := a.samples[:0]
+	for _, s := range a.samples {
+		if !s.at.Before(before) {
+			kept = append(kept, s)
+		}
+	}
+	a.samples = kept
+}
+
+// Report returns the availability of every endpoint with requests
+// recorded from the start up to, but excluding, the end of the period,
+// sorted by endpoint.
+func (a *Availability) Report(from, to time.Time) []EndpointAvailability {
+	a.mu.Lock()
+	durations := make(map[Endpoint][]time.Duration)
+	failures := make(map[Endpoint]int)
+	for _, s := range a.samples {
+		if s.at.Before(from) || !s.at.Before(to) {
+			continue
+		}
+		durations[s.endpoint] = append(durations[s.endpoint], s.duration)
+		if s.failed {
+			failures[s.endpoint]++
+		}
+	}
+	a.mu.Unlock()
+
+	report := make([]EndpointAvailability, 0, len(durations))
+	for e, d := range durations {
+		sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+		report = append(report, EndpointAvailability{
+			Endpoint:     e,
+			Requests:     len(d),
+			Failures:     failures[e],
+			Availability: 1 - float64(failures[e])/float64(len(d)),
+			P50:          percentile(d, 50),
+			P90:          percentile(d, 90),
+			P99:          percentile(d, 99),
+		})
+	}
+	sort.Slice(report, func(i, j int) bool { return report[i].Endpoint < report[j].Endpoint })
+	return report
+}
+
+// percentile returns the nearest rank percentile p of the sorted
+// durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,89 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestAvailability will verify failures and latency percentiles are
+// reported per endpoint over the period.
+func TestAvailability(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	a := NewAvailability()
+	for i := 1; i <= 100; i++ {
+		e := RequestFinished{Endpoint: EndpointCurrent, StatusCode: http.StatusOK, Duration: time.Duration(i) * time.Millisecond}
+		switch i {
+		case 10:
+			e.StatusCode = http.StatusBadGateway
+		case 20:
+			e.StatusCode, e.Err = 0, errors.New("connection reset")
+		case 30:
+			e.StatusCode = http.StatusNotFound
+		case 40:
+			e.StatusCode, e.Err = 0, context.Canceled
+		}
+		a.Record(e, start.Add(time.Duration(i)*time.Second))
+	}
+	a.Record(RequestFinished{Endpoint: EndpointUV, StatusCode: http.StatusOK, Duration: time.Second}, start)
+	a.Record(RequestFinished{Endpoint: EndpointUV, StatusCode: http.StatusOK}, start.Add(-time.Hour))
+
+	report := a.Report(start, start.Add(time.Hour))
+	if len(report) != 2 || report[0].Endpoint != EndpointCurrent || report[1].Requests != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	c := report[0]
+	if c.Requests != 100 || c.Failures != 2 || c.Availability != 0.98 {
+		t.Errorf("expected 2 failures in 100 requests, got %+v", c)
+	}
+	if c.P50 != 50*time.Millisecond || c.P90 != 90*time.Millisecond || c.P99 != 99*time.Millisecond {
+		t.Errorf("unexpected percentiles %v %v %v", c.P50, c.P90, c.P99)
+	}
+
+	a.Prune(start.Add(51 * time.Second))
+	if r := a.Report(start, start.Add(time.Hour)); len(r) != 1 || r[0].Requests != 50 {
+		t.Errorf("expected pruning to keep the last 50 requests, got %+v", r)
+	}
+}
+
+// TestAvailabilitySubscribe will verify requests published on the bus
+// are recorded.
+func TestAvailabilitySubscribe(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"Oslo"}`)
+	})
+	defer ts.Close()
+
+	now := time.Unix(1700000000, 0)
+	a := NewAvailability()
+	a.now = func() time.Time { return now }
+	bus := NewBus()
+	a.Subscribe(bus)
+	c, err := NewCurrent("C", "EN", "key", WithHttpClient(hc), WithEventBus(bus))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CurrentByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if r := a.Report(now, now.Add(time.Second)); len(r) != 1 || r[0].Requests != 1 || r[0].Availability != 1 {
+		t.Errorf("unexpected report %+v", r)
+	}
+}
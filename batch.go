@@ -0,0 +1,180 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+var errQuotaExceeded = errors.New("batch needs more calls than the quota has left")
+
+// BatchJob is a set of requests planned ahead, such as the points of a
+// Grid.
+type BatchJob interface {
+	// Calls returns how many requests the job sends.
+	Calls() int
+	// Coarser returns the job at a lower resolution needing fewer calls,
+	// or false if it can't be shrunk any further.
+	Coarser() (BatchJob, bool)
+}
+
+// FixedJob is a job of a given number of calls that can't be shrunk, e.g.
+// a list of city IDs.
+type FixedJob int
+
+// Calls returns the number of calls of the job.
+func (j FixedJob) Calls() int { return int(j) }
+
+// Coarser always returns false.
+func (j FixedJob) Coarser() (BatchJob, bool) { return j, false }
+
+// Grid is a regular grid of points Step degrees apart within a bounding
+// box, starting from its bottom left corner.
+type Grid struct {
+	LonLeft, LatBottom, LonRight, LatTop float64
+	Step                                 float64
+}
+
+// steps returns the number of points along an axis spanning d degrees.
+func (g Grid) steps(d float64) int {
+	if g.Step <= 0 || d < 0 {
+		return 0
+	}
+	return int(math.Floor(d/g.Step+1e-9)) + 1
+}
+
+// Calls returns the number of points of the grid, one call each.
+func (g Grid) Calls() int {
+	return g.steps(g.LonRight-g.LonLeft) * g.steps(g.LatTop-g.LatBottom)
+}
+
+// Coarser returns the grid with twice the step, until it holds a single
+// point.
+func (g Grid) Coarser() (BatchJob, bool) {
+	if g.Calls() <= 1 {
+		return g, false
+	}
+	g.Step *= 2
+	return g, true
+}
+
+// Points returns the coordinates of every point of the grid, row by row
+// from the bottom.
+func (g Grid) Points() []Coordinates {
+	cols, rows := g.steps(g.LonRight-g.LonLeft), g.steps(g.LatTop-g.LatBottom)
+	points := make([]Coordinates, 0, cols*rows)
+	for r := 0; r < rows; r++ {
+		for c := 0; c < cols; c++ {
+			points = append(points, Coordinates{
+				Longitude: g.LonLeft + float64(c)*g.Step,
+				Latitude:  g.LatBottom + float64(r)*g.Step,
+			})
+		}
+	}
+	return points
+}
+
+// BatchPolicy decides what to do with a job needing more calls than the
+// quota has left.
+type BatchPolicy int
+
+// Batch policies.
+const (
+	// RefuseOverQuota fails planning with errQuotaExceeded.
+	RefuseOverQuota BatchPolicy = iota
+	// ShrinkToQuota lowers the job's resolution until it fits.
+	ShrinkToQuota
+	// ScheduleOverQuota spreads the calls over the time windows in which
+	// the quota refills.
+	ScheduleOverQuota
+)
+
+// BatchWindow is a number of calls which may be sent from At on.
+type BatchWindow struct {
+	At    time.Time
+	Calls int
+}
+
+// BatchPlan is the outcome of planning a job against the quota.
+type BatchPlan struct {
+	// Job is the job to run, coarser than the one planned when shrunk.
+	Job       BatchJob
+	Calls     int
+	Remaining int
+	Windows   []BatchWindow
+}
+
+// Remaining returns how many calls may be sent right away.
+func (l *RateLimiter) Remaining() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	if l.tokens < 0 {
+		return 0
+	}
+	return int(l.tokens)
+}
+
+// Plan estimates the calls the job needs before running it, and compares
+// them with the calls left in the limiter's quota, applying the policy if
+// they don't fit. Planning takes no tokens; the job's requests still have
+// to go through the limiter. A nil limiter has no quota, so every call is
+// planned right away.
+func (l *RateLimiter) Plan(job BatchJob, policy BatchPolicy) (*BatchPlan, error) {
+	calls := job.Calls()
+	if l == nil {
+		return &BatchPlan{Job: job, Calls: calls, Remaining: calls, Windows: []BatchWindow{{At: time.Now(), Calls: calls}}}, nil
+	}
+
+	now := l.now()
+	remaining := l.Remaining()
+	plan := &BatchPlan{Job: job, Calls: calls, Remaining: remaining}
+	if calls > remaining {
+		switch policy {
+		case ShrinkToQuota:
+			for plan.Calls > remaining {
+				coarser, ok := plan.Job.Coarser()
+				if !ok {
+					return nil, errQuotaExceeded
+				}
+				plan.Job, plan.Calls = coarser, coarser.Calls()
+			}
+		case ScheduleOverQuota:
+			if remaining > 0 {
+				plan.Windows = append(plan.Windows, BatchWindow{At: now, Calls: remaining})
+			}
+			burst := int(l.burst)
+			waited := 0
+			for left := calls - remaining; left > 0; {
+				n := burst
+				if left < n {
+					n = left
+				}
+				waited += n
+				left -= n
+				plan.Windows = append(plan.Windows, BatchWindow{At: now.Add(time.Duration(waited) * l.interval), Calls: n})
+			}
+			return plan, nil
+		default:
+			return nil, errQuotaExceeded
+		}
+	}
+	if plan.Calls > 0 {
+		plan.Windows = []BatchWindow{{At: now, Calls: plan.Calls}}
+	}
+	return plan, nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"testing"
+	"time"
+)
+
+// TestGrid will verify grid points and how grids are shrunk.
+func TestGrid(t *testing.T) {
+	g := Grid{LonLeft: 10, LatBottom: 50, LonRight: 11, LatTop: 50.5, Step: 0.25}
+	if g.Calls() != 15 || len(g.Points()) != 15 {
+		t.Fatalf("expected 15 points, got %d", g.Calls())
+	}
+	if p := g.Points()[14]; p.Longitude != 11 || p.Latitude != 50.5 {
+		t.Errorf("unexpected last point %+v", p)
+	}
+	coarser, ok := g.Coarser()
+	if !ok || coarser.Calls() != 6 {
+		t.Errorf("expected 6 points at twice the step, got %d", coarser.Calls())
+	}
+	if _, ok := (Grid{LonLeft: 1, LonRight: 1, Step: 1}).Coarser(); ok {
+		t.Error("expected a single point not to shrink")
+	}
+}
+
+// TestRateLimiterPlan will verify jobs over quota are refused, shrunk or
+// scheduled following the policy.
+func TestRateLimiterPlan(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewRateLimiter(10, time.Minute)
+	l.now = func() time.Time { return now }
+	for i := 0; i < 4; i++ {
+		l.Allow()
+	}
+	g := Grid{LonLeft: 10, LatBottom: 50, LonRight: 11, LatTop: 50.5, Step: 0.25}
+
+	if _, err := l.Plan(g, RefuseOverQuota); err != errQuotaExceeded {
+		t.Errorf("expected %v, got %v", errQuotaExceeded, err)
+	}
+	if _, err := l.Plan(FixedJob(7), ShrinkToQuota); err != errQuotaExceeded {
+		t.Errorf("expected fixed jobs not to shrink, got %v", err)
+	}
+
+	plan, err := l.Plan(g, ShrinkToQuota)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plan.Calls != 6 || plan.Remaining != 6 || plan.Job.(Grid).Step != 0.5 {
+		t.Errorf("unexpected shrunk plan %+v", plan)
+	}
+
+	plan, err = l.Plan(g, ScheduleOverQuota)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []BatchWindow{
+		{At: now, Calls: 6},
+		{At: now.Add(54 * time.Second), Calls: 9},
+	}
+	if len(plan.Windows) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, plan.Windows)
+	}
+	for i, w := range expected {
+		if !plan.Windows[i].At.Equal(w.At) || plan.Windows[i].Calls != w.Calls {
+			t.Errorf("window %d: expected %v, got %v", i, w, plan.Windows[i])
+		}
+	}
+	if l.Remaining() != 6 {
+		t.Error("expected planning not to take tokens")
+	}
+
+	plan, err = l.Plan(FixedJob(3), RefuseOverQuota)
+	if err != nil || len(plan.Windows) != 1 || plan.Windows[0].Calls != 3 {
+		t.Errorf("expected a job within quota to run at once, got %+v, %v", plan, err)
+	}
+	var unlimited *RateLimiter
+	if plan, err := unlimited.Plan(g, RefuseOverQuota); err != nil || plan.Windows[0].Calls != 15 {
+		t.Errorf("expected no quota without a limiter, got %+v, %v", plan, err)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Unicode directional isolates and marks used to keep left to right
+// values intact inside right to left text.
+const (
+	leftToRightIsolate = "\u2066"
+	popDirectional     = "\u2069"
+	rightToLeftMark    = "\u200f"
+)
+
+// rtlLangs holds the supported language codes written right to left.
+var rtlLangs = map[string]bool{"AR": true, "FA": true, "HE": true}
+
+// IsRTL reports whether the language code is written right to left.
+func IsRTL(lang string) bool { return rtlLangs[strings.ToUpper(lang)] }
+
+// IsolateLTR wraps s in a left to right isolate when lang is written right
+// to left, so a number and its unit such as "-3°C" aren't reordered or
+// split by the surrounding text. Other languages get s back unchanged.
+func IsolateLTR(s, lang string) string {
+	if !IsRTL(lang) {
+		return s
+	}
+	return leftToRightIsolate + s + popDirectional
+}
+
+// summarySymbols holds the temperature and speed symbols of each OWM unit
+// system.
+var summarySymbols = map[Unit][2]string{
+	Metric:   {"°C", "m/s"},
+	Imperial: {"°F", "mph"},
+	Standard: {"K", "m/s"},
+}
+
+// Summary renders the current conditions as a short line, e.g. "Paris:
+// light rain, 14°C, 40%, 3 m/s". In right to left languages each value is
+// isolated so it keeps its unit on the same side, the line starts with a
+// right to left mark so it's laid out right to left even when it begins
+// with a digit, and Arabic and Farsi use the Arabic comma.
+func (w *CurrentWeatherData) Summary() string {
+	symbols, ok := summarySymbols[w.Unit]
+	if !ok {
+		symbols = summarySymbols[Standard]
+	}
+	lang := w.Lang
+	value := func(v float64, unit string) string {
+		return IsolateLTR(fmt.Sprintf("%d%s", int(math.Round(v)), unit), lang)
+	}
+
+	parts := make([]string, 0, 4)
+	if len(w.Weather) > 0 && w.Weather[0].Description != "" {
+		parts = append(parts, w.Weather[0].Description)
+	}
+	parts = append(parts,
+		value(w.Main.Temp, symbols[0]),
+		value(float64(w.Main.Humidity), "%"),
+		value(w.Wind.Speed, " "+symbols[1]),
+	)
+
+	return summaryLine(w.Name, parts, lang)
+}
+
+// summaryLine joins the parts of a summary after its title, if any.
+func summaryLine(title string, parts []string, lang string) string {
+	sep := ", "
+	if l := strings.ToUpper(lang); l == "AR" || l == "FA" {
+		sep = "، "
+	}
+	text := strings.Join(parts, sep)
+	if title != "" {
+		text = title + ": " + text
+	}
+	if IsRTL(lang) {
+		text = rightToLeftMark + text
+	}
+	return text
+}
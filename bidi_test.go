@@ -0,0 +1,66 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"testing"
+)
+
+// TestSummary will verify values are isolated and separated the right
+// to left way only for right to left languages.
+func TestSummary(t *testing.T) {
+	w := &CurrentWeatherData{
+		Name:    "Paris",
+		Weather: []Weather{{Description: "light rain"}},
+		Main:    Main{Temp: 13.6, Humidity: 40},
+		Wind:    Wind{Speed: 3.2},
+		Unit:    "metric",
+		Lang:    "EN",
+	}
+	if s := w.Summary(); s != "Paris: light rain, 14°C, 40%, 3 m/s" {
+		t.Errorf("unexpected summary %q", s)
+	}
+
+	w.Name = "القاهرة"
+	w.Lang = "AR"
+	w.Weather[0].Description = "مطر خفيف"
+	w.Unit = "imperial"
+	expected := rightToLeftMark + "القاهرة: مطر خفيف، " + leftToRightIsolate + "14°F" + popDirectional + "، " +
+		leftToRightIsolate + "40%" + popDirectional + "، " + leftToRightIsolate + "3 mph" + popDirectional
+	if s := w.Summary(); s != expected {
+		t.Errorf("expected %q, got %q", expected, s)
+	}
+
+	w.Lang = "HE"
+	w.Name = ""
+	w.Weather = nil
+	expected = rightToLeftMark + leftToRightIsolate + "14°F" + popDirectional + ", " +
+		leftToRightIsolate + "40%" + popDirectional + ", " + leftToRightIsolate + "3 mph" + popDirectional
+	if s := w.Summary(); s != expected {
+		t.Errorf("expected %q, got %q", expected, s)
+	}
+}
+
+// TestIsRTL will verify the right to left languages are detected.
+func TestIsRTL(t *testing.T) {
+	for lang, expected := range map[string]bool{"ar": true, "FA": true, "HE": true, "EN": false, "ZH_CN": false} {
+		if IsRTL(lang) != expected {
+			t.Errorf("%s: expected %v", lang, expected)
+		}
+	}
+	if s := IsolateLTR("-3°C", "fr"); s != "-3°C" {
+		t.Errorf("expected no isolate, got %q", s)
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Cache stores response bodies for WithCache. Implementations, e.g. backed
+// by Redis or memcached, must be safe for concurrent use.
+type Cache interface {
+	// Get returns the body stored under key, if it hasn't expired.
+	Get(key string) ([]byte, bool)
+	// Set stores the body under key for ttl.
+	Set(key string, body []byte, ttl time.Duration)
+}
+
+// WithCache serves repeated requests from the cache for ttl, e.g. 10
+// minutes, instead of calling the API. Only successful responses are
+// cached, unless WithNegativeCache is used, keyed by their URL without the
+// API key so clients with different keys can share the cache. Hits are published on the event bus
+// as CacheHit and neither count against a rate limiter nor update
+// Checksum and Changed.
+func WithCache(c Cache, ttl time.Duration) Option {
+	return func(s *Settings) error {
+		if c == nil || ttl <= 0 {
+			return errInvalidOption
+		}
+		s.cache = c
+		s.cacheTTL = ttl
+		return nil
+	}
+}
+
+// cacheKey returns the URL with its query sorted and the API key removed.
+func cacheKey(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	q := u.Query()
+	q.Del("appid")
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// Cache records start with their type, followed by the Unix time in
+// nanoseconds they were fetched at and the response body.
+const (
+	recordBody     byte = 'b'
+	recordNotFound byte = 'n'
+
+	recordHeader = 9
+)
+
+// encodeRecord returns the cache record of a response body fetched at
+// the given time.
+func encodeRecord(kind byte, fetched time.Time, body []byte) []byte {
+	b := make([]byte, recordHeader+len(body))
+	b[0] = kind
+	binary.BigEndian.PutUint64(b[1:recordHeader], uint64(fetched.UnixNano()))
+	copy(b[recordHeader:], body)
+	return b
+}
+
+// decodeRecord splits a cache record. Values written by anything else are
+// reported as invalid.
+func decodeRecord(b []byte) (kind byte, fetched time.Time, body []byte, ok bool) {
+	if len(b) < recordHeader || (b[0] != recordBody && b[0] != recordNotFound) {
+		return 0, time.Time{}, nil, false
+	}
+	fetched = time.Unix(0, int64(binary.BigEndian.Uint64(b[1:recordHeader])))
+	return b[0], fetched, b[recordHeader:], true
+}
+
+// WithSoftTTL serves cached responses older than soft, but still within
+// the TTL given to WithCache, while a single background request per URL
+// refreshes them. Popular entries then don't all expire at once and send
+// a burst of requests to the API; only once the TTL passed does a request
+// wait for the API again. A soft TTL of at least the cache TTL has no
+// effect.
+func WithSoftTTL(soft time.Duration) Option {
+	return func(s *Settings) error {
+		if soft <= 0 {
+			return errInvalidOption
+		}
+		s.cacheSoftTTL = soft
+		return nil
+	}
+}
+
+// WithNegativeCache also caches 404 Not Found responses, e.g. for a
+// misspelled city, for ttl. They are stored as a distinct entry type and
+// served as the same *APIError without calling the API, so repeated
+// failing lookups don't use up the quota. The ttl is best kept short, a
+// few minutes, so newly added locations are found. It requires WithCache,
+// in any order; without it the client fails to build with
+// errInvalidOption.
+func WithNegativeCache(ttl time.Duration) Option {
+	return func(s *Settings) error {
+		if ttl <= 0 {
+			return errInvalidOption
+		}
+		s.notFoundTTL = ttl
+		return nil
+	}
+}
+
+// refreshes tracks the background refreshes of soft expired entries. It
+// is shared by every copy of the settings.
+type refreshes struct {
+	mu      sync.Mutex
+	running map[string]bool
+	wg      sync.WaitGroup
+}
+
+// start claims the refresh of the key, reporting false if one is already
+// running.
+func (r *refreshes) start(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running[key] {
+		return false
+	}
+	r.running[key] = true
+	r.wg.Add(1)
+	return true
+}
+
+// finish releases the refresh of the key.
+func (r *refreshes) finish(key string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.running, key)
+	r.wg.Done()
+}
+
+// cached returns the response stored for the URL, if any, starting a
+// refresh once it is soft expired.
+func (s *Settings) cached(e Endpoint, uri string) (*http.Response, bool) {
+	if s.cache == nil {
+		return nil, false
+	}
+	key := cacheKey(uri)
+	record, ok := s.cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	kind, fetched, body, ok := decodeRecord(record)
+	if !ok {
+		return nil, false
+	}
+	req, _ := http.NewRequest(http.MethodGet, uri, nil)
+	if kind == recordNotFound {
+		s.bus.publish(CacheHit{Endpoint: e, Key: key, NotFound: true})
+		return &http.Response{
+			Request:    req,
+			Status:     "404 Not Found",
+			StatusCode: http.StatusNotFound,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(bytes.NewReader(body)),
+		}, true
+	}
+
+	stale := s.cacheSoftTTL > 0 && s.cacheSoftTTL < s.cacheTTL && time.Since(fetched) >= s.cacheSoftTTL
+	if stale {
+		s.revalidate(e, uri)
+	}
+	s.bus.publish(CacheHit{Endpoint: e, Key: key, Stale: stale})
+	return &http.Response{
+		Request:    req,
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(bytes.NewReader(body)),
+	}, true
+}
+
+// revalidate refreshes the cached response for the URL in the background,
+// unless a refresh of it is already running. The refresh works on a copy
+// of the settings so it doesn't change the result being served, is bound
+// to the endpoint's timeout and recovers panics, e.g. of a subscriber or
+// Cache implementation. Failures are published as RefreshFailed.
+func (s *Settings) revalidate(e Endpoint, uri string) {
+	key := cacheKey(uri)
+	if s.refreshes == nil || !s.refreshes.start(key) {
+		return
+	}
+	r := *s
+	go func() {
+		defer r.refreshes.finish(key)
+		ctx, cancel := context.WithCancel(context.Background())
+		if d := r.timeout(e); d > 0 {
+			ctx, cancel = context.WithTimeout(context.Background(), d)
+		}
+		defer cancel()
+
+		err := safely(func() error {
+			response, err := checked(r.fetch(ctx, e, uri))
+			if err != nil {
+				return err
+			}
+			return response.Body.Close()
+		})
+		if err != nil {
+			safely(func() error {
+				r.bus.publish(RefreshFailed{Endpoint: e, Key: key, Err: err})
+				return nil
+			})
+		}
+	}()
+}
+
+// store caches the body of a successful response.
+func (s *Settings) store(uri string, body []byte) {
+	if s.cache != nil {
+		s.cache.Set(cacheKey(uri), encodeRecord(recordBody, time.Now(), body), s.cacheTTL)
+	}
+}
+
+// storeNotFound caches the body of a 404 response when negative caching
+// is enabled.
+func (s *Settings) storeNotFound(uri string, body []byte) {
+	if s.cache != nil && s.notFoundTTL > 0 {
+		s.cache.Set(cacheKey(uri), encodeRecord(recordNotFound, time.Now(), body), s.notFoundTTL)
+	}
+}
+
+// MemoryCache is an in-process Cache. Expired entries are dropped as the
+// cache grows.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	swept   int
+	now     func() time.Time
+}
+
+type cacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+// NewMemoryCache returns an empty in-memory cache.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]cacheEntry), now: time.Now}
+}
+
+// Get returns the body stored under key, if it hasn't expired.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(e.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.body, true
+}
+
+// Set stores the body under key for ttl.
+func (c *MemoryCache) Set(key string, body []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	c.entries[key] = cacheEntry{body: body, expires: now.Add(ttl)}
+	if len(c.entries) > 2*c.swept {
+		for k, e := range c.entries {
+			if !now.Before(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		c.swept = len(c.entries)
+	}
+}
@@ -0,0 +1,292 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestWithCache will verify repeated requests within the TTL are served
+// from the cache, whichever key they were made with.
+func TestWithCache(t *testing.T) {
+	calls := 0
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("q") == "Nowhere" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"cod":"404","message":"city not found"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"name":"London","main":{"temp":%d}}`, calls)
+	})
+	defer ts.Close()
+
+	now := time.Unix(0, 0)
+	cache := NewMemoryCache()
+	cache.now = func() time.Time { return now }
+	bus := NewBus()
+	var hits []CacheHit
+	bus.Subscribe(func(e Event) {
+		if h, ok := e.(CacheHit); ok {
+			hits = append(hits, h)
+		}
+	})
+
+	a, err := NewCurrent("C", "EN", "key-a", WithHttpClient(hc), WithCache(cache, 10*time.Minute), WithEventBus(bus))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewCurrent("C", "EN", "key-b", WithHttpClient(hc), WithCache(cache, 10*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.CurrentByName("London"); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.CurrentByName("London"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || a.Main.Temp != 1 {
+		t.Fatalf("expected the second lookup from the cache, got %d calls", calls)
+	}
+	if len(hits) != 1 || hits[0].Endpoint != EndpointCurrent {
+		t.Errorf("unexpected cache hits %+v", hits)
+	}
+
+	now = now.Add(10 * time.Minute)
+	if err := a.CurrentByName("London"); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 || a.Main.Temp != 2 {
+		t.Errorf("expected the entry to expire, got %d calls", calls)
+	}
+
+	a.CurrentByName("Nowhere")
+	a.CurrentByName("Nowhere")
+	if calls != 4 {
+		t.Errorf("expected failures not to be cached, got %d calls", calls)
+	}
+
+	if _, err := NewCurrent("C", "EN", "key", WithCache(cache, 0)); err != errInvalidOption {
+		t.Errorf("expected %v, got %v", errInvalidOption, err)
+	}
+}
+
+// TestMemoryCacheSweep will verify expired entries are dropped as the
+// cache grows.
+func TestMemoryCacheSweep(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := NewMemoryCache()
+	c.now = func() time.Time { return now }
+	for i := 0; i < 4; i++ {
+		c.Set(fmt.Sprint(i), nil, time.Minute)
+	}
+	now = now.Add(time.Minute)
+	for i := 4; i < 9; i++ {
+		c.Set(fmt.Sprint(i), nil, time.Minute)
+	}
+	if len(c.entries) != 5 {
+		t.Errorf("expected 5 live entries, got %d", len(c.entries))
+	}
+}
+
+// TestWithSoftTTL will verify soft expired entries are served while a
+// single background request refreshes them.
+func TestWithSoftTTL(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if n > 1 {
+			<-release
+		}
+		fmt.Fprintf(w, `{"name":"London","main":{"temp":%d}}`, n)
+	})
+	defer ts.Close()
+
+	cache := NewMemoryCache()
+	bus := NewBus()
+	var (
+		mu    sync.Mutex
+		stale int
+	)
+	bus.Subscribe(func(e Event) {
+		if h, ok := e.(CacheHit); ok && h.Stale {
+			mu.Lock()
+			stale++
+			mu.Unlock()
+		}
+	})
+	w, err := NewCurrent("C", "EN", "key", WithHttpClient(hc), WithCache(cache, 10*time.Minute), WithSoftTTL(time.Minute), WithEventBus(bus))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.CurrentByName("London"); err != nil {
+		t.Fatal(err)
+	}
+
+	// age the entry past the soft TTL
+	for key, e := range cache.entries {
+		_, _, body, _ := decodeRecord(e.body)
+		cache.Set(key, encodeRecord(recordBody, time.Now().Add(-2*time.Minute), body), 10*time.Minute)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := w.CurrentByName("London"); err != nil {
+			t.Fatal(err)
+		}
+		if w.Main.Temp != 1 {
+			t.Fatalf("expected the stale entry, got %v", w.Main.Temp)
+		}
+	}
+	close(release)
+	w.refreshes.wg.Wait()
+
+	if n := atomic.LoadInt32(&calls); n != 2 || stale != 3 {
+		t.Errorf("expected a single refresh for 3 stale hits, got %d calls and %d stale hits", n, stale)
+	}
+	if err := w.CurrentByName("London"); err != nil {
+		t.Fatal(err)
+	}
+	if w.Main.Temp != 2 || atomic.LoadInt32(&calls) != 2 {
+		t.Errorf("expected the refreshed entry, got %v", w.Main.Temp)
+	}
+
+	if _, err := NewCurrent("C", "EN", "key", WithSoftTTL(0)); err != errInvalidOption {
+		t.Errorf("expected %v, got %v", errInvalidOption, err)
+	}
+}
+
+// TestWithNegativeCache will verify not found responses are served from
+// the cache as the same error until their own TTL passed.
+func TestWithNegativeCache(t *testing.T) {
+	calls := 0
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("q") == "Lodnon" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"cod":"404","message":"city not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"name":"London"}`)
+	})
+	defer ts.Close()
+
+	now := time.Unix(0, 0)
+	cache := NewMemoryCache()
+	cache.now = func() time.Time { return now }
+	bus := NewBus()
+	var hits []CacheHit
+	bus.Subscribe(func(e Event) {
+		if h, ok := e.(CacheHit); ok {
+			hits = append(hits, h)
+		}
+	})
+
+	w, err := NewCurrent("C", "EN", "key", WithHttpClient(hc), WithCache(cache, 10*time.Minute), WithNegativeCache(time.Minute), WithEventBus(bus))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		err := w.CurrentByName("Lodnon")
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Message != "city not found" {
+			t.Fatalf("lookup %d: expected the not found error, got %v", i, err)
+		}
+	}
+	if calls != 1 || len(hits) != 1 || !hits[0].NotFound {
+		t.Errorf("expected the second lookup from the cache, got %d calls and hits %+v", calls, hits)
+	}
+
+	now = now.Add(time.Minute)
+	w.CurrentByName("Lodnon")
+	if calls != 2 {
+		t.Errorf("expected the negative entry to expire, got %d calls", calls)
+	}
+
+	if _, err := NewCurrent("C", "EN", "key", WithNegativeCache(time.Minute)); err != errInvalidOption {
+		t.Errorf("expected %v without a cache, got %v", errInvalidOption, err)
+	}
+	if _, err := NewCurrent("C", "EN", "key", WithNegativeCache(time.Minute), WithCache(NewMemoryCache(), time.Minute)); err != nil {
+		t.Errorf("expected the options in any order, got %v", err)
+	}
+	if _, err := NewCurrent("C", "EN", "key", WithNegativeCache(0)); err != errInvalidOption {
+		t.Errorf("expected %v, got %v", errInvalidOption, err)
+	}
+}
+
+// panickingCache is a MemoryCache whose Set panics once failing is set.
+type panickingCache struct {
+	*MemoryCache
+	failing bool
+}
+
+func (c *panickingCache) Set(key string, body []byte, ttl time.Duration) {
+	if c.failing {
+		panic("cache unavailable")
+	}
+	c.MemoryCache.Set(key, body, ttl)
+}
+
+// TestSoftTTLRefreshFailure will verify a failing background refresh is
+// recovered and published instead of crashing the program.
+func TestSoftTTLRefreshFailure(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"London","main":{"temp":1}}`)
+	})
+	defer ts.Close()
+
+	cache := &panickingCache{MemoryCache: NewMemoryCache()}
+	bus := NewBus()
+	failed := make(chan RefreshFailed, 1)
+	bus.Subscribe(func(e Event) {
+		if f, ok := e.(RefreshFailed); ok {
+			failed <- f
+		}
+	})
+	w, err := NewCurrent("C", "EN", "key", WithHttpClient(hc), WithCache(cache, 10*time.Minute), WithSoftTTL(time.Minute), WithEventBus(bus))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.CurrentByName("London"); err != nil {
+		t.Fatal(err)
+	}
+	for key, e := range cache.entries {
+		_, _, body, _ := decodeRecord(e.body)
+		cache.MemoryCache.Set(key, encodeRecord(recordBody, time.Now().Add(-2*time.Minute), body), 10*time.Minute)
+	}
+
+	cache.failing = true
+	if err := w.CurrentByName("London"); err != nil {
+		t.Fatal(err)
+	}
+	w.refreshes.wg.Wait()
+
+	select {
+	case f := <-failed:
+		var p *PanicError
+		if !errors.As(f.Err, &p) || f.Endpoint != EndpointCurrent {
+			t.Errorf("expected the recovered panic, got %+v", f)
+		}
+	default:
+		t.Error("expected the failed refresh to be published")
+	}
+}
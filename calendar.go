@@ -0,0 +1,147 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"strings"
+	"time"
+)
+
+// Calendar holds the weekly rhythm of a locale, used to turn selectors
+// like "tomorrow" or "this weekend" into time windows, e.g. for
+// BestSlots.
+type Calendar struct {
+	// WeekendDays lists the consecutive days off, in order.
+	WeekendDays []time.Weekday
+	FirstDay    time.Weekday
+}
+
+// DefaultCalendar has a Saturday and Sunday weekend and weeks starting on
+// Monday, following ISO 8601.
+var DefaultCalendar = Calendar{
+	WeekendDays: []time.Weekday{time.Saturday, time.Sunday},
+	FirstDay:    time.Monday,
+}
+
+var (
+	fridaySaturday = []time.Weekday{time.Friday, time.Saturday}
+	saturdaySunday = []time.Weekday{time.Saturday, time.Sunday}
+)
+
+// Calendars holds the calendars of countries departing from the default,
+// keyed by ISO 3166 country code.
+var Calendars = map[string]Calendar{
+	"US": {WeekendDays: saturdaySunday, FirstDay: time.Sunday},
+	"CA": {WeekendDays: saturdaySunday, FirstDay: time.Sunday},
+	"BR": {WeekendDays: saturdaySunday, FirstDay: time.Sunday},
+	"JP": {WeekendDays: saturdaySunday, FirstDay: time.Sunday},
+	"IN": {WeekendDays: []time.Weekday{time.Sunday}, FirstDay: time.Sunday},
+	"IL": {WeekendDays: fridaySaturday, FirstDay: time.Sunday},
+	"SA": {WeekendDays: fridaySaturday, FirstDay: time.Sunday},
+	"EG": {WeekendDays: fridaySaturday, FirstDay: time.Saturday},
+	"QA": {WeekendDays: fridaySaturday, FirstDay: time.Saturday},
+	"KW": {WeekendDays: fridaySaturday, FirstDay: time.Saturday},
+	"BH": {WeekendDays: fridaySaturday, FirstDay: time.Saturday},
+	"OM": {WeekendDays: fridaySaturday, FirstDay: time.Saturday},
+	"JO": {WeekendDays: fridaySaturday, FirstDay: time.Saturday},
+	"IQ": {WeekendDays: fridaySaturday, FirstDay: time.Saturday},
+	"DZ": {WeekendDays: fridaySaturday, FirstDay: time.Saturday},
+	"IR": {WeekendDays: []time.Weekday{time.Friday}, FirstDay: time.Saturday},
+	"AF": {WeekendDays: []time.Weekday{time.Thursday, time.Friday}, FirstDay: time.Saturday},
+}
+
+// CalendarFor returns the calendar of the country, or DefaultCalendar.
+func CalendarFor(country string) Calendar {
+	if c, ok := Calendars[strings.ToUpper(country)]; ok {
+		return c
+	}
+	return DefaultCalendar
+}
+
+// WithCalendar sets the calendar used by the client's time windows.
+func WithCalendar(c Calendar) Option {
+	return func(s *Settings) error {
+		if len(c.WeekendDays) == 0 {
+			return errInvalidOption
+		}
+		s.calendar = &c
+		return nil
+	}
+}
+
+// Calendar returns the calendar configured with WithCalendar, or
+// DefaultCalendar.
+func (s *Settings) Calendar() Calendar {
+	if s.calendar == nil {
+		return DefaultCalendar
+	}
+	return *s.calendar
+}
+
+// startOfDay returns the midnight starting the day of t in its location.
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// Today returns the window from midnight to midnight of the day of now,
+// in now's location.
+func (c Calendar) Today(now time.Time) (from, to time.Time) {
+	from = startOfDay(now)
+	return from, from.AddDate(0, 0, 1)
+}
+
+// Tomorrow returns the window of the day after now.
+func (c Calendar) Tomorrow(now time.Time) (from, to time.Time) {
+	return c.Today(now.AddDate(0, 0, 1))
+}
+
+// Week returns the window of the week now falls in, starting on the
+// calendar's first day.
+func (c Calendar) Week(now time.Time) (from, to time.Time) {
+	day := startOfDay(now)
+	offset := (int(day.Weekday()) - int(c.FirstDay) + 7) % 7
+	from = day.AddDate(0, 0, -offset)
+	return from, from.AddDate(0, 0, 7)
+}
+
+// Weekend returns the window of the weekend now falls in, starting on its
+// first day, or of the next one during the week.
+func (c Calendar) Weekend(now time.Time) (from, to time.Time) {
+	day := startOfDay(now)
+	if i := c.weekendIndex(day.Weekday()); i >= 0 {
+		from = day.AddDate(0, 0, -i)
+	} else {
+		for !c.IsWeekend(day) {
+			day = day.AddDate(0, 0, 1)
+		}
+		from = day
+	}
+	return from, from.AddDate(0, 0, len(c.WeekendDays))
+}
+
+// IsWeekend reports whether t falls on a weekend day.
+func (c Calendar) IsWeekend(t time.Time) bool {
+	return c.weekendIndex(t.Weekday()) >= 0
+}
+
+// weekendIndex returns the position of the day in the weekend, or -1.
+func (c Calendar) weekendIndex(d time.Weekday) int {
+	for i, w := range c.WeekendDays {
+		if w == d {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"testing"
+	"time"
+)
+
+// TestCalendarWindows will verify windows follow the locale's weekend and
+// first day of the week.
+func TestCalendarWindows(t *testing.T) {
+	riyadh := time.FixedZone("AST", 3*3600)
+	thursday := time.Date(2023, 11, 16, 21, 30, 0, 0, riyadh)
+	day := func(d int) time.Time { return time.Date(2023, 11, d, 0, 0, 0, 0, riyadh) }
+
+	tests := []struct {
+		name     string
+		window   func(time.Time) (time.Time, time.Time)
+		now      time.Time
+		from, to time.Time
+	}{
+		{"tomorrow", DefaultCalendar.Tomorrow, thursday, day(17), day(18)},
+		{"default weekend", DefaultCalendar.Weekend, thursday, day(18), day(20)},
+		{"saudi weekend", CalendarFor("sa").Weekend, thursday, day(17), day(19)},
+		{"saudi weekend on saturday", CalendarFor("SA").Weekend, day(18).Add(time.Hour), day(17), day(19)},
+		{"iranian weekend", CalendarFor("IR").Weekend, thursday, day(17), day(18)},
+		{"iso week", DefaultCalendar.Week, thursday, day(13), day(20)},
+		{"us week", CalendarFor("US").Week, thursday, day(12), day(19)},
+		{"egyptian week", CalendarFor("EG").Week, thursday, day(11), day(18)},
+	}
+	for _, tt := range tests {
+		from, to := tt.window(tt.now)
+		if !from.Equal(tt.from) || !to.Equal(tt.to) {
+			t.Errorf("%s: expected %v to %v, got %v to %v", tt.name, tt.from, tt.to, from, to)
+		}
+	}
+	if from, _ := DefaultCalendar.Today(thursday); from.Location() != riyadh {
+		t.Error("expected windows in the location of now")
+	}
+}
+
+// TestWithCalendar will verify the calendar is configurable on the client.
+func TestWithCalendar(t *testing.T) {
+	c, err := NewCurrent("C", "HE", "key", WithCalendar(CalendarFor("IL")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Calendar().WeekendDays[0] != time.Friday || c.Calendar().FirstDay != time.Sunday {
+		t.Errorf("unexpected calendar %+v", c.Calendar())
+	}
+	if d, _ := NewCurrent("C", "EN", "key"); d.Calendar().FirstDay != time.Monday {
+		t.Error("expected the default calendar")
+	}
+	if _, err := NewCurrent("C", "EN", "key", WithCalendar(Calendar{})); err != errInvalidOption {
+		t.Errorf("expected %v, got %v", errInvalidOption, err)
+	}
+}
@@ -0,0 +1,131 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// capTimeLayout is the CAP 1.2 date time format, which requires a numeric
+// zone offset.
+const capTimeLayout = "2006-01-02T15:04:05-07:00"
+
+// CAPAlert is a Common Alerting Protocol 1.2 alert message.
+type CAPAlert struct {
+	XMLName    xml.Name  `xml:"urn:oasis:names:tc:emergency:cap:1.2 alert"`
+	Identifier string    `xml:"identifier"`
+	Sender     string    `xml:"sender"`
+	Sent       string    `xml:"sent"`
+	Status     string    `xml:"status"`
+	MsgType    string    `xml:"msgType"`
+	Scope      string    `xml:"scope"`
+	Info       []CAPInfo `xml:"info"`
+}
+
+// CAPInfo describes the event of a CAP alert.
+type CAPInfo struct {
+	Category    string   `xml:"category"`
+	Event       string   `xml:"event"`
+	Urgency     string   `xml:"urgency"`
+	Severity    string   `xml:"severity"`
+	Certainty   string   `xml:"certainty"`
+	Onset       string   `xml:"onset"`
+	Expires     string   `xml:"expires"`
+	SenderName  string   `xml:"senderName,omitempty"`
+	Headline    string   `xml:"headline,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Parameters  []CAPKey `xml:"parameter,omitempty"`
+	Area        CAPArea  `xml:"area"`
+}
+
+// CAPKey is a CAP name and value pair.
+type CAPKey struct {
+	ValueName string `xml:"valueName"`
+	Value     string `xml:"value"`
+}
+
+// CAPArea is the area a CAP alert applies to.
+type CAPArea struct {
+	AreaDesc string `xml:"areaDesc"`
+	Circle   string `xml:"circle,omitempty"`
+}
+
+// NewCAPAlert converts the OWM alert issued for location into a CAP alert
+// from sender, which should identify the exporting system, e.g. a domain
+// name. Times are given in loc. OWM doesn't grade its alerts, so urgency,
+// severity and certainty are Unknown and its tags are kept as parameters.
+func NewCAPAlert(a OneCallAlertData, sender string, location Coordinates, areaDesc string, loc *time.Location, sent time.Time) CAPAlert {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%s|%d", a.SenderName, a.Event, a.Start)))
+	info := CAPInfo{
+		Category:    "Met",
+		Event:       a.Event,
+		Urgency:     "Unknown",
+		Severity:    "Unknown",
+		Certainty:   "Unknown",
+		Onset:       time.Unix(int64(a.Start), 0).In(loc).Format(capTimeLayout),
+		Expires:     time.Unix(int64(a.End), 0).In(loc).Format(capTimeLayout),
+		SenderName:  a.SenderName,
+		Headline:    a.Event,
+		Description: a.Description,
+		Area: CAPArea{
+			AreaDesc: areaDesc,
+			Circle: strconv.FormatFloat(location.Latitude, 'f', -1, 64) + "," +
+				strconv.FormatFloat(location.Longitude, 'f', -1, 64) + " 0",
+		},
+	}
+	for _, tag := range a.Tags {
+		info.Parameters = append(info.Parameters, CAPKey{ValueName: "tag", Value: tag})
+	}
+	return CAPAlert{
+		Identifier: hex.EncodeToString(sum[:16]),
+		Sender:     sender,
+		Sent:       sent.In(loc).Format(capTimeLayout),
+		Status:     "Actual",
+		MsgType:    "Alert",
+		Scope:      "Public",
+		Info:       []CAPInfo{info},
+	}
+}
+
+// XML returns the alert as an indented CAP document.
+func (c CAPAlert) XML() ([]byte, error) {
+	b, err := xml.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// CAPAlerts converts the alerts of the one call result into CAP alerts
+// from sender, timed in the location's zone.
+func (w *OneCallData) CAPAlerts(sender string, sent time.Time) []CAPAlert {
+	loc := time.FixedZone(w.Timezone, w.TimezoneOffset)
+	areaDesc := w.Timezone
+	if areaDesc == "" {
+		areaDesc = fmt.Sprintf("%g,%g", w.Latitude, w.Longitude)
+	}
+	location := Coordinates{Latitude: w.Latitude, Longitude: w.Longitude}
+
+	alerts := make([]CAPAlert, 0, len(w.Alerts))
+	for _, a := range w.Alerts {
+		alerts = append(alerts, NewCAPAlert(a, sender, location, areaDesc, loc, sent))
+	}
+	return alerts
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestCAPAlerts will verify alerts are exported as CAP 1.2 documents.
+func TestCAPAlerts(t *testing.T) {
+	w := &OneCallData{
+		Latitude:       33.45,
+		Longitude:      -112.07,
+		Timezone:       "America/Phoenix",
+		TimezoneOffset: -25200,
+		Alerts: []OneCallAlertData{{
+			SenderName:  "NWS Phoenix",
+			Event:       "Excessive Heat Warning",
+			Start:       1689519600,
+			End:         1689562800,
+			Description: "Dangerously hot conditions <118F> expected.",
+			Tags:        []string{"Extreme temperature value"},
+		}},
+	}
+
+	alerts := w.CAPAlerts("weather.example.com", time.Unix(1689500000, 0))
+	if len(alerts) != 1 {
+		t.Fatalf("expected 1 alert, got %d", len(alerts))
+	}
+	b, err := alerts[0].XML()
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := string(b)
+	for _, s := range []string{
+		`<alert xmlns="urn:oasis:names:tc:emergency:cap:1.2">`,
+		`<sent>2023-07-16T02:33:20-07:00</sent>`,
+		`<onset>2023-07-16T08:00:00-07:00</onset>`,
+		`<expires>2023-07-16T20:00:00-07:00</expires>`,
+		`<description>Dangerously hot conditions &lt;118F&gt; expected.</description>`,
+		`<circle>33.45,-112.07 0</circle>`,
+		`<value>Extreme temperature value</value>`,
+	} {
+		if !strings.Contains(doc, s) {
+			t.Errorf("expected %s in\n%s", s, doc)
+		}
+	}
+
+	var decoded CAPAlert
+	if err := xml.Unmarshal(b, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Identifier != alerts[0].Identifier || len(decoded.Identifier) != 32 || decoded.Info[0].Event != "Excessive Heat Warning" {
+		t.Errorf("unexpected round trip %+v", decoded)
+	}
+	if again := w.CAPAlerts("weather.example.com", time.Now()); again[0].Identifier != alerts[0].Identifier {
+		t.Error("expected the identifier to be stable across exports")
+	}
+}
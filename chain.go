@@ -0,0 +1,114 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// ChainLink is an encoded snapshot in a hash chain. Hash covers the
+// previous link's hash and the snapshot, so altering, removing or
+// reordering an archived snapshot breaks every later link.
+type ChainLink struct {
+	Snapshot []byte `json:"snapshot"`
+	Prev     string `json:"prev"`
+	Hash     string `json:"hash"`
+}
+
+// ChainError reports the first link of a chain that doesn't verify.
+type ChainError struct {
+	Index int
+}
+
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("snapshot chain broken at link %d", e.Index)
+}
+
+// Chain hash-chains snapshots for archives that need to be tamper evident,
+// e.g. weather evidence kept for insurance claims. Storing the head hash
+// apart from the archive, or publishing it, lets the whole archive be
+// verified later.
+type Chain struct {
+	Links []ChainLink
+}
+
+// linkHash returns the hash of a snapshot following the link hashed prev.
+func linkHash(prev string, snapshot []byte) string {
+	h := sha256.New()
+	h.Write([]byte(prev))
+	h.Write(snapshot)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Head returns the hash of the last link, or an empty string for an empty
+// chain.
+func (c *Chain) Head() string {
+	if len(c.Links) == 0 {
+		return ""
+	}
+	return c.Links[len(c.Links)-1].Hash
+}
+
+// Append encodes the snapshot and adds it to the chain.
+func (c *Chain) Append(s *Snapshot) (ChainLink, error) {
+	b, err := s.MarshalBinary()
+	if err != nil {
+		return ChainLink{}, err
+	}
+	prev := c.Head()
+	link := ChainLink{Snapshot: b, Prev: prev, Hash: linkHash(prev, b)}
+	c.Links = append(c.Links, link)
+	return link, nil
+}
+
+// Verify checks every link of the chain, returning a *ChainError for the
+// first one that was altered or is out of place.
+func (c *Chain) Verify() error {
+	prev := ""
+	for i, l := range c.Links {
+		if l.Prev != prev || l.Hash != linkHash(prev, l.Snapshot) {
+			return &ChainError{Index: i}
+		}
+		prev = l.Hash
+	}
+	return nil
+}
+
+// VerifyHead checks the chain and that it ends with head, as recorded
+// when it was archived, so links dropped from its end are detected too.
+func (c *Chain) VerifyHead(head string) error {
+	if err := c.Verify(); err != nil {
+		return err
+	}
+	if c.Head() != head {
+		return &ChainError{Index: len(c.Links)}
+	}
+	return nil
+}
+
+// Snapshots decodes the snapshots of the chain in order.
+func (c *Chain) Snapshots() ([]*Snapshot, error) {
+	snapshots := make([]*Snapshot, 0, len(c.Links))
+	for _, l := range c.Links {
+		s := new(Snapshot)
+		if err := s.UnmarshalBinary(l.Snapshot); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, nil
+}
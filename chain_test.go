@@ -0,0 +1,66 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// TestChain will verify an archived chain verifies until it's tampered
+// with.
+func TestChain(t *testing.T) {
+	var c Chain
+	for i, name := range []string{"oslo", "paris", "rome"} {
+		w := &CurrentWeatherData{ID: i, Dt: 1700000000 + i*600, Main: Main{Temp: float64(i)}}
+		if _, err := c.Append(w.Snapshot(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	head := c.Head()
+	if err := c.VerifyHead(head); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var archived Chain
+	if err := json.Unmarshal(b, &archived); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := archived.Snapshots(); err != nil || len(s) != 3 || s[1].Location != "paris" {
+		t.Fatalf("unexpected snapshots %v, %v", s, err)
+	}
+
+	tampered := archived
+	tampered.Links = append([]ChainLink(nil), archived.Links...)
+	var s Snapshot
+	s.UnmarshalBinary(tampered.Links[1].Snapshot)
+	s.Weather.Temp = 30
+	tampered.Links[1].Snapshot, _ = s.MarshalBinary()
+	if err, ok := tampered.Verify().(*ChainError); !ok || err.Index != 1 {
+		t.Errorf("expected the altered link to break the chain, got %v", err)
+	}
+
+	truncated := Chain{Links: archived.Links[:2]}
+	if err := truncated.Verify(); err != nil {
+		t.Errorf("expected a truncated chain to be consistent, got %v", err)
+	}
+	if err, ok := truncated.VerifyHead(head).(*ChainError); !ok || err.Index != 2 {
+		t.Errorf("expected the missing link to be detected, got %v", err)
+	}
+}
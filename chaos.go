@@ -0,0 +1,136 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ChaosTransport is an http.RoundTripper injecting faults into requests
+// made through Base at the configured rates, from 0 for never to 1 for
+// every request, so error handling and retries can be tested against
+// realistic failures:
+//
+//	chaos := owm.NewChaosTransport(nil, 1)
+//	chaos.ServerErrorRate = 0.2
+//	hc := &http.Client{Transport: chaos}
+//	w, err := owm.NewCurrent("C", "EN", apiKey, owm.WithHttpClient(hc))
+//
+// Injected server errors are returned without calling Base. Truncated and
+// malformed bodies replace the body of successful responses.
+type ChaosTransport struct {
+	Base http.RoundTripper // http.DefaultTransport if nil
+
+	// Latency is the longest delay added to a request at LatencyRate,
+	// each delay picked uniformly up to it.
+	Latency     time.Duration
+	LatencyRate float64
+
+	ServerErrorRate float64 // 503 responses
+	TruncateRate    float64 // bodies cut in half
+	MalformedRate   float64 // bodies that aren't valid JSON
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewChaosTransport returns a transport injecting no faults until rates
+// are set, drawing them from a source seeded with seed so failing runs
+// can be replayed.
+func NewChaosTransport(base http.RoundTripper, seed int64) *ChaosTransport {
+	return &ChaosTransport{Base: base, rand: rand.New(rand.NewSource(seed))}
+}
+
+// roll reports whether a fault at the given rate happens, and returns a
+// random fraction for sizing it.
+func (c *ChaosTransport) roll(rate float64) (bool, float64) {
+	if rate <= 0 {
+		return false, 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rand == nil {
+		c.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return c.rand.Float64() < rate, c.rand.Float64()
+}
+
+// RoundTrip implements http.RoundTripper.
+func (c *ChaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if ok, f := c.roll(c.LatencyRate); ok {
+		t := time.NewTimer(time.Duration(f * float64(c.Latency)))
+		select {
+		case <-t.C:
+		case <-req.Context().Done():
+			t.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+	if ok, _ := c.roll(c.ServerErrorRate); ok {
+		return chaosResponse(req, http.StatusServiceUnavailable, []byte(`{"cod":503,"message":"chaos: injected server error"}`)), nil
+	}
+
+	base := c.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	response, err := base.RoundTrip(req)
+	if err != nil || response.StatusCode != http.StatusOK {
+		return response, err
+	}
+
+	truncate, _ := c.roll(c.TruncateRate)
+	malformed, _ := c.roll(c.MalformedRate)
+	if !truncate && !malformed {
+		return response, nil
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case truncate:
+		body = body[:len(body)/2]
+	case malformed:
+		body = []byte(strings.Replace(string(body), ":", ";", -1) + "}")
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+	response.ContentLength = int64(len(body))
+	response.Header.Del("Content-Length")
+	return response, nil
+}
+
+// chaosResponse returns an injected JSON response to the request.
+func chaosResponse(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json; charset=utf-8"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
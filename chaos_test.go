@@ -0,0 +1,85 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestChaosTransport will verify each fault is injected at its rate.
+func TestChaosTransport(t *testing.T) {
+	calls := 0
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"id":1,"name":"Oslo","main":{"temp":3.5}}`)
+	})
+	defer ts.Close()
+
+	current := func(chaos *ChaosTransport, options ...Option) (*CurrentWeatherData, error) {
+		options = append(options, WithHttpClient(&http.Client{Transport: chaos}))
+		c, err := NewCurrent("C", "EN", "key", options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c, c.CurrentByID(1)
+	}
+
+	chaos := NewChaosTransport(hc.Transport, 1)
+	if c, err := current(chaos); err != nil || c.Name != "Oslo" {
+		t.Fatalf("expected no faults by default, got %v", err)
+	}
+
+	chaos.ServerErrorRate = 1
+	calls = 0
+	if _, err := current(chaos); !isAPIStatus(err, http.StatusServiceUnavailable) || calls != 0 {
+		t.Errorf("expected an injected 503 without a request, got %v after %d calls", err, calls)
+	}
+
+	chaos.ServerErrorRate, chaos.TruncateRate = 0, 1
+	if _, err := current(chaos); err == nil {
+		t.Error("expected a truncated body to fail decoding")
+	}
+	chaos.TruncateRate, chaos.MalformedRate = 0, 1
+	if _, err := current(chaos); err == nil {
+		t.Error("expected a malformed body to fail decoding")
+	}
+
+	chaos.MalformedRate, chaos.Latency, chaos.LatencyRate = 0, time.Hour, 1
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	c, _ := NewCurrent("C", "EN", "key", WithHttpClient(&http.Client{Transport: chaos}))
+	if err := c.CurrentByIDCtx(ctx, 1); err == nil {
+		t.Error("expected the injected latency to hit the deadline")
+	}
+
+	chaos = NewChaosTransport(hc.Transport, 1)
+	chaos.ServerErrorRate = 0.5
+	retry := RetryPolicy{MaxAttempts: 10, Jitter: func(time.Duration) time.Duration { return 0 }}
+	for i := 0; i < 20; i++ {
+		if _, err := current(chaos, WithRetry(retry)); err != nil {
+			t.Fatalf("expected retries to get past injected errors, got %v", err)
+		}
+	}
+}
+
+// isAPIStatus reports whether err is an *APIError with the status code.
+func isAPIStatus(err error, status int) bool {
+	e, ok := err.(*APIError)
+	return ok && e.StatusCode == status
+}
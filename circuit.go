@@ -0,0 +1,146 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var errCircuitOpen = errors.New("circuit breaker open")
+
+// CircuitBreaker stops sending requests once the API keeps failing. After
+// threshold consecutive failures the circuit opens and requests fail
+// right away with errCircuitOpen. Once the cooldown passed a single
+// trial request is sent: its success closes the circuit again, its
+// failure keeps it open for another cooldown. Network errors, 429 and 5xx
+// responses count as failures, like for retries. A CircuitBreaker may be
+// shared by several clients, through WithCircuitBreaker, and is safe for
+// concurrent use.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	open      bool
+	openedAt  time.Time
+	trial     bool
+	now       func() time.Time
+}
+
+// NewCircuitBreaker returns a closed breaker opening after threshold
+// consecutive failures for the cooldown.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// WithCircuitBreaker guards every request of the client, retries
+// included, with the breaker. The breaker publishes CircuitOpened and
+// CircuitClosed events on the client's bus.
+func WithCircuitBreaker(b *CircuitBreaker) Option {
+	return func(s *Settings) error {
+		if b == nil {
+			return errInvalidOption
+		}
+		s.breaker = b
+		return nil
+	}
+}
+
+// Open reports whether the circuit is open.
+func (b *CircuitBreaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.open
+}
+
+// allow reports whether a request may be sent, claiming the trial once
+// the cooldown of an open circuit passed.
+func (b *CircuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open {
+		return true
+	}
+	if b.trial || b.now().Sub(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+// record counts the outcome of a request and reports whether it opened
+// or closed the circuit. A failed trial opens it again.
+func (b *CircuitBreaker) record(failed bool) (opened, closed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+	if !failed {
+		closed = b.open
+		b.open = false
+		b.failures = 0
+		return false, closed
+	}
+	b.failures++
+	if b.open || b.failures >= b.threshold {
+		b.open = true
+		b.openedAt = b.now()
+		return true, false
+	}
+	return false, false
+}
+
+// abort gives back the trial of a request that was never completed,
+// e.g. because its context was canceled.
+func (b *CircuitBreaker) abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.trial = false
+}
+
+// guard fails fast while the circuit of the configured breaker, if any,
+// is open.
+func (s *Settings) guard() error {
+	if s.breaker == nil || s.breaker.allow() {
+		return nil
+	}
+	return errCircuitOpen
+}
+
+// settle records the outcome of an attempt with the configured breaker,
+// if any. Attempts ended by the caller's context don't count.
+func (s *Settings) settle(ctx context.Context, e Endpoint, response *http.Response, err error) {
+	if s.breaker == nil {
+		return
+	}
+	if err != nil && ctx.Err() != nil {
+		s.breaker.abort()
+		return
+	}
+	failed := err != nil || response.StatusCode == http.StatusTooManyRequests || response.StatusCode >= 500
+	opened, closed := s.breaker.record(failed)
+	switch {
+	case opened:
+		s.bus.publish(CircuitOpened{Endpoint: e, Cooldown: s.breaker.cooldown})
+	case closed:
+		s.bus.publish(CircuitClosed{Endpoint: e})
+	}
+}
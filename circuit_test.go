@@ -0,0 +1,104 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestCircuitBreaker will verify the circuit opens after consecutive
+// failures, fails fast during the cooldown and closes after a successful
+// trial.
+func TestCircuitBreaker(t *testing.T) {
+	calls, status := 0, http.StatusInternalServerError
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(status)
+		fmt.Fprint(w, `{"cod":500,"message":"internal error"}`)
+	})
+	defer ts.Close()
+
+	now := time.Unix(0, 0)
+	b := NewCircuitBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	var events []Event
+	bus := NewBus()
+	bus.Subscribe(func(e Event) {
+		switch e.(type) {
+		case CircuitOpened, CircuitClosed:
+			events = append(events, e)
+		}
+	})
+
+	c, err := NewCurrent("C", "EN", "key", WithHttpClient(hc), WithCircuitBreaker(b), WithEventBus(bus))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := c.CurrentByID(1); err == nil || errors.Is(err, errCircuitOpen) {
+			t.Fatalf("request %d: expected the API error, got %v", i, err)
+		}
+	}
+	if !b.Open() {
+		t.Fatal("expected the circuit to be open")
+	}
+	if err := c.CurrentByID(1); !errors.Is(err, errCircuitOpen) || calls != 2 {
+		t.Fatalf("expected to fail fast, got %v after %d calls", err, calls)
+	}
+
+	now = now.Add(time.Minute)
+	if err := c.CurrentByID(1); err == nil || calls != 3 {
+		t.Fatalf("expected a failed trial, got %v after %d calls", err, calls)
+	}
+	if err := c.CurrentByID(1); !errors.Is(err, errCircuitOpen) || calls != 3 {
+		t.Fatalf("expected the failed trial to reopen the circuit, got %v", err)
+	}
+
+	status = http.StatusOK
+	now = now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		if err := c.CurrentByID(1); err != nil {
+			t.Fatalf("request %d: expected the circuit to close, got %v", i, err)
+		}
+	}
+
+	expected := []Event{
+		CircuitOpened{Endpoint: EndpointCurrent, Cooldown: time.Minute},
+		CircuitOpened{Endpoint: EndpointCurrent, Cooldown: time.Minute},
+		CircuitClosed{Endpoint: EndpointCurrent},
+	}
+	if fmt.Sprint(events) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, events)
+	}
+}
+
+// TestCircuitBreakerSuccessResets will verify only consecutive failures
+// open the circuit.
+func TestCircuitBreakerSuccessResets(t *testing.T) {
+	b := NewCircuitBreaker(2, time.Minute)
+	for _, failed := range []bool{true, false, true} {
+		if opened, _ := b.record(failed); opened {
+			t.Fatal("expected the circuit to stay closed")
+		}
+	}
+	if opened, _ := b.record(true); !opened || b.allow() {
+		t.Error("expected the circuit to open after two failures in a row")
+	}
+}
@@ -0,0 +1,190 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"time"
+)
+
+var (
+	errClaimPeriod    = errors.New("report period ends before it starts")
+	errClaimSignature = errors.New("report signature doesn't match")
+)
+
+// ClaimObservation is the weather at one time of a claim report. The
+// history API returns standard units: temperatures in Kelvin and speeds in
+// meters per second.
+type ClaimObservation struct {
+	Time      time.Time `json:"time"`
+	Condition string    `json:"condition,omitempty"`
+	Temp      float64   `json:"temp"`
+	Humidity  int       `json:"humidity"`
+	Pressure  float64   `json:"pressure"`
+	WindSpeed float64   `json:"wind_speed"`
+	WindGust  float64   `json:"wind_gust,omitempty"`
+	Rain1h    float64   `json:"rain_1h,omitempty"`
+	Rain3h    float64   `json:"rain_3h,omitempty"`
+}
+
+// ClaimSummary holds the extremes of a claim report's period.
+type ClaimSummary struct {
+	MinTemp     float64 `json:"min_temp"`
+	MaxTemp     float64 `json:"max_temp"`
+	MaxWindGust float64 `json:"max_wind_gust"`
+	TotalRain   float64 `json:"total_rain"`
+}
+
+// ClaimReport documents the historical conditions at a location over a
+// period, e.g. to support an insurance claim. Once signed with Sign, any
+// change to the report invalidates its signature.
+type ClaimReport struct {
+	Location     Coordinates        `json:"location"`
+	Start        time.Time          `json:"start"`
+	End          time.Time          `json:"end"`
+	Generated    time.Time          `json:"generated"`
+	Unit         Unit               `json:"unit"`
+	Observations []ClaimObservation `json:"observations"`
+	Summary      ClaimSummary       `json:"summary"`
+	// Key and Signature are the hex encoded ed25519 public key that
+	// signed the report and its signature.
+	Key       string `json:"key,omitempty"`
+	Signature string `json:"signature,omitempty"`
+}
+
+// NewClaimReport builds an unsigned report of the history entries between
+// start and end, given in the unit system unit, such as ones archived
+// earlier.
+func NewClaimReport(location Coordinates, start, end time.Time, unit Unit, list []WeatherHistory) (*ClaimReport, error) {
+	if end.Before(start) {
+		return nil, errClaimPeriod
+	}
+	r := &ClaimReport{
+		Location:     location,
+		Start:        start.UTC(),
+		End:          end.UTC(),
+		Generated:    time.Now().UTC(),
+		Unit:         unit,
+		Observations: []ClaimObservation{},
+	}
+	for _, h := range list {
+		at := time.Unix(int64(h.Dt), 0).UTC()
+		if at.Before(r.Start) || at.After(r.End) {
+			continue
+		}
+		o := ClaimObservation{
+			Time:      at,
+			Temp:      h.Main.Temp,
+			Humidity:  h.Main.Humidity,
+			Pressure:  h.Main.Pressure,
+			WindSpeed: h.Wind.Speed,
+			WindGust:  h.Wind.Gust,
+			Rain1h:    h.Rain.OneH,
+			Rain3h:    h.Rain.ThreeH,
+		}
+		if c, ok := h.FirstCondition(); ok {
+			o.Condition = c.Description
+		}
+		r.Observations = append(r.Observations, o)
+	}
+	sort.Slice(r.Observations, func(i, j int) bool { return r.Observations[i].Time.Before(r.Observations[j].Time) })
+	r.summarize()
+	return r, nil
+}
+
+// summarize computes the summary from the observations.
+func (r *ClaimReport) summarize() {
+	if len(r.Observations) == 0 {
+		return
+	}
+	s := ClaimSummary{MinTemp: math.Inf(1), MaxTemp: math.Inf(-1)}
+	for _, o := range r.Observations {
+		s.MinTemp = math.Min(s.MinTemp, o.Temp)
+		s.MaxTemp = math.Max(s.MaxTemp, o.Temp)
+		s.MaxWindGust = math.Max(s.MaxWindGust, math.Max(o.WindGust, o.WindSpeed))
+		s.TotalRain += o.Rain1h
+	}
+	r.Summary = s
+}
+
+// payload returns the signed content of the report: its JSON encoding
+// without the signature.
+func (r *ClaimReport) payload() ([]byte, error) {
+	c := *r
+	c.Signature = ""
+	return json.Marshal(c)
+}
+
+// Sign signs the report with the private key, recording its public key
+// so the report can be checked with Verify.
+func (r *ClaimReport) Sign(key ed25519.PrivateKey) error {
+	r.Key = hex.EncodeToString(key.Public().(ed25519.PublicKey))
+	b, err := r.payload()
+	if err != nil {
+		return err
+	}
+	r.Signature = hex.EncodeToString(ed25519.Sign(key, b))
+	return nil
+}
+
+// Verify checks that the report was signed by the public key and hasn't
+// changed since.
+func (r *ClaimReport) Verify(key ed25519.PublicKey) error {
+	if r.Key != hex.EncodeToString(key) {
+		return errClaimSignature
+	}
+	sig, err := hex.DecodeString(r.Signature)
+	if err != nil {
+		return errClaimSignature
+	}
+	b, err := r.payload()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(key, b, sig) {
+		return errClaimSignature
+	}
+	return nil
+}
+
+// ClaimReport fetches the history at the location between start and end
+// and returns an unsigned report of it, timestamped with the clock skew
+// corrected time.
+func (h *HistoricalWeatherData) ClaimReport(location *Coordinates, start, end time.Time) (*ClaimReport, error) {
+	return h.ClaimReportCtx(context.Background(), location, start, end)
+}
+
+// ClaimReportCtx is like ClaimReport but the request is bound to ctx,
+// which cancels it or sets its deadline.
+func (h *HistoricalWeatherData) ClaimReportCtx(ctx context.Context, location *Coordinates, start, end time.Time) (*ClaimReport, error) {
+	if end.Before(start) {
+		return nil, errClaimPeriod
+	}
+	if err := h.HistoryByCoordCtx(ctx, location, &HistoricalParameters{Start: start.Unix(), End: end.Unix()}); err != nil {
+		return nil, err
+	}
+	r, err := NewClaimReport(*location, start, end, Standard, h.List)
+	if err != nil {
+		return nil, err
+	}
+	r.Generated = h.Now().UTC()
+	return r, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestClaimReport will verify the report covers the period and its
+// signature detects changes.
+func TestClaimReport(t *testing.T) {
+	var query string
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		fmt.Fprint(w, `{"cod":200,"cnt":3,"list":[
+			{"dt":1700003600,"main":{"temp":280.1,"humidity":90},"wind":{"speed":12,"gust":21.5},"rain":{"1h":4.2},"weather":[{"description":"heavy rain"}]},
+			{"dt":1700000000,"main":{"temp":281.4,"humidity":85},"wind":{"speed":8},"rain":{"1h":1.3}},
+			{"dt":1690000000,"main":{"temp":300}}]}`)
+	})
+	defer ts.Close()
+
+	h, err := NewHistorical("C", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start, end := time.Unix(1699999000, 0), time.Unix(1700010000, 0)
+	r, err := h.ClaimReport(&Coordinates{Latitude: 59.91, Longitude: 10.75}, start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query == "" || r.Unit != Standard || r.Generated.IsZero() {
+		t.Errorf("unexpected report %+v for %s", r, query)
+	}
+	if len(r.Observations) != 2 || r.Observations[0].Temp != 281.4 || r.Observations[1].Condition != "heavy rain" {
+		t.Errorf("unexpected observations %+v", r.Observations)
+	}
+	if s := r.Summary; s.MinTemp != 280.1 || s.MaxTemp != 281.4 || s.MaxWindGust != 21.5 || s.TotalRain != 5.5 {
+		t.Errorf("unexpected summary %+v", s)
+	}
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Sign(priv); err != nil {
+		t.Fatal(err)
+	}
+	b, _ := json.Marshal(r)
+	var received ClaimReport
+	if err := json.Unmarshal(b, &received); err != nil {
+		t.Fatal(err)
+	}
+	if err := received.Verify(pub); err != nil {
+		t.Errorf("expected the received report to verify, got %v", err)
+	}
+	received.Summary.TotalRain = 55
+	if err := received.Verify(pub); err != errClaimSignature {
+		t.Errorf("expected a changed report to fail verification, got %v", err)
+	}
+	other, _, _ := ed25519.GenerateKey(nil)
+	if err := r.Verify(other); err != errClaimSignature {
+		t.Errorf("expected another key to fail verification, got %v", err)
+	}
+
+	if _, err := h.ClaimReport(&Coordinates{}, end, start); err != errClaimPeriod {
+		t.Errorf("expected errClaimPeriod, got %v", err)
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"strings"
+)
+
+// Client holds the configuration shared by every endpoint: the unit,
+// language and API key along with the options, such as the http client,
+// rate limiter, cache and event bus. Each method returns a new result
+// ready to be queried, whose settings are a copy of the client's sharing
+// those. A Client is safe for concurrent use; its results are not, as
+// each holds the state of its last response. Query methods such as
+// CurrentByName return a fresh result per call, so they may be called
+// concurrently.
+type Client struct {
+	settings Settings
+	unit     Unit
+	lang     string
+	key      string
+}
+
+// NewClient returns a client for the API key. Results are in kelvin and
+// English unless set otherwise with WithUnit and WithLang.
+func NewClient(key string, options ...Option) (*Client, error) {
+	return newClient("K", "EN", key, options)
+}
+
+// newClient applies the options and validates the configuration, with
+// options taking precedence over the unit, language and key given.
+func newClient(unit, lang, key string, options []Option) (*Client, error) {
+	s := NewSettings()
+	if err := setOptions(s, options); err != nil {
+		return nil, err
+	}
+	unit, lang, key = s.configured(unit, lang, key)
+	unitChoice := strings.ToUpper(unit)
+	langChoice := strings.ToUpper(lang)
+
+	if !ValidDataUnit(unitChoice) {
+		return nil, errUnitUnavailable
+	}
+	if !ValidLangCode(langChoice) {
+		return nil, errLangUnavailable
+	}
+	k, err := setKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{settings: *s, unit: Unit(DataUnits[unitChoice]), lang: langChoice, key: k}, nil
+}
+
+// newSettings returns a copy of the client's settings for a new result.
+func (c *Client) newSettings() *Settings {
+	s := c.settings
+	return &s
+}
+
+// Current returns a new CurrentWeatherData.
+func (c *Client) Current() *CurrentWeatherData {
+	return &CurrentWeatherData{Unit: c.unit, Lang: c.lang, Key: c.key, Settings: c.newSettings()}
+}
+
+// CurrentGroup returns a new CurrentWeatherGroup.
+func (c *Client) CurrentGroup() *CurrentWeatherGroup {
+	return &CurrentWeatherGroup{Unit: c.unit, Lang: c.lang, Key: c.key, Settings: c.newSettings()}
+}
+
+// Forecast5 returns a new ForecastWeatherData for the 5 day, 3 hourly
+// forecast.
+func (c *Client) Forecast5() *ForecastWeatherData {
+	return &ForecastWeatherData{
+		Unit:                c.unit,
+		Lang:                c.lang,
+		Key:                 c.key,
+		baseURL:             forecast5Base,
+		Settings:            c.newSettings(),
+		ForecastWeatherJson: &Forecast5WeatherData{},
+	}
+}
+
+// Forecast16 returns a new ForecastWeatherData for the 16 day daily
+// forecast.
+func (c *Client) Forecast16() *ForecastWeatherData {
+	f := c.Forecast5()
+	f.baseURL = forecast16Base
+	f.ForecastWeatherJson = &Forecast16WeatherData{}
+	return f
+}
+
+// OneCall returns a new OneCallData leaving out the excluded parts of the
+// response.
+func (c *Client) OneCall(excludes ...string) (*OneCallData, error) {
+	e, err := ValidExcludes(excludes)
+	if err != nil {
+		return nil, err
+	}
+	return &OneCallData{Unit: c.unit, Lang: c.lang, Key: c.key, Excludes: e, Settings: c.newSettings()}, nil
+}
+
+// TimeMachine returns a new TimeMachineData.
+func (c *Client) TimeMachine() *TimeMachineData {
+	return &TimeMachineData{Unit: c.unit, Lang: c.lang, Key: c.key, Settings: c.newSettings()}
+}
+
+// History returns a new HistoricalWeatherData.
+func (c *Client) History() *HistoricalWeatherData {
+	return &HistoricalWeatherData{Unit: c.unit, Key: c.key, Settings: c.newSettings()}
+}
+
+// Pollution returns a new Pollution.
+func (c *Client) Pollution() *Pollution {
+	return &Pollution{Key: c.key, Settings: c.newSettings()}
+}
+
+// UV returns a new UV.
+func (c *Client) UV() *UV {
+	return &UV{Key: c.key, Settings: c.newSettings()}
+}
+
+// Geocoding returns a new Geocoding.
+func (c *Client) Geocoding() *Geocoding {
+	return &Geocoding{Key: c.key, Settings: c.newSettings()}
+}
+
+// Tiles returns a new Tiles.
+func (c *Client) Tiles() *Tiles {
+	return &Tiles{Key: c.key, Settings: c.newSettings()}
+}
+
+// CurrentByName returns the current weather at the location name.
+func (c *Client) CurrentByName(location string) (*CurrentWeatherData, error) {
+	return c.CurrentByNameCtx(context.Background(), location)
+}
+
+// CurrentByNameCtx is like CurrentByName but the request is bound to ctx,
+// which cancels it or sets its deadline.
+func (c *Client) CurrentByNameCtx(ctx context.Context, location string) (*CurrentWeatherData, error) {
+	w := c.Current()
+	if err := w.CurrentByNameCtx(ctx, location); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// CurrentByCoordinates returns the current weather at the coordinates.
+func (c *Client) CurrentByCoordinates(location *Coordinates) (*CurrentWeatherData, error) {
+	return c.CurrentByCoordinatesCtx(context.Background(), location)
+}
+
+// CurrentByCoordinatesCtx is like CurrentByCoordinates but the request is
+// bound to ctx, which cancels it or sets its deadline.
+func (c *Client) CurrentByCoordinatesCtx(ctx context.Context, location *Coordinates) (*CurrentWeatherData, error) {
+	w := c.Current()
+	if err := w.CurrentByCoordinatesCtx(ctx, location); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// CurrentByID returns the current weather of the city ID.
+func (c *Client) CurrentByID(id int) (*CurrentWeatherData, error) {
+	return c.CurrentByIDCtx(context.Background(), id)
+}
+
+// CurrentByIDCtx is like CurrentByID but the request is bound to ctx,
+// which cancels it or sets its deadline.
+func (c *Client) CurrentByIDCtx(ctx context.Context, id int) (*CurrentWeatherData, error) {
+	w := c.Current()
+	if err := w.CurrentByIDCtx(ctx, id); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// CurrentByZipcode returns the current weather at the zip code.
+func (c *Client) CurrentByZipcode(zip, countryCode string) (*CurrentWeatherData, error) {
+	return c.CurrentByZipcodeCtx(context.Background(), zip, countryCode)
+}
+
+// CurrentByZipcodeCtx is like CurrentByZipcode but the request is bound to
+// ctx, which cancels it or sets its deadline.
+func (c *Client) CurrentByZipcodeCtx(ctx context.Context, zip, countryCode string) (*CurrentWeatherData, error) {
+	w := c.Current()
+	if err := w.CurrentByZipcodeCtx(ctx, zip, countryCode); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// OneCallByCoordinates returns the one call data at the coordinates,
+// leaving out the excluded parts of the response.
+func (c *Client) OneCallByCoordinates(location *Coordinates, excludes ...string) (*OneCallData, error) {
+	return c.OneCallByCoordinatesCtx(context.Background(), location, excludes...)
+}
+
+// OneCallByCoordinatesCtx is like OneCallByCoordinates but the request is
+// bound to ctx, which cancels it or sets its deadline.
+func (c *Client) OneCallByCoordinatesCtx(ctx context.Context, location *Coordinates, excludes ...string) (*OneCallData, error) {
+	w, err := c.OneCall(excludes...)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.OneCallByCoordinatesCtx(ctx, location); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
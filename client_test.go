@@ -0,0 +1,119 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNewClient will verify the client's defaults and validation.
+func TestNewClient(t *testing.T) {
+	c, err := NewClient("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := c.Current(); w.Unit != Standard || w.Lang != "EN" || w.Key != "key" {
+		t.Errorf("unexpected defaults %s %s %s", w.Unit, w.Lang, w.Key)
+	}
+	c, err = NewClient("key", WithUnit("f"), WithLang("de"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := c.Forecast16(); f.Unit != Imperial || f.Lang != "DE" || f.baseURL != forecast16Base {
+		t.Errorf("unexpected forecast %+v", f)
+	}
+	if _, err := c.OneCall("bogus"); err != errExcludesUnavailable {
+		t.Errorf("expected %v, got %v", errExcludesUnavailable, err)
+	}
+	if _, err := NewClient(strings.Repeat("k", 65)); err != errInvalidKey {
+		t.Errorf("expected %v, got %v", errInvalidKey, err)
+	}
+}
+
+// TestClientSharesConfiguration will verify results of a client share its
+// http client, rate limiter and cache, but not their response state.
+func TestClientSharesConfiguration(t *testing.T) {
+	calls := 0
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprintf(w, `{"id":%s,"name":"Oslo"}`, r.URL.Query().Get("id"))
+	})
+	defer ts.Close()
+
+	limiter := NewRateLimiter(2, time.Hour)
+	c, err := NewClient("key", WithHttpClient(hc), WithRateLimiter(limiter, false), WithCache(NewMemoryCache(), time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, b := c.Current(), c.Current()
+	if a.Settings == b.Settings {
+		t.Fatal("expected each result to have its own settings")
+	}
+	if err := a.CurrentByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if b.Checksum() != "" {
+		t.Error("expected the checksum to be kept per result")
+	}
+	if err := b.CurrentByID(1); err != nil || b.ID != 1 || calls != 1 {
+		t.Errorf("expected the cached response, got %v after %d calls", err, calls)
+	}
+	if err := b.CurrentByID(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Forecast5().DailyByID(3, 1); err != errRateLimited {
+		t.Errorf("expected the shared quota to be used up, got %v", err)
+	}
+}
+
+// TestClientQueries will verify query methods return a new result or the
+// request's error.
+func TestClientQueries(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("q") == "Nowhere" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"cod":"404","message":"city not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"name":"Oslo","timezone_offset":3600}`)
+	})
+	defer ts.Close()
+
+	c, err := NewClient("key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := c.CurrentByName("Oslo")
+	if err != nil || a.Name != "Oslo" {
+		t.Fatalf("unexpected result %+v, %v", a, err)
+	}
+	b, err := c.CurrentByCoordinates(&Coordinates{Latitude: 59.91, Longitude: 10.75})
+	if err != nil || b == a || b.Settings == a.Settings {
+		t.Errorf("expected a new result, got %v", err)
+	}
+	if w, err := c.CurrentByName("Nowhere"); w != nil || err == nil {
+		t.Errorf("expected an error, got %+v", w)
+	}
+	if o, err := c.OneCallByCoordinates(&Coordinates{}, "minutely"); err != nil || o.TimezoneOffset != 3600 || o.Excludes != "minutely" {
+		t.Errorf("unexpected one call %+v, %v", o, err)
+	}
+	if _, err := c.OneCallByCoordinates(&Coordinates{}, "bogus"); err != errExcludesUnavailable {
+		t.Errorf("expected %v, got %v", errExcludesUnavailable, err)
+	}
+}
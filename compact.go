@@ -0,0 +1,158 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"math"
+)
+
+// CompactWeather is a fixed size, pointer free representation of current
+// weather for devices keeping thousands of snapshots in memory. Values are
+// stored as float32 and small integers, and the sunrise and sunset times
+// are packed as minute offsets from Dt. Strings such as the city name and
+// condition description aren't kept; the city ID and condition ID identify
+// them. The unit system is kept as a code so temperatures and speeds can
+// be told apart.
+type CompactWeather struct {
+	ID        int32
+	Dt        uint32
+	Lat       float32
+	Lon       float32
+	Temp      float32
+	FeelsLike float32
+	TempMin   float32
+	TempMax   float32
+	Pressure  float32
+	WindSpeed float32
+	WindGust  float32
+	Rain1h    float32
+	Snow1h    float32
+	// Sunrise and Sunset are minutes relative to Dt.
+	Sunrise int16
+	Sunset  int16
+	// Timezone is the UTC offset in minutes.
+	Timezone   int16
+	WindDeg    uint16
+	Condition  uint16
+	Visibility uint16
+	Humidity   uint8
+	Clouds     uint8
+	// Present holds the CompactSunrise and CompactSunset bits of the
+	// times that are set, as an offset of 0 is a valid time.
+	Present uint8
+	// Unit is the code of the unit system, 0 if unknown, 1 for Metric, 2
+	// for Imperial and 3 for Standard.
+	Unit uint8
+}
+
+// The bits of CompactWeather.Present.
+const (
+	CompactSunrise uint8 = 1 << iota
+	CompactSunset
+)
+
+// compactUnits holds the unit systems by their CompactWeather code.
+var compactUnits = []Unit{"", Metric, Imperial, Standard}
+
+// Compact converts the current weather to its compact form. Times are
+// rounded to the minute and values out of the compact range are clamped.
+func (w *CurrentWeatherData) Compact() CompactWeather {
+	c := CompactWeather{
+		ID:         int32(w.ID),
+		Dt:         uint32(w.Dt),
+		Lat:        float32(w.GeoPos.Latitude),
+		Lon:        float32(w.GeoPos.Longitude),
+		Temp:       float32(w.Main.Temp),
+		FeelsLike:  float32(w.Main.FeelsLike),
+		TempMin:    float32(w.Main.TempMin),
+		TempMax:    float32(w.Main.TempMax),
+		Pressure:   float32(w.Main.Pressure),
+		WindSpeed:  float32(w.Wind.Speed),
+		WindGust:   float32(w.Wind.Gust),
+		Rain1h:     float32(w.Rain.OneH),
+		Snow1h:     float32(w.Snow.OneH),
+		Timezone:   packMinutes(w.Timezone),
+		WindDeg:    uint16(clamp(w.Wind.Deg, 0, 360)),
+		Visibility: uint16(clamp(float64(w.Visibility), 0, math.MaxUint16)),
+		Humidity:   uint8(clampPercent(w.Main.Humidity)),
+		Clouds:     uint8(clampPercent(w.Clouds.All)),
+	}
+	if w.Sys.Sunrise != 0 {
+		c.Sunrise = packMinutes(w.Sys.Sunrise - w.Dt)
+		c.Present |= CompactSunrise
+	}
+	if w.Sys.Sunset != 0 {
+		c.Sunset = packMinutes(w.Sys.Sunset - w.Dt)
+		c.Present |= CompactSunset
+	}
+	if len(w.Weather) > 0 {
+		c.Condition = uint16(w.Weather[0].ID)
+	}
+	for i, u := range compactUnits {
+		if u != "" && u == w.Unit {
+			c.Unit = uint8(i)
+		}
+	}
+	return c
+}
+
+// Expand converts the compact form back to current weather data. The
+// result has no client settings, so it can't be used to make requests.
+// Compact forms without presence bits, e.g. from snapshots of version 1.0,
+// have a sunrise or sunset whenever its offset isn't 0.
+func (c CompactWeather) Expand() *CurrentWeatherData {
+	w := &CurrentWeatherData{
+		ID:         int(c.ID),
+		Dt:         int(c.Dt),
+		GeoPos:     Coordinates{Latitude: float64(c.Lat), Longitude: float64(c.Lon)},
+		Visibility: int(c.Visibility),
+		Timezone:   int(c.Timezone) * 60,
+		Main: Main{
+			Temp:      float64(c.Temp),
+			FeelsLike: float64(c.FeelsLike),
+			TempMin:   float64(c.TempMin),
+			TempMax:   float64(c.TempMax),
+			Pressure:  float64(c.Pressure),
+			Humidity:  int(c.Humidity),
+		},
+		Wind:   Wind{Speed: float64(c.WindSpeed), Deg: float64(c.WindDeg), Gust: float64(c.WindGust)},
+		Clouds: Clouds{All: int(c.Clouds)},
+		Rain:   Rain{OneH: float64(c.Rain1h)},
+		Snow:   Snow{OneH: float64(c.Snow1h)},
+	}
+	if c.Present&CompactSunrise != 0 || c.Sunrise != 0 {
+		w.Sys.Sunrise = w.Dt + int(c.Sunrise)*60
+	}
+	if c.Present&CompactSunset != 0 || c.Sunset != 0 {
+		w.Sys.Sunset = w.Dt + int(c.Sunset)*60
+	}
+	if int(c.Unit) < len(compactUnits) {
+		w.Unit = compactUnits[c.Unit]
+	}
+	if c.Condition != 0 {
+		w.Weather = []Weather{{ID: int(c.Condition)}}
+	}
+	return w
+}
+
+// packMinutes converts seconds to whole minutes fitting an int16.
+func packMinutes(seconds int) int16 {
+	return int16(clamp(math.Round(float64(seconds)/60), math.MinInt16, math.MaxInt16))
+}
+
+// clamp limits v to the range from min to max.
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
@@ -0,0 +1,75 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"math"
+	"testing"
+	"unsafe"
+)
+
+// TestCompact will verify the compact form round trips within float32
+// precision and minute resolution.
+func TestCompact(t *testing.T) {
+	w := &CurrentWeatherData{
+		ID:         2643743,
+		Dt:         1700000000,
+		GeoPos:     Coordinates{Latitude: 51.5085, Longitude: -0.1257},
+		Main:       Main{Temp: 11.37, FeelsLike: 10.52, Pressure: 1012, Humidity: 82},
+		Wind:       Wind{Speed: 4.12, Deg: 250},
+		Clouds:     Clouds{All: 75},
+		Rain:       Rain{OneH: 0.21},
+		Sys:        Sys{Sunrise: 1699989020, Sunset: 1700021150},
+		Weather:    []Weather{{ID: 500, Main: "Rain"}},
+		Visibility: 10000,
+		Timezone:   3600,
+	}
+
+	c := w.Compact()
+	if size := unsafe.Sizeof(c); size > 72 {
+		t.Errorf("expected a compact size of at most 72 bytes, got %d", size)
+	}
+
+	got := c.Expand()
+	if got.ID != w.ID || got.Dt != w.Dt || got.Main.Humidity != 82 || got.Visibility != 10000 || got.Timezone != 3600 {
+		t.Errorf("unexpected integer fields %+v", got)
+	}
+	if math.Abs(got.Main.Temp-w.Main.Temp) > 1e-5 || math.Abs(got.Rain.OneH-w.Rain.OneH) > 1e-6 {
+		t.Errorf("unexpected float fields %+v", got.Main)
+	}
+	if got.Sys.Sunrise != w.Sys.Sunrise || got.Sys.Sunset != 1700021180 {
+		t.Errorf("expected times rounded to whole minutes from Dt, got %d and %d", got.Sys.Sunrise, got.Sys.Sunset)
+	}
+	if len(got.Weather) != 1 || got.Weather[0].ID != 500 {
+		t.Errorf("unexpected condition %+v", got.Weather)
+	}
+
+	if c := (&CurrentWeatherData{Main: Main{Humidity: 140}, Visibility: 100000}).Compact(); c.Humidity != 100 || c.Visibility != math.MaxUint16 {
+		t.Errorf("expected out of range values clamped, got %+v", c)
+	}
+
+	// A sunset within half a minute of Dt packs to an offset of 0.
+	w = &CurrentWeatherData{Dt: 1700000000, Sys: Sys{Sunset: 1700000020}, Unit: Imperial}
+	got = w.Compact().Expand()
+	if got.Sys.Sunset != 1700000000 || got.Sys.Sunrise != 0 {
+		t.Errorf("expected the sunset kept at Dt and no sunrise, got %d and %d", got.Sys.Sunset, got.Sys.Sunrise)
+	}
+	if got.Unit != Imperial {
+		t.Errorf("expected %s, got %q", Imperial, got.Unit)
+	}
+	if got := (CompactWeather{Dt: 1700000000, Sunrise: -300}).Expand(); got.Sys.Sunrise != 1700000000-300*60 || got.Unit != "" {
+		t.Errorf("expected a compact form without presence bits to keep its sunrise, got %+v", got.Sys)
+	}
+}
@@ -15,6 +15,7 @@
 package openweathermap
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -36,14 +37,31 @@ type ConditionData struct {
 	Icon2   string
 }
 
-// RetrieveIcon will get the specified icon from the API.
-func RetrieveIcon(destination, iconFile string) (int64, error) {
+// RetrieveIcon will get the specified icon from the API. Options such as
+// WithHttpClient configure the download.
+func RetrieveIcon(destination, iconFile string, options ...Option) (int64, error) {
+	return RetrieveIconCtx(context.Background(), destination, iconFile, options...)
+}
+
+// RetrieveIconCtx is like RetrieveIcon but the download is bound to ctx,
+// which cancels it or sets its deadline.
+func RetrieveIconCtx(ctx context.Context, destination, iconFile string, options ...Option) (int64, error) {
+	s := NewSettings()
+	if err := setOptions(s, options); err != nil {
+		return 0, err
+	}
+
 	fullFilePath := fmt.Sprintf("%s/%s", destination, iconFile)
 
 	// Check to see if we've already gotten that icon file.  If so, use it
 	// rather than getting it again.
 	if _, err := os.Stat(fullFilePath); err != nil {
-		response, err := http.Get(fmt.Sprintf(iconURL, iconFile))
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(iconURL, iconFile), nil)
+		if err != nil {
+			return 0, err
+		}
+		s.rebase(req.URL)
+		response, err := checked(s.client.Do(req))
 		if err != nil {
 			return 0, err
 		}
@@ -16,7 +16,10 @@ package openweathermap
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -46,3 +49,34 @@ func TestRetrieveIcon(t *testing.T) {
 		}
 	}
 }
+
+// TestRetrieveIconWithHttpClient will verify the icon is downloaded with
+// the supplied client.
+func TestRetrieveIconWithHttpClient(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "png")
+	})
+	defer ts.Close()
+
+	dir, err := ioutil.TempDir("", "icons")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	size, err := RetrieveIcon(dir, "01d.png", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(dir, "01d.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 3 || string(b) != "png" {
+		t.Errorf("expected the served icon, got %d bytes %q", size, b)
+	}
+
+	if _, err := RetrieveIcon(dir, "02d.png", WithHttpClient(nil)); err != errInvalidHttpClient {
+		t.Errorf("expected errInvalidHttpClient, got %v", err)
+	}
+}
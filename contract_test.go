@@ -0,0 +1,161 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+// contracts pairs each fixture in testdata with the type it decodes into
+// and the live call returning the same kind of payload. Unmodeled lists
+// the fixture fields the type deliberately leaves out.
+var contracts = []struct {
+	fixture   string
+	target    func() interface{}
+	live      func(key string, hc *http.Client) error
+	unmodeled []string
+}{
+	{
+		fixture: "current.json",
+		target:  func() interface{} { return &CurrentWeatherData{} },
+		live: func(key string, hc *http.Client) error {
+			w, err := NewCurrent("C", "EN", key, WithHttpClient(hc))
+			if err != nil {
+				return err
+			}
+			return w.CurrentByID(2643743)
+		},
+	},
+	{
+		fixture: "forecast5.json",
+		target:  func() interface{} { return &Forecast5WeatherData{} },
+		live: func(key string, hc *http.Client) error {
+			f, err := NewForecast("5", "C", "EN", key, WithHttpClient(hc))
+			if err != nil {
+				return err
+			}
+			return f.DailyByID(2643743, 2)
+		},
+		unmodeled: []string{"cod", "message"},
+	},
+	{
+		// the 16 day forecast needs a paid plan so it's only checked offline
+		fixture: "forecast16.json",
+		target:  func() interface{} { return &Forecast16WeatherData{} },
+	},
+	{
+		fixture: "pollution.json",
+		target:  func() interface{} { return &Pollution{} },
+		live: func(key string, hc *http.Client) error {
+			p, err := NewPollution(key, WithHttpClient(hc))
+			if err != nil {
+				return err
+			}
+			return p.PollutionByParams(&PollutionParameters{Location: *liveCoordinates, Datetime: "current"})
+		},
+	},
+}
+
+// readFixture loads a payload from testdata.
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// pathDiff returns the sorted paths in a that aren't in b.
+func pathDiff(a, b map[string]bool) []string {
+	var diff []string
+	for p := range a {
+		if !b[p] {
+			diff = append(diff, p)
+		}
+	}
+	sort.Strings(diff)
+	return diff
+}
+
+// TestFixturesDecode will verify every fixture decodes into its type
+// without losing fields.
+func TestFixturesDecode(t *testing.T) {
+	for _, c := range contracts {
+		raw, v := readFixture(t, c.fixture), c.target()
+		if err := json.Unmarshal(raw, v); err != nil {
+			t.Errorf("%s: %v", c.fixture, err)
+			continue
+		}
+
+		dropped, err := droppedFields(raw, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		unmodeled := make(map[string]bool, len(c.unmodeled))
+		for _, p := range c.unmodeled {
+			unmodeled[p] = true
+		}
+		for _, p := range dropped {
+			if !unmodeled[p] {
+				t.Errorf("%s: field %s isn't modeled by %T", c.fixture, p, v)
+			}
+		}
+	}
+}
+
+// TestContract fetches live responses and structurally compares them with
+// the fixtures. Fields OWM added fail the test so the fixtures and models
+// get updated; fields missing from the live response are only logged as
+// many blocks, like rain, are optional.
+func TestContract(t *testing.T) {
+	key := liveKey(t)
+
+	for _, c := range contracts {
+		if c.live == nil {
+			continue
+		}
+		hc, rt := newLiveClient()
+		if err := c.live(key, hc); err != nil {
+			t.Errorf("%s: %v", c.fixture, err)
+			continue
+		}
+
+		var fixture, live interface{}
+		if err := json.Unmarshal(readFixture(t, c.fixture), &fixture); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(rt.last, &live); err != nil {
+			t.Errorf("%s: %v", c.fixture, err)
+			continue
+		}
+
+		fixturePaths, livePaths := make(map[string]bool), make(map[string]bool)
+		jsonPaths(fixture, "", fixturePaths)
+		jsonPaths(live, "", livePaths)
+
+		if added := pathDiff(livePaths, fixturePaths); len(added) > 0 {
+			t.Errorf("%s: live response has fields missing from the fixture: %v", c.fixture, added)
+		}
+		if missing := pathDiff(fixturePaths, livePaths); len(missing) > 0 {
+			t.Logf("%s: fixture fields absent from the live response: %v", c.fixture, missing)
+		}
+	}
+}
@@ -15,83 +15,101 @@
 package openweathermap
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"io"
+	"io/ioutil"
 	"net/url"
-	"strings"
 )
 
 // CurrentWeatherData struct contains an aggregate view of the structs
 // defined above for JSON to be unmarshaled into.
 type CurrentWeatherData struct {
-	GeoPos   Coordinates `json:"coord"`
-	Sys      Sys         `json:"sys"`
-	Base     string      `json:"base"`
-	Weather  []Weather   `json:"weather"`
-	Main     Main        `json:"main"`
-	Visibility int       `json:"visibility"`
-	Wind     Wind        `json:"wind"`
-	Clouds   Clouds      `json:"clouds"`
-	Rain     Rain        `json:"rain"`
-	Snow     Snow        `json:"snow"`
-	Dt       int         `json:"dt"`
-	ID       int         `json:"id"`
-	Name     string      `json:"name"`
-	Cod      int         `json:"cod"`
-	Timezone int         `json:"timezone"`
-	Unit     string
-	Lang     string
-	Key      string
+	GeoPos     Coordinates    `json:"coord"`
+	Sys        Sys            `json:"sys"`
+	Base       string         `json:"base"`
+	Weather    []Weather      `json:"weather"`
+	Main       Main           `json:"main"`
+	Visibility int            `json:"visibility"`
+	Wind       Wind           `json:"wind"`
+	Clouds     Clouds         `json:"clouds"`
+	Rain       Rain           `json:"rain"`
+	Snow       Snow           `json:"snow"`
+	Dt         int            `json:"dt"`
+	ID         int            `json:"id"`
+	Name       string         `json:"name"`
+	Cod        int            `json:"cod"`
+	Timezone   int            `json:"timezone"`
+	Precise    *PreciseValues `json:"-"`
+	HTML       string         `json:"-"` // the page requested with ModeHTML
+	Unit       Unit
+	Lang       string
+	Key        string
+
+	// DisplayName is the name of the place at the coordinates of a
+	// coordinate query made with WithDisplayNames.
+	DisplayName string `json:"-"`
 	*Settings
 }
 
 // NewCurrent returns a new CurrentWeatherData pointer with the supplied parameters
 func NewCurrent(unit, lang, key string, options ...Option) (*CurrentWeatherData, error) {
-	unitChoice := strings.ToUpper(unit)
-	langChoice := strings.ToUpper(lang)
-
-	c := &CurrentWeatherData{
-		Settings: NewSettings(),
-	}
-
-	if ValidDataUnit(unitChoice) {
-		c.Unit = DataUnits[unitChoice]
-	} else {
-		return nil, errUnitUnavailable
-	}
-
-	if ValidLangCode(langChoice) {
-		c.Lang = langChoice
-	} else {
-		return nil, errLangUnavailable
-	}
-	var err error
-	c.Key, err = setKey(key)
+	c, err := newClient(unit, lang, key, options)
 	if err != nil {
 		return nil, err
 	}
+	return c.Current(), nil
+}
 
-	if err := setOptions(c.Settings, options); err != nil {
-		return nil, err
+// decode unmarshals the response body into w. When precise numbers were
+// requested the exact pressure and precipitation values are kept too.
+// Responses in another mode than JSON are decoded according to it.
+func (w *CurrentWeatherData) decode(r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	switch w.mode {
+	case ModeXML:
+		if err := w.decodeXML(b); err != nil {
+			return err
+		}
+		return w.postDecode(w)
+	case ModeHTML:
+		w.HTML = string(b)
+		return w.postDecode(w)
+	}
+	if err := json.Unmarshal(b, &w); err != nil {
+		return err
 	}
-	return c, nil
+	if w.precise {
+		w.Precise = &PreciseValues{}
+		if err := json.Unmarshal(b, w.Precise); err != nil {
+			return err
+		}
+	}
+	return w.postDecode(w)
 }
 
 // CurrentByName will provide the current weather with the provided
 // location name.
 func (w *CurrentWeatherData) CurrentByName(location string) error {
-	response, err := w.client.Get(fmt.Sprintf(fmt.Sprintf(baseURL, "appid=%s&q=%s&units=%s&lang=%s"), w.Key, url.QueryEscape(location), w.Unit, w.Lang))
+	return w.CurrentByNameCtx(context.Background(), location)
+}
+
+// CurrentByNameCtx is like CurrentByName but the request is bound to
+// ctx, which cancels it or sets its deadline.
+func (w *CurrentWeatherData) CurrentByNameCtx(ctx context.Context, location string) error {
+	response, err := w.getByName(ctx, EndpointCurrent, location, func(location string) string {
+		return w.withMode(fmt.Sprintf(fmt.Sprintf(baseURL, "appid=%s&q=%s&units=%s&lang=%s"), w.Key, url.QueryEscape(location), w.Unit, w.Lang))
+	})
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode == http.StatusUnauthorized {
-		return errInvalidKey
-	}
-
-	if err := json.NewDecoder(response.Body).Decode(&w); err != nil {
+	if err := w.decode(response.Body); err != nil {
 		return err
 	}
 
@@ -101,17 +119,20 @@ func (w *CurrentWeatherData) CurrentByName(location string) error {
 // CurrentByCoordinates will provide the current weather with the
 // provided location coordinates.
 func (w *CurrentWeatherData) CurrentByCoordinates(location *Coordinates) error {
-	response, err := w.client.Get(fmt.Sprintf(fmt.Sprintf(baseURL, "appid=%s&lat=%f&lon=%f&units=%s&lang=%s"), w.Key, location.Latitude, location.Longitude, w.Unit, w.Lang))
+	return w.CurrentByCoordinatesCtx(context.Background(), location)
+}
+
+// CurrentByCoordinatesCtx is like CurrentByCoordinates but the request
+// is bound to ctx, which cancels it or sets its deadline.
+func (w *CurrentWeatherData) CurrentByCoordinatesCtx(ctx context.Context, location *Coordinates) error {
+	response, err := w.get(ctx, EndpointCurrent, w.withMode(fmt.Sprintf(fmt.Sprintf(baseURL, "appid=%s&lat=%f&lon=%f&units=%s&lang=%s"), w.Key, location.Latitude, location.Longitude, w.Unit, w.Lang)))
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode == http.StatusUnauthorized {
-		return errInvalidKey
-	}
-
-	if err = json.NewDecoder(response.Body).Decode(&w); err != nil {
+	w.DisplayName = w.displayName(ctx, w.Key, w.Lang, location)
+	if err = w.decode(response.Body); err != nil {
 		return err
 	}
 
@@ -121,17 +142,19 @@ func (w *CurrentWeatherData) CurrentByCoordinates(location *Coordinates) error {
 // CurrentByID will provide the current weather with the
 // provided location ID.
 func (w *CurrentWeatherData) CurrentByID(id int) error {
-	response, err := w.client.Get(fmt.Sprintf(fmt.Sprintf(baseURL, "appid=%s&id=%d&units=%s&lang=%s"), w.Key, id, w.Unit, w.Lang))
+	return w.CurrentByIDCtx(context.Background(), id)
+}
+
+// CurrentByIDCtx is like CurrentByID but the request is bound to ctx,
+// which cancels it or sets its deadline.
+func (w *CurrentWeatherData) CurrentByIDCtx(ctx context.Context, id int) error {
+	response, err := w.get(ctx, EndpointCurrent, w.withMode(fmt.Sprintf(fmt.Sprintf(baseURL, "appid=%s&id=%d&units=%s&lang=%s"), w.Key, id, w.Unit, w.Lang)))
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode == http.StatusUnauthorized {
-		return errInvalidKey
-	}
-
-	if err = json.NewDecoder(response.Body).Decode(&w); err != nil {
+	if err = w.decode(response.Body); err != nil {
 		return err
 	}
 
@@ -143,31 +166,50 @@ func (w *CurrentWeatherData) CurrentByID(id int) error {
 //
 // Deprecated: Use CurrentByZipcode instead.
 func (w *CurrentWeatherData) CurrentByZip(zip int, countryCode string) error {
-	response, err := w.client.Get(fmt.Sprintf(fmt.Sprintf(baseURL, "appid=%s&zip=%05d,%s&units=%s&lang=%s"), w.Key, zip, countryCode, w.Unit, w.Lang))
+	return w.CurrentByZipCtx(context.Background(), zip, countryCode)
+}
+
+// CurrentByZipCtx is like CurrentByZip but the request is bound to ctx,
+// which cancels it or sets its deadline.
+//
+// Deprecated: Use CurrentByZipcodeCtx instead.
+func (w *CurrentWeatherData) CurrentByZipCtx(ctx context.Context, zip int, countryCode string) error {
+	if !ValidLocation(countryCode) {
+		return errInvalidLocation
+	}
+	response, err := w.get(ctx, EndpointCurrent, w.withMode(fmt.Sprintf(fmt.Sprintf(baseURL, "appid=%s&zip=%05d,%s&units=%s&lang=%s"), w.Key, zip, url.QueryEscape(countryCode), w.Unit, w.Lang)))
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	return json.NewDecoder(response.Body).Decode(&w)
+	return w.decode(response.Body)
 }
 
 // CurrentByZipcode will provide the current weather for the
 // provided zip code.
 func (w *CurrentWeatherData) CurrentByZipcode(zip string, countryCode string) error {
-	response, err := w.client.Get(fmt.Sprintf(fmt.Sprintf(baseURL, "appid=%s&zip=%s,%s&units=%s&lang=%s"), w.Key, zip, countryCode, w.Unit, w.Lang))
+	return w.CurrentByZipcodeCtx(context.Background(), zip, countryCode)
+}
+
+// CurrentByZipcodeCtx is like CurrentByZipcode but the request is bound
+// to ctx, which cancels it or sets its deadline.
+func (w *CurrentWeatherData) CurrentByZipcodeCtx(ctx context.Context, zip string, countryCode string) error {
+	if !ValidLocation(zip) || !ValidLocation(countryCode) {
+		return errInvalidLocation
+	}
+	response, err := w.get(ctx, EndpointCurrent, w.withMode(fmt.Sprintf(fmt.Sprintf(baseURL, "appid=%s&zip=%s,%s&units=%s&lang=%s"), w.Key, url.QueryEscape(zip), url.QueryEscape(countryCode), w.Unit, w.Lang)))
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode == http.StatusUnauthorized {
-		return errInvalidKey
-	}
-
-	return json.NewDecoder(response.Body).Decode(&w)
+	return w.decode(response.Body)
 }
 
 // CurrentByArea will provide the current weather for the
 // provided area.
+//
+// Deprecated: an area holds several cities, use
+// CurrentWeatherGroup.CurrentByBox instead.
 func (w *CurrentWeatherData) CurrentByArea() {}
@@ -1,9 +1,13 @@
 package openweathermap
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
+	"io"
+	"io/ioutil"
+	"net/url"
 	"strconv"
 	"strings"
 )
@@ -12,13 +16,33 @@ import (
 // used in CurrentByIDs
 const maxCityIDs = 20
 
+// maximum count of cities returned by CurrentByCircle
+const maxCircleCities = 50
+
+var (
+	errCityMissing = errors.New("city missing from group response")
+	errInvalidBox  = errors.New("invalid bounding box")
+	errCircleCount = errors.New("count of cities should be between 1 and 50")
+)
+
+// Search types supported by SearchByName. SearchLike matches every city
+// whose name contains the query while SearchAccurate only returns exact
+// matches.
+const (
+	SearchLike     = "like"
+	SearchAccurate = "accurate"
+)
+
 // CurrentWeatherGroup struct contains list of the CurrentWeatherData
 // structs for JSON to be unmarshaled into.
 type CurrentWeatherGroup struct {
 	Count int                   `json:"count"`
 	List  []*CurrentWeatherData `json:"list,omitempty"`
+	// Failed holds the error of every city requested by CurrentByIDs
+	// the response didn't include, e.g. because its ID is unknown.
+	Failed map[int]error `json:"-"`
 
-	Unit string
+	Unit Unit
 	Lang string
 	Key  string
 
@@ -27,39 +51,24 @@ type CurrentWeatherGroup struct {
 
 // NewCurrentGroup returns a new CurrentWeatherGroup pointer with the supplied parameters
 func NewCurrentGroup(unit, lang, key string, options ...Option) (*CurrentWeatherGroup, error) {
-	unitChoice := strings.ToUpper(unit)
-	langChoice := strings.ToUpper(lang)
-
-	g := &CurrentWeatherGroup{
-		Settings: NewSettings(),
-	}
-
-	if ValidDataUnit(unitChoice) {
-		g.Unit = DataUnits[unitChoice]
-	} else {
-		return nil, errUnitUnavailable
-	}
-
-	if ValidLangCode(langChoice) {
-		g.Lang = langChoice
-	} else {
-		return nil, errLangUnavailable
-	}
-	var err error
-	g.Key, err = setKey(key)
+	c, err := newClient(unit, lang, key, options)
 	if err != nil {
 		return nil, err
 	}
-
-	if err := setOptions(g.Settings, options); err != nil {
-		return nil, err
-	}
-	return g, nil
+	return c.CurrentGroup(), nil
 }
 
 // CurrentByIDs will provide the current weather as a list
-// by the specified location identifiers
+// by the specified location identifiers, fetching up to 20 cities in a
+// single request. OWM leaves out cities it can't find instead of failing,
+// so those are reported in Failed and the others are still returned.
 func (g *CurrentWeatherGroup) CurrentByIDs(ids ...int) error {
+	return g.CurrentByIDsCtx(context.Background(), ids...)
+}
+
+// CurrentByIDsCtx is like CurrentByIDs but the request is bound to ctx,
+// which cancels it or sets its deadline.
+func (g *CurrentWeatherGroup) CurrentByIDsCtx(ctx context.Context, ids ...int) error {
 	n := len(ids)
 	if n > maxCityIDs {
 		return errCountOfCityIDs
@@ -73,26 +82,173 @@ func (g *CurrentWeatherGroup) CurrentByIDs(ids ...int) error {
 	id := strings.Join(strIDs, ",")
 	uri := fmt.Sprintf(groupURL, "appid=%s&id=%s&units=%s&lang=%s")
 
-	response, err := g.client.Get(fmt.Sprintf(uri, g.Key, id, g.Unit, g.Lang))
+	response, err := g.get(ctx, EndpointGroup, fmt.Sprintf(uri, g.Key, id, g.Unit, g.Lang))
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode == http.StatusUnauthorized {
-		return errInvalidKey
+	if err = g.decode(response.Body); err != nil {
+		return err
+	}
+
+	g.Failed = nil
+	for _, id := range ids {
+		if _, err := g.ByID(id); err != nil {
+			if g.Failed == nil {
+				g.Failed = make(map[int]error)
+			}
+			g.Failed[id] = err
+		}
+	}
+
+	g.shareSettings()
+	return g.postDecode(g)
+}
+
+// CurrentByBox will provide the current weather of every city within the
+// rectangle bounded by the given longitudes and latitudes, at the map zoom
+// level which sets how many cities are returned.
+func (g *CurrentWeatherGroup) CurrentByBox(lonLeft, latBottom, lonRight, latTop float64, zoom int) error {
+	return g.CurrentByBoxCtx(context.Background(), lonLeft, latBottom, lonRight, latTop, zoom)
+}
+
+// CurrentByBoxCtx is like CurrentByBox but the request is bound to ctx,
+// which cancels it or sets its deadline.
+func (g *CurrentWeatherGroup) CurrentByBoxCtx(ctx context.Context, lonLeft, latBottom, lonRight, latTop float64, zoom int) error {
+	if lonLeft < -180 || lonRight > 180 || lonLeft >= lonRight ||
+		latBottom < -90 || latTop > 90 || latBottom >= latTop || zoom < 0 {
+		return errInvalidBox
 	}
 
-	if err = json.NewDecoder(response.Body).Decode(&g); err != nil {
+	uri := fmt.Sprintf(boxURL, "appid=%s&bbox=%f,%f,%f,%f,%d&units=%s&lang=%s")
+
+	response, err := g.get(ctx, EndpointGroup, fmt.Sprintf(uri, g.Key, lonLeft, latBottom, lonRight, latTop, zoom, g.Unit, g.Lang))
+	if err != nil {
 		return err
 	}
+	defer response.Body.Close()
 
+	if err = g.decode(response.Body); err != nil {
+		return err
+	}
+	g.Count = len(g.List)
+
+	g.shareSettings()
+	return g.postDecode(g)
+}
+
+// CurrentByCircle will provide the current weather of the cnt cities
+// nearest to the given point, closest first, e.g. for weather near the
+// user. Up to 50 cities can be requested.
+func (g *CurrentWeatherGroup) CurrentByCircle(lat, lon float64, cnt int) error {
+	return g.CurrentByCircleCtx(context.Background(), lat, lon, cnt)
+}
+
+// CurrentByCircleCtx is like CurrentByCircle but the request is bound to
+// ctx, which cancels it or sets its deadline.
+func (g *CurrentWeatherGroup) CurrentByCircleCtx(ctx context.Context, lat, lon float64, cnt int) error {
+	if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+		return errInvalidLocation
+	}
+	if cnt < 1 || cnt > maxCircleCities {
+		return errCircleCount
+	}
+
+	uri := fmt.Sprintf(findURL, "appid=%s&lat=%f&lon=%f&cnt=%d&units=%s&lang=%s")
+
+	response, err := g.get(ctx, EndpointGroup, fmt.Sprintf(uri, g.Key, lat, lon, cnt, g.Unit, g.Lang))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if err = g.decode(response.Body); err != nil {
+		return err
+	}
+
+	g.shareSettings()
+	return g.postDecode(g)
+}
+
+// decode unmarshals the response body into g, replacing its list. When
+// precise numbers were requested the Precise field of every city is
+// filled as well.
+func (g *CurrentWeatherGroup) decode(r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	g.List = nil
+	if err := json.Unmarshal(b, &g); err != nil {
+		return err
+	}
+	if !g.precise {
+		return nil
+	}
+	precise, err := preciseEntries(b)
+	if err != nil {
+		return err
+	}
+	for i, w := range g.List {
+		if w != nil && i < len(precise) {
+			w.Precise = precise[i]
+		}
+	}
+	return nil
+}
+
+// ByID returns the current weather of the city in the list, or an error
+// if the response didn't include it.
+func (g *CurrentWeatherGroup) ByID(id int) (*CurrentWeatherData, error) {
+	for _, w := range g.List {
+		if w.ID == id {
+			return w, nil
+		}
+	}
+	return nil, fmt.Errorf("city %d: %w", id, errCityMissing)
+}
+
+// SearchByName will provide the current weather for every city matching
+// the given name instead of only the first match, so that callers can offer
+// a choice between the candidates. Each entry in List carries the city ID
+// to use for subsequent CurrentByID calls.
+func (g *CurrentWeatherGroup) SearchByName(location, searchType string) error {
+	return g.SearchByNameCtx(context.Background(), location, searchType)
+}
+
+// SearchByNameCtx is like SearchByName but the request is bound to ctx,
+// which cancels it or sets its deadline.
+func (g *CurrentWeatherGroup) SearchByNameCtx(ctx context.Context, location, searchType string) error {
+	if searchType != SearchLike && searchType != SearchAccurate {
+		return errSearchUnavailable
+	}
+
+	uri := fmt.Sprintf(findURL, "appid=%s&q=%s&type=%s&units=%s&lang=%s")
+
+	response, err := g.getByName(ctx, EndpointGroup, location, func(location string) string {
+		return fmt.Sprintf(uri, g.Key, url.QueryEscape(location), searchType, g.Unit, g.Lang)
+	})
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if err = g.decode(response.Body); err != nil {
+		return err
+	}
+
+	g.shareSettings()
+	return g.postDecode(g)
+}
+
+// shareSettings hands the group configuration down to every entry of
+// the list.
+func (g *CurrentWeatherGroup) shareSettings() {
 	for _, w := range g.List {
 		w.Settings = g.Settings
 		w.Unit = g.Unit
 		w.Lang = g.Lang
 		w.Key = g.Key
 	}
-
-	return nil
 }
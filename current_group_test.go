@@ -1,7 +1,11 @@
 package openweathermap
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"testing"
 )
 
@@ -34,3 +38,138 @@ func TestCurrentByIDs(t *testing.T) {
 		t.Errorf("wrong count of results: expected %d, got %d", 3, n)
 	}
 }
+
+// TestSearchByName will verify that every candidate returned by the find
+// endpoint is decoded.
+func TestSearchByName(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/data/2.5/find" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if q := r.URL.Query(); q.Get("q") != "Springfield" || q.Get("type") != SearchLike {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"message":"like","cod":"200","count":2,"list":[
+			{"id":4409896,"name":"Springfield","sys":{"country":"US"},"coord":{"lat":37.2153,"lon":-93.2982}},
+			{"id":4250542,"name":"Springfield","sys":{"country":"US"},"coord":{"lat":39.8017,"lon":-89.6437}}]}`)
+	})
+	defer ts.Close()
+
+	g, err := NewCurrentGroup("c", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.SearchByName("Springfield", "fuzzy"); err != errSearchUnavailable {
+		t.Errorf("expected %v, got %v", errSearchUnavailable, err)
+	}
+
+	if err := g.SearchByName("Springfield", SearchLike); err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Count != 2 || len(g.List) != 2 {
+		t.Fatalf("expected 2 candidates, got %d", len(g.List))
+	}
+	if g.List[1].ID != 4250542 || g.List[1].Unit != "metric" {
+		t.Errorf("unexpected candidate %+v", g.List[1])
+	}
+}
+
+// TestCurrentByIDsPartialFailure will verify cities left out of the group
+// response are reported without failing the others.
+func TestCurrentByIDsPartialFailure(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if ids := r.URL.Query().Get("id"); !strings.HasPrefix(ids, "2643743,") {
+			t.Errorf("unexpected ids %q", ids)
+		}
+		fmt.Fprint(w, `{"cnt":2,"list":[
+			{"id":2643743,"name":"London"},
+			{"id":5128581,"name":"New York"}]}`)
+	})
+	defer ts.Close()
+
+	g, err := NewCurrentGroup("c", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CurrentByIDs(2643743, 1, 5128581); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.List) != 2 || len(g.Failed) != 1 || !errors.Is(g.Failed[1], errCityMissing) {
+		t.Fatalf("expected one missing city, got %v", g.Failed)
+	}
+	if w, err := g.ByID(5128581); err != nil || w.Name != "New York" || w.Unit != "metric" {
+		t.Errorf("unexpected city %+v, %v", w, err)
+	}
+	if _, err := g.ByID(1); !errors.Is(err, errCityMissing) {
+		t.Errorf("expected %v, got %v", errCityMissing, err)
+	}
+
+	if err := g.CurrentByIDs(2643743, 5128581); err != nil || g.Failed != nil {
+		t.Errorf("expected no failures, got %v, %v", g.Failed, err)
+	}
+}
+
+// TestCurrentByBox will verify the cities within a bounding box are
+// requested and returned.
+func TestCurrentByBox(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/box/city") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		if bbox := r.URL.Query().Get("bbox"); bbox != "12.000000,32.000000,15.000000,37.000000,10" {
+			t.Errorf("unexpected bbox %q", bbox)
+		}
+		fmt.Fprint(w, `{"cod":200,"calctime":0.3,"cnt":2,"list":[
+			{"id":2208791,"name":"Yafran","coord":{"Lon":12.52859,"Lat":32.06329},"main":{"temp":9.68}},
+			{"id":2210247,"name":"Tarhuna","coord":{"Lon":13.63324,"Lat":32.43502},"main":{"temp":9.5}}]}`)
+	})
+	defer ts.Close()
+
+	g, err := NewCurrentGroup("c", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CurrentByBox(15, 32, 12, 37, 10); err != errInvalidBox {
+		t.Errorf("expected %v, got %v", errInvalidBox, err)
+	}
+	if err := g.CurrentByBox(12, 32, 15, 37, 10); err != nil {
+		t.Fatal(err)
+	}
+	if g.Count != 2 || g.List[1].Name != "Tarhuna" || g.List[1].GeoPos.Latitude != 32.43502 || g.List[1].Unit != "metric" {
+		t.Errorf("unexpected cities %+v", g.List)
+	}
+}
+
+// TestCurrentByCircle will verify the cities nearest to a point are
+// requested from the find endpoint.
+func TestCurrentByCircle(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if !strings.HasSuffix(r.URL.Path, "/find") || q.Get("lat") != "55.500000" || q.Get("lon") != "37.500000" || q.Get("cnt") != "2" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		fmt.Fprint(w, `{"message":"accurate","cod":"200","count":2,"list":[
+			{"id":495260,"name":"Shcherbinka","coord":{"lat":55.4997,"lon":37.5597}},
+			{"id":564517,"name":"Dubrovka","coord":{"lat":55.4667,"lon":37.55}}]}`)
+	})
+	defer ts.Close()
+
+	g, err := NewCurrentGroup("c", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CurrentByCircle(55.5, 37.5, 51); err != errCircleCount {
+		t.Errorf("expected %v, got %v", errCircleCount, err)
+	}
+	if err := g.CurrentByCircle(95, 37.5, 2); err != errInvalidLocation {
+		t.Errorf("expected %v, got %v", errInvalidLocation, err)
+	}
+	if err := g.CurrentByCircle(55.5, 37.5, 2); err != nil {
+		t.Fatal(err)
+	}
+	if g.Count != 2 || g.List[0].Name != "Shcherbinka" || g.List[0].Key != "key" {
+		t.Errorf("unexpected cities %+v", g.List)
+	}
+}
@@ -15,6 +15,7 @@
 package openweathermap
 
 import (
+	"fmt"
 	"net/http"
 	"os"
 	"reflect"
@@ -256,3 +257,33 @@ func TestCurrentByZipcode(t *testing.T) {
 }
 
 func TestCurrentByArea(t *testing.T) {}
+
+// TestCurrentByZipcodeEscaping will verify that zip and country codes
+// can't inject additional query parameters.
+func TestCurrentByZipcodeEscaping(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("zip") != "19125&units=standard,US=1" || q.Get("units") != "imperial" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, `{"cod":200}`)
+	})
+	defer ts.Close()
+
+	w, err := NewCurrent("F", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.CurrentByZipcode("19125&units=standard", "US=1"); err != nil {
+		t.Error(err)
+	}
+
+	if err := w.CurrentByZipcode("19125", "US\nHost: evil"); err != errInvalidLocation {
+		t.Errorf("expected %v, got %v", errInvalidLocation, err)
+	}
+
+	if err := w.CurrentByName("London\r\n"); err != errInvalidLocation {
+		t.Errorf("expected %v, got %v", errInvalidLocation, err)
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"time"
+)
+
+// Common base temperatures for degree days: 18°C in most of the world and
+// 65°F in the US.
+const (
+	BaseCelsius    = 18.0
+	BaseFahrenheit = 65.0
+)
+
+// DegreeDays holds heating and cooling degree days, which approximate the
+// energy needed to heat or cool a building. They're in the temperature
+// unit of the data they were computed from.
+type DegreeDays struct {
+	Heating float64
+	Cooling float64
+}
+
+// Add returns the sum of both degree days, e.g. to combine a history with
+// a forecast.
+func (d DegreeDays) Add(o DegreeDays) DegreeDays {
+	return DegreeDays{Heating: d.Heating + o.Heating, Cooling: d.Cooling + o.Cooling}
+}
+
+// SlotDegreeDays integrates the temperature of the slots against base,
+// in the same unit as the slot temperatures, weighting each slot by its
+// duration.
+func SlotDegreeDays(slots []Slot, base float64) DegreeDays {
+	var d DegreeDays
+	for _, s := range slots {
+		days := float64(s.Duration) / float64(24*time.Hour)
+		if s.Temp < base {
+			d.Heating += (base - s.Temp) * days
+		} else {
+			d.Cooling += (s.Temp - base) * days
+		}
+	}
+	return d
+}
+
+// DailyDegreeDays returns the degree days of one day from its minimum and
+// maximum temperatures using the mean temperature method.
+func DailyDegreeDays(min, max, base float64) DegreeDays {
+	mean := (min + max) / 2
+	if mean < base {
+		return DegreeDays{Heating: base - mean}
+	}
+	return DegreeDays{Cooling: mean - base}
+}
+
+// DegreeDays sums the daily degree days of the forecast.
+func (f *Forecast16WeatherData) DegreeDays(base float64) DegreeDays {
+	var d DegreeDays
+	for _, day := range f.List {
+		d = d.Add(DailyDegreeDays(day.Temp.Min, day.Temp.Max, base))
+	}
+	return d
+}
+
+// DegreeDays sums the daily degree days of the one call daily forecast.
+func (w *OneCallData) DegreeDays(base float64) DegreeDays {
+	var d DegreeDays
+	for _, day := range w.Daily {
+		d = d.Add(DailyDegreeDays(day.Temp.Min, day.Temp.Max, base))
+	}
+	return d
+}
+
+// Slots returns the hourly history as one hour slots in UTC.
+func (h *HistoricalWeatherData) Slots() []Slot {
+	slots := make([]Slot, 0, len(h.List))
+	for _, l := range h.List {
+		s := Slot{
+			Start:         time.Unix(int64(l.Dt), 0).UTC(),
+			Duration:      time.Hour,
+			Temp:          l.Main.Temp,
+			WindSpeed:     l.Wind.Speed,
+			Precipitation: l.Rain.OneH,
+			Humidity:      l.Main.Humidity,
+			Clouds:        l.Clouds.All,
+		}
+		if c, ok := l.FirstCondition(); ok {
+			s.ConditionID = c.ID
+		}
+		slots = append(slots, s)
+	}
+	return slots
+}
@@ -0,0 +1,57 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// TestSlotDegreeDays will verify slots are weighted by their duration.
+func TestSlotDegreeDays(t *testing.T) {
+	slots := []Slot{
+		{Temp: 10, Duration: 12 * time.Hour}, // 8 below base for half a day
+		{Temp: 24, Duration: 6 * time.Hour},  // 6 above base for a quarter day
+		{Temp: 18, Duration: 6 * time.Hour},
+	}
+	d := SlotDegreeDays(slots, BaseCelsius)
+	if d.Heating != 4 || d.Cooling != 1.5 {
+		t.Errorf("expected 4 heating and 1.5 cooling degree days, got %+v", d)
+	}
+
+	h := &HistoricalWeatherData{List: []WeatherHistory{{Main: Main{Temp: 6}}, {Main: Main{Temp: 6}}}}
+	if d := SlotDegreeDays(h.Slots(), BaseCelsius); math.Abs(d.Heating-1) > 1e-9 {
+		t.Errorf("expected 1 heating degree day from two hours at 6, got %+v", d)
+	}
+}
+
+// TestDailyDegreeDays will verify the mean temperature method over daily
+// forecasts.
+func TestDailyDegreeDays(t *testing.T) {
+	f := &Forecast16WeatherData{List: []Forecast16WeatherList{
+		{Temp: Temperature{Min: 40, Max: 60}},
+		{Temp: Temperature{Min: 70, Max: 90}},
+	}}
+	d := f.DegreeDays(BaseFahrenheit)
+	if d.Heating != 15 || d.Cooling != 15 {
+		t.Errorf("expected 15 heating and 15 cooling degree days, got %+v", d)
+	}
+
+	o := &OneCallData{Daily: []OneCallDailyData{{Temp: Temperature{Min: 14, Max: 18}}}}
+	if total := o.DegreeDays(BaseCelsius).Add(d); total.Heating != 17 || total.Cooling != 15 {
+		t.Errorf("unexpected total %+v", total)
+	}
+}
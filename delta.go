@@ -0,0 +1,142 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Delta holds only the fields that changed between two snapshots, keyed by
+// their JSON names. It follows JSON merge patch (RFC 7386) semantics:
+// nested objects are diffed field by field, arrays are replaced as a whole
+// and fields missing from the newer snapshot are recorded as null. Deltas
+// marshal to compact JSON, making them suited for persisting long polling
+// histories as a base snapshot followed by a series of deltas.
+type Delta map[string]interface{}
+
+// settingsFields are the result fields copied from the request settings
+// rather than decoded from the response. They are left out of snapshots
+// so the API key is never persisted.
+var settingsFields = []string{"Key", "Unit", "Lang"}
+
+// Diff computes the delta turning the prev snapshot into next. Both must
+// marshal to JSON objects, such as *CurrentWeatherData. The Key, Unit and
+// Lang fields of results aren't part of the delta.
+func Diff(prev, next interface{}) (Delta, error) {
+	p, err := toJSONObject(prev)
+	if err != nil {
+		return nil, err
+	}
+	n, err := toJSONObject(next)
+	if err != nil {
+		return nil, err
+	}
+	return diffObjects(p, n), nil
+}
+
+// Apply reconstructs a snapshot by applying the delta to base and
+// unmarshaling the result into out.
+func (d Delta) Apply(base, out interface{}) error {
+	return Reconstruct(base, []Delta{d}, out)
+}
+
+// Reconstruct applies the deltas in order to base and unmarshals the final
+// snapshot into out.
+func Reconstruct(base interface{}, deltas []Delta, out interface{}) error {
+	obj, err := toJSONObject(base)
+	if err != nil {
+		return err
+	}
+	for _, d := range deltas {
+		obj = mergeObjects(obj, d)
+	}
+
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+// toJSONObject converts v to its generic JSON object representation,
+// without the settings fields of results.
+func toJSONObject(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(withoutFields(v, settingsFields...))
+	if err != nil {
+		return nil, err
+	}
+	obj := make(map[string]interface{})
+	if err := json.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// diffObjects returns the merge patch turning prev into next.
+func diffObjects(prev, next map[string]interface{}) Delta {
+	d := make(Delta)
+	for k, nv := range next {
+		pv, ok := prev[k]
+		if !ok {
+			d[k] = nv
+			continue
+		}
+		pm, pok := pv.(map[string]interface{})
+		nm, nok := nv.(map[string]interface{})
+		if pok && nok {
+			if sub := diffObjects(pm, nm); len(sub) > 0 {
+				d[k] = map[string]interface{}(sub)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(pv, nv) {
+			d[k] = nv
+		}
+	}
+	for k := range prev {
+		if _, ok := next[k]; !ok {
+			d[k] = nil
+		}
+	}
+	return d
+}
+
+// mergeObjects applies the merge patch to the object in place and
+// returns it.
+func mergeObjects(obj map[string]interface{}, patch map[string]interface{}) map[string]interface{} {
+	for k, pv := range patch {
+		if pv == nil {
+			delete(obj, k)
+			continue
+		}
+		pm, ok := pv.(map[string]interface{})
+		if !ok {
+			if d, isDelta := pv.(Delta); isDelta {
+				pm, ok = map[string]interface{}(d), true
+			}
+		}
+		if ok {
+			om, _ := obj[k].(map[string]interface{})
+			if om == nil {
+				om = make(map[string]interface{})
+			}
+			obj[k] = mergeObjects(om, pm)
+			continue
+		}
+		obj[k] = pv
+	}
+	return obj
+}
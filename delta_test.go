@@ -0,0 +1,128 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestDiff will verify only changed fields end up in the delta.
+func TestDiff(t *testing.T) {
+	prev := &CurrentWeatherData{ID: 1, Name: "Dublin", Dt: 100, Main: Main{Temp: 11.2, Humidity: 80}}
+	next := &CurrentWeatherData{ID: 1, Name: "Dublin", Dt: 200, Main: Main{Temp: 11.8, Humidity: 80}}
+
+	d, err := Diff(prev, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := json.Marshal(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"dt":200,"main":{"temp":11.8}}`; string(b) != expected {
+		t.Errorf("expected %s, got %s", expected, b)
+	}
+
+	if d, _ := Diff(next, next); len(d) != 0 {
+		t.Errorf("expected empty delta, got %v", d)
+	}
+}
+
+// TestDiffWithoutKey will verify the API key and the other settings of a
+// result never end up in a delta or a reconstructed snapshot.
+func TestDiffWithoutKey(t *testing.T) {
+	prev := &CurrentWeatherData{ID: 1, Dt: 100, Key: "secret", Unit: Metric, Lang: "en"}
+	next := &CurrentWeatherData{ID: 1, Dt: 200, Key: "other", Unit: Imperial, Lang: "de"}
+
+	for _, pair := range [][2]interface{}{{prev, next}, {&CurrentWeatherData{}, next}} {
+		d, err := Diff(pair[0], pair[1])
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := `{"dt":200}`; pair[0] == prev && string(b) != expected {
+			t.Errorf("expected %s, got %s", expected, b)
+		}
+		for _, s := range []string{"secret", "other", "Key", "Unit", "Lang"} {
+			if strings.Contains(string(b), s) {
+				t.Errorf("expected no %q in the delta, got %s", s, b)
+			}
+		}
+	}
+
+	d, _ := Diff(prev, next)
+	var out CurrentWeatherData
+	if err := Reconstruct(prev, []Delta{d}, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Key != "" || out.Unit != "" || out.Lang != "" || out.Dt != 200 {
+		t.Errorf("unexpected snapshot %+v", out)
+	}
+	if prev.Key != "secret" {
+		t.Error("expected the result key to be left untouched")
+	}
+}
+
+// TestReconstruct will verify snapshots can be rebuilt from a base and a
+// series of persisted deltas.
+func TestReconstruct(t *testing.T) {
+	snapshots := []*CurrentWeatherData{
+		{ID: 1, Dt: 100, Weather: []Weather{{ID: 800, Main: "Clear"}}, Rain: Rain{OneH: 0.5}},
+		{ID: 1, Dt: 200, Weather: []Weather{{ID: 500, Main: "Rain"}}},
+		{ID: 1, Dt: 300, Weather: []Weather{{ID: 500, Main: "Rain"}}, Main: Main{Pressure: 1012}},
+	}
+
+	var deltas []Delta
+	for i := 1; i < len(snapshots); i++ {
+		d, err := Diff(snapshots[i-1], snapshots[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// persist and load the delta to make sure it survives encoding
+		b, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var loaded Delta
+		if err := json.Unmarshal(b, &loaded); err != nil {
+			t.Fatal(err)
+		}
+		deltas = append(deltas, loaded)
+	}
+
+	var got CurrentWeatherData
+	if err := Reconstruct(snapshots[0], deltas, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&got, snapshots[2]) {
+		t.Errorf("expected %+v, got %+v", snapshots[2], got)
+	}
+
+	got = CurrentWeatherData{}
+	if err := deltas[0].Apply(snapshots[0], &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&got, snapshots[1]) {
+		t.Errorf("expected %+v, got %+v", snapshots[1], got)
+	}
+}
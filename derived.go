@@ -0,0 +1,91 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import "math"
+
+// fromCelsius returns a temperature in degrees Celsius in the unit system
+// u.
+func fromCelsius(c float64, u Unit) Degrees {
+	d := Degrees{Value: c, Unit: Metric}
+	switch u {
+	case Imperial:
+		return Degrees{Value: d.ToFahrenheit(), Unit: u}
+	case Metric:
+		return d
+	default:
+		return Degrees{Value: d.ToKelvin(), Unit: Standard}
+	}
+}
+
+// DewPoint returns the temperature the air has to be cooled to for dew to
+// form, from the temperature and relative humidity with the Magnus
+// formula, in the unit system of the data.
+func (w *CurrentWeatherData) DewPoint() Degrees {
+	const a, b = 17.62, 243.12
+	t := w.Temp().ToCelsius()
+	rh := math.Max(float64(w.Main.Humidity), 1)
+	gamma := math.Log(rh/100) + a*t/(b+t)
+	return fromCelsius(b*gamma/(a-gamma), w.Unit)
+}
+
+// HeatIndex returns how hot it feels when humidity is factored in, with
+// the US National Weather Service's Rothfusz regression, in the unit
+// system of the data. Below 80°F the regression doesn't apply and the
+// simpler Steadman approximation is used instead.
+func (w *CurrentWeatherData) HeatIndex() Degrees {
+	t := w.Temp().ToFahrenheit()
+	rh := float64(w.Main.Humidity)
+	hi := 0.5 * (t + 61 + (t-68)*1.2 + rh*0.094)
+	if (hi+t)/2 < 80 {
+		return fromCelsius(Degrees{Value: hi, Unit: Imperial}.ToCelsius(), w.Unit)
+	}
+	hi = -42.379 + 2.04901523*t + 10.14333127*rh - 0.22475541*t*rh -
+		0.00683783*t*t - 0.05481717*rh*rh + 0.00122874*t*t*rh +
+		0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+	switch {
+	case rh < 13 && t <= 112:
+		hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+	case rh > 85 && t <= 87:
+		hi += (rh - 85) / 10 * (87 - t) / 5
+	}
+	return fromCelsius(Degrees{Value: hi, Unit: Imperial}.ToCelsius(), w.Unit)
+}
+
+// WindChill returns how cold it feels when wind is factored in, with the
+// formula of the US National Weather Service and Environment Canada, in
+// the unit system of the data. It's only defined up to 50°F and from a
+// wind of 3 mph; otherwise the temperature itself is returned.
+func (w *CurrentWeatherData) WindChill() Degrees {
+	t := w.Temp().ToFahrenheit()
+	v := w.WindSpeed().ToMph()
+	if t > 50 || v < 3 {
+		return w.Temp()
+	}
+	p := math.Pow(v, 0.16)
+	wc := 35.74 + 0.6215*t - 35.75*p + 0.4275*t*p
+	return fromCelsius(Degrees{Value: wc, Unit: Imperial}.ToCelsius(), w.Unit)
+}
+
+// ApparentTemperature returns the temperature as perceived by people in
+// the shade, accounting for humidity and wind, with the Steadman formula
+// used by the Australian Bureau of Meteorology, in the unit system of the
+// data.
+func (w *CurrentWeatherData) ApparentTemperature() Degrees {
+	t := w.Temp().ToCelsius()
+	e := float64(w.Main.Humidity) / 100 * 6.105 * math.Exp(17.27*t/(237.7+t))
+	at := t + 0.33*e - 0.70*w.WindSpeed().ToMs() - 4.00
+	return fromCelsius(at, w.Unit)
+}
@@ -0,0 +1,55 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"math"
+	"testing"
+)
+
+// TestDerivedMetrics will verify the derived temperatures against
+// published reference values, in the unit system of the data.
+func TestDerivedMetrics(t *testing.T) {
+	near := func(a, b, tolerance float64) bool { return math.Abs(a-b) <= tolerance }
+
+	hot := &CurrentWeatherData{Unit: Imperial, Main: Main{Temp: 90, Humidity: 70}}
+	if hi := hot.HeatIndex(); hi.Unit != Imperial || !near(hi.Value, 105.9, 0.5) {
+		t.Errorf("expected a heat index of about 106°F, got %+v", hi)
+	}
+	if hi := (&CurrentWeatherData{Unit: Imperial, Main: Main{Temp: 70, Humidity: 50}}).HeatIndex(); !near(hi.Value, 69.1, 0.5) {
+		t.Errorf("expected a heat index of about 69°F, got %+v", hi)
+	}
+
+	cold := &CurrentWeatherData{Unit: Imperial, Main: Main{Temp: 0}, Wind: Wind{Speed: 15}}
+	if wc := cold.WindChill(); !near(wc.Value, -19.4, 0.1) {
+		t.Errorf("expected a wind chill of about -19°F, got %+v", wc)
+	}
+	if wc := hot.WindChill(); wc != hot.Temp() {
+		t.Errorf("expected no wind chill when warm, got %+v", wc)
+	}
+
+	mild := &CurrentWeatherData{Unit: Metric, Main: Main{Temp: 25, Humidity: 60}, Wind: Wind{Speed: 2}}
+	if dp := mild.DewPoint(); dp.Unit != Metric || !near(dp.Value, 16.7, 0.1) {
+		t.Errorf("expected a dew point of about 16.7°C, got %+v", dp)
+	}
+	if at := mild.ApparentTemperature(); !near(at.Value, 25.9, 0.1) {
+		t.Errorf("expected an apparent temperature of about 25.9°C, got %+v", at)
+	}
+
+	kelvin := &CurrentWeatherData{Unit: Standard, Main: Main{Temp: 298.15, Humidity: 60}}
+	if dp := kelvin.DewPoint(); dp.Unit != Standard || !near(dp.ToCelsius(), 16.7, 0.1) {
+		t.Errorf("expected the dew point in Kelvin, got %+v", dp)
+	}
+}
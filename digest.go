@@ -0,0 +1,162 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DigestItem is a change or alert waiting to be sent in a digest.
+type DigestItem struct {
+	Time    time.Time         `json:"time"`
+	Kind    string            `json:"kind"` // e.g. alert_started or changed
+	Summary string            `json:"summary"`
+	Alert   *OneCallAlertData `json:"alert,omitempty"`
+}
+
+// Digest groups the items of a location collected over a window into a
+// single notification.
+type Digest struct {
+	Location string       `json:"location"`
+	From     time.Time    `json:"from"`
+	To       time.Time    `json:"to"`
+	Items    []DigestItem `json:"items"`
+}
+
+// Digester batches notifications per location, sending one digest per
+// location once its window has passed since the first pending item. It's
+// safe for concurrent use.
+type Digester struct {
+	mu      sync.Mutex
+	window  time.Duration
+	send    func(Digest) error
+	pending map[string]*Digest
+	now     func() time.Time
+}
+
+// NewDigester returns a digester sending the digests collected over the
+// window with send.
+func NewDigester(window time.Duration, send func(Digest) error) *Digester {
+	return &Digester{
+		window:  window,
+		send:    send,
+		pending: make(map[string]*Digest),
+		now:     time.Now,
+	}
+}
+
+// Add queues the item in the digest of the location.
+func (d *Digester) Add(location string, item DigestItem) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	p, ok := d.pending[location]
+	if !ok {
+		p = &Digest{Location: location, From: item.Time}
+		d.pending[location] = p
+	}
+	if item.Time.After(p.To) {
+		p.To = item.Time
+	}
+	p.Items = append(p.Items, item)
+}
+
+// Flush sends the digests whose window has passed at now, oldest first,
+// and stops at the first failure, keeping the unsent digests queued.
+func (d *Digester) Flush(now time.Time) error {
+	return d.flush(func(p *Digest) bool { return !now.Before(p.From.Add(d.window)) })
+}
+
+// FlushAll sends every pending digest regardless of its window, e.g. on
+// shutdown.
+func (d *Digester) FlushAll() error {
+	return d.flush(func(*Digest) bool { return true })
+}
+
+// flush sends the pending digests selected by due.
+func (d *Digester) flush(due func(*Digest) bool) error {
+	d.mu.Lock()
+	var ready []*Digest
+	for _, p := range d.pending {
+		if due(p) {
+			ready = append(ready, p)
+		}
+	}
+	for _, p := range ready {
+		delete(d.pending, p.Location)
+	}
+	d.mu.Unlock()
+
+	sort.Slice(ready, func(i, j int) bool { return ready[i].From.Before(ready[j].From) })
+	for i, p := range ready {
+		if err := d.send(*p); err != nil {
+			d.requeue(ready[i:])
+			return fmt.Errorf("digest for %s: %w", p.Location, err)
+		}
+	}
+	return nil
+}
+
+// requeue puts unsent digests back, merging items added since.
+func (d *Digester) requeue(unsent []*Digest) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, p := range unsent {
+		if newer, ok := d.pending[p.Location]; ok {
+			p.Items = append(p.Items, newer.Items...)
+			if newer.To.After(p.To) {
+				p.To = newer.To
+			}
+		}
+		d.pending[p.Location] = p
+	}
+}
+
+// Subscribe queues the alert lifecycle and data change events of the bus
+// under location, so a bus per location, e.g. shared by the location's
+// client and AlertTracker, yields one digest per location.
+func (d *Digester) Subscribe(b *Bus, location string) {
+	b.Subscribe(func(e Event) {
+		item := DigestItem{Time: d.now()}
+		switch e := e.(type) {
+		case AlertStarted:
+			item.Kind, item.Summary, item.Alert = "alert_started", e.Alert.Event, &e.Alert
+		case AlertUpdated:
+			item.Kind, item.Summary, item.Alert = "alert_updated", e.Alert.Event, &e.Alert
+		case AlertExpired:
+			item.Kind, item.Summary, item.Alert = "alert_expired", e.Alert.Event, &e.Alert
+		case DataChanged:
+			item.Kind, item.Summary = "changed", string(e.Endpoint)
+		default:
+			return
+		}
+		d.Add(location, item)
+	})
+}
+
+// PublishDigest returns a send function publishing digests as JSON to
+// "<prefix>.<location>".
+func PublishDigest(p Publisher, prefix string) func(Digest) error {
+	return func(dg Digest) error {
+		b, err := json.Marshal(dg)
+		if err != nil {
+			return err
+		}
+		return p.Publish(prefix+"."+dg.Location, b)
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestDigester will verify items are batched per location until their
+// window has passed.
+func TestDigester(t *testing.T) {
+	var sent []Digest
+	d := NewDigester(time.Hour, func(dg Digest) error {
+		sent = append(sent, dg)
+		return nil
+	})
+
+	start := time.Date(2023, 11, 14, 9, 0, 0, 0, time.UTC)
+	d.Add("oslo", DigestItem{Time: start, Kind: "changed"})
+	d.Add("oslo", DigestItem{Time: start.Add(20 * time.Minute), Kind: "alert_started", Summary: "Wind Warning"})
+	d.Add("bergen", DigestItem{Time: start.Add(30 * time.Minute), Kind: "changed"})
+
+	if err := d.Flush(start.Add(59 * time.Minute)); err != nil || len(sent) != 0 {
+		t.Fatalf("expected nothing due yet, got %v, %v", sent, err)
+	}
+	if err := d.Flush(start.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0].Location != "oslo" || len(sent[0].Items) != 2 || !sent[0].To.Equal(start.Add(20*time.Minute)) {
+		t.Fatalf("unexpected digests %+v", sent)
+	}
+
+	if err := d.FlushAll(); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 2 || sent[1].Location != "bergen" {
+		t.Errorf("unexpected digests %+v", sent)
+	}
+}
+
+// TestDigesterRequeue will verify digests that failed to send are kept.
+func TestDigesterRequeue(t *testing.T) {
+	fail := true
+	var sent []Digest
+	d := NewDigester(time.Minute, func(dg Digest) error {
+		if fail {
+			return errors.New("smtp unavailable")
+		}
+		sent = append(sent, dg)
+		return nil
+	})
+
+	now := time.Unix(1700000000, 0)
+	d.Add("oslo", DigestItem{Time: now})
+	if err := d.Flush(now.Add(time.Hour)); err == nil || !strings.Contains(err.Error(), "oslo") {
+		t.Fatalf("expected the failure to be reported, got %v", err)
+	}
+	fail = false
+	if err := d.Flush(now.Add(time.Hour)); err != nil || len(sent) != 1 {
+		t.Errorf("expected the digest to be retried, got %v, %v", sent, err)
+	}
+}
+
+// TestDigesterSubscribe will verify bus events are collected and published
+// once per location.
+func TestDigesterSubscribe(t *testing.T) {
+	pub := memoryPublisher{}
+	d := NewDigester(time.Minute, PublishDigest(pub, "digest"))
+	d.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	bus := NewBus()
+	d.Subscribe(bus, "oslo")
+	tracker := NewAlertTracker(bus)
+	tracker.Observe([]OneCallAlertData{{Event: "Fog", End: 1700003600}}, time.Unix(1700000000, 0))
+	bus.publish(DataChanged{Endpoint: EndpointOneCall})
+	bus.publish(RequestStarted{Endpoint: EndpointOneCall})
+
+	if err := d.FlushAll(); err != nil {
+		t.Fatal(err)
+	}
+	msgs := pub["digest.oslo"]
+	if len(msgs) != 1 || !strings.Contains(msgs[0], `"kind":"alert_started"`) || !strings.Contains(msgs[0], `"kind":"changed"`) {
+		t.Errorf("unexpected messages %v", pub)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+)
+
+// WithDisplayNames fills DisplayName for current weather and one call
+// queries by coordinates with the name of the place the geocoding API
+// finds there, as the Name OWM sends for coordinates is often that of a
+// nearby station or empty. The coordinates are rounded to three decimals,
+// about 100 meters, so nearby queries share a lookup, which WithCache
+// caches like any other response. A failed lookup leaves DisplayName
+// empty without failing the query.
+func WithDisplayNames() Option {
+	return func(s *Settings) error {
+		s.displayNames = true
+		return nil
+	}
+}
+
+// displayName returns the name in the language of the place at the
+// coordinates, or an empty string if display names are disabled or the
+// lookup failed. The lookup works on a copy of the settings, so it
+// doesn't change the Checksum of the result or run its hooks.
+func (s *Settings) displayName(ctx context.Context, key, lang string, location *Coordinates) string {
+	if !s.displayNames {
+		return ""
+	}
+	v := url.Values{
+		"lat":   {strconv.FormatFloat(math.Round(location.Latitude*1000)/1000, 'f', 3, 64)},
+		"lon":   {strconv.FormatFloat(math.Round(location.Longitude*1000)/1000, 'f', 3, 64)},
+		"limit": {"1"},
+		"appid": {key},
+	}
+	r := *s
+	response, err := r.get(ctx, EndpointGeocoding, fmt.Sprintf(geoReverseURL, v.Encode()))
+	if err != nil {
+		return ""
+	}
+	defer response.Body.Close()
+
+	var places []GeoLocation
+	if err := json.NewDecoder(response.Body).Decode(&places); err != nil || len(places) == 0 {
+		return ""
+	}
+	return places[0].LocalName(lang)
+}
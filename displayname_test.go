@@ -0,0 +1,80 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWithDisplayNames will verify coordinate queries are given the name
+// of the place, looked up once for nearby coordinates through the cache.
+func TestWithDisplayNames(t *testing.T) {
+	const weather = `{"name":"","coord":{"lat":51.5,"lon":-0.12},"current":{"dt":1}}`
+	lookups := 0
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/geo/") {
+			lookups++
+			if r.URL.Query().Get("lat") != "51.501" || r.URL.Query().Get("limit") != "1" {
+				t.Errorf("unexpected lookup %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `[{"name":"Westminster","local_names":{"fr":"Westminster","de":"Westminster"},"lat":51.5,"lon":-0.12,"country":"GB"}]`)
+			return
+		}
+		fmt.Fprint(w, weather)
+	})
+	defer ts.Close()
+
+	cache := NewMemoryCache()
+	w, err := NewCurrent("C", "FR", "key", WithHttpClient(hc), WithDisplayNames(), WithCache(cache, time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.CurrentByCoordinates(&Coordinates{Latitude: 51.5012, Longitude: -0.1245}); err != nil {
+		t.Fatal(err)
+	}
+	o, err := NewOneCall("C", "FR", "key", nil, WithHttpClient(hc), WithDisplayNames(), WithCache(cache, time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := o.OneCallByCoordinates(&Coordinates{Latitude: 51.5008, Longitude: -0.1249}); err != nil {
+		t.Fatal(err)
+	}
+	if w.DisplayName != "Westminster" || o.DisplayName != "Westminster" {
+		t.Errorf("unexpected display names %q and %q", w.DisplayName, o.DisplayName)
+	}
+	if lookups != 1 {
+		t.Errorf("expected nearby coordinates to share a cached lookup, got %d", lookups)
+	}
+	if sum := sha256.Sum256([]byte(weather)); w.Checksum() != hex.EncodeToString(sum[:]) {
+		t.Error("expected the checksum of the weather response")
+	}
+
+	w, err = NewCurrent("C", "FR", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.CurrentByCoordinates(&Coordinates{Latitude: 51.5, Longitude: -0.12}); err != nil {
+		t.Fatal(err)
+	}
+	if w.DisplayName != "" || lookups != 1 {
+		t.Errorf("expected no lookup by default, got %q", w.DisplayName)
+	}
+}
@@ -15,4 +15,10 @@
 // Package openweathermap is a library for use to access the
 // http://openweathermap.org API.  JSON is the only return format supported
 // at this time.
+//
+// The package keeps the API of github.com/briandowns/openweathermap, so
+// users of that package can migrate by changing the import path, with one
+// exception: the Unit field of the result types has the type Unit rather
+// than string. Compare it against Metric, Imperial and Standard, or convert
+// it with string(w.Unit) where a string is needed.
 package openweathermap
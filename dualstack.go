@@ -0,0 +1,142 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+var errUnsupportedTransport = errors.New("http client transport must be an *http.Transport")
+
+// WithDualStackFallback makes connections that time out within the given
+// connect timeout, are refused or find the network unreachable retry once
+// immediately over the other IP family, so a network with broken IPv6 (or
+// IPv4) doesn't stall every request. It works on a copy of the configured
+// http client, which must use an *http.Transport, and is applied after
+// all other options. Connections are still made by the transport's own
+// DialContext, if set.
+func WithDualStackFallback(connectTimeout time.Duration) Option {
+	return func(s *Settings) error {
+		s.dualStack = connectTimeout
+		return nil
+	}
+}
+
+// dualStackClient returns a copy of the client whose transport dials with
+// a fallbackDialer wrapping its DialContext, or a default dialer.
+func dualStackClient(c *http.Client, connectTimeout time.Duration) (*http.Client, error) {
+	rt := c.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		return nil, errUnsupportedTransport
+	}
+
+	dial := t.DialContext
+	if dial == nil {
+		d := &net.Dialer{KeepAlive: 30 * time.Second}
+		dial = d.DialContext
+	}
+	fd := &fallbackDialer{
+		lookup:  net.DefaultResolver.LookupIPAddr,
+		dial:    dial,
+		timeout: connectTimeout,
+	}
+
+	t = t.Clone()
+	t.DialContext = fd.DialContext
+
+	hc := *c
+	hc.Transport = t
+	return &hc, nil
+}
+
+// fallbackDialer dials over the IP family of the first resolved address
+// and, if that fails to connect, once over the other family. Each dial is
+// bound to the timeout.
+type fallbackDialer struct {
+	lookup  func(ctx context.Context, host string) ([]net.IPAddr, error)
+	dial    func(ctx context.Context, network, address string) (net.Conn, error)
+	timeout time.Duration
+}
+
+// DialContext implements the http.Transport dial hook.
+func (d *fallbackDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if network != "tcp" {
+		return d.dialTimeout(ctx, network, address)
+	}
+
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	addrs, err := d.lookup(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		return d.dialTimeout(ctx, network, address)
+	}
+
+	primary, alternate := "tcp4", "tcp6"
+	if addrs[0].IP.To4() == nil {
+		primary, alternate = alternate, primary
+	}
+
+	conn, err := d.dialTimeout(ctx, primary, address)
+	if err == nil || !unreachable(err) || ctx.Err() != nil || !hasFamily(addrs, alternate) {
+		return conn, err
+	}
+
+	return d.dialTimeout(ctx, alternate, address)
+}
+
+// dialTimeout dials bound to the timeout, if any.
+func (d *fallbackDialer) dialTimeout(ctx context.Context, network, address string) (net.Conn, error) {
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+	return d.dial(ctx, network, address)
+}
+
+// unreachable reports whether the error shows the address can't be
+// reached over its family: a timeout, a refused connection or an
+// unreachable network or host.
+func unreachable(err error) bool {
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ENETUNREACH) || errors.Is(err, syscall.EHOSTUNREACH)
+}
+
+// hasFamily reports whether any of the addresses belongs to the given
+// network family.
+func hasFamily(addrs []net.IPAddr, network string) bool {
+	for _, a := range addrs {
+		if (a.IP.To4() != nil) == (network == "tcp4") {
+			return true
+		}
+	}
+	return false
+}
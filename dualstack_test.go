@@ -0,0 +1,145 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// timeoutError is a net.Error reporting a timeout.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// TestFallbackDialer will verify a timed out dial is retried once over the
+// other IP family.
+func TestFallbackDialer(t *testing.T) {
+	var networks []string
+	d := &fallbackDialer{
+		lookup: func(ctx context.Context, host string) ([]net.IPAddr, error) {
+			return []net.IPAddr{{IP: net.ParseIP("2001:db8::1")}, {IP: net.ParseIP("192.0.2.1")}}, nil
+		},
+		dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			networks = append(networks, network)
+			if network == "tcp6" {
+				return nil, timeoutError{}
+			}
+			c, _ := net.Pipe()
+			return c, nil
+		},
+	}
+
+	conn, err := d.DialContext(context.Background(), "tcp", "api.openweathermap.org:443")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	if len(networks) != 2 || networks[0] != "tcp6" || networks[1] != "tcp4" {
+		t.Errorf("unexpected dial order %v", networks)
+	}
+
+	// Refused and unreachable connections fall back too.
+	for _, errno := range []syscall.Errno{syscall.ECONNREFUSED, syscall.ENETUNREACH, syscall.EHOSTUNREACH} {
+		networks = nil
+		d.dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+			networks = append(networks, network)
+			if network == "tcp6" {
+				return nil, &net.OpError{Op: "dial", Net: network, Err: os.NewSyscallError("connect", errno)}
+			}
+			c, _ := net.Pipe()
+			return c, nil
+		}
+		conn, err := d.DialContext(context.Background(), "tcp", "api.openweathermap.org:443")
+		if err != nil {
+			t.Fatalf("%v: %v", errno, err)
+		}
+		conn.Close()
+		if len(networks) != 2 {
+			t.Errorf("expected %v to fall back, got %v", errno, networks)
+		}
+	}
+}
+
+// TestFallbackDialerSingleFamily will verify there's no retry when only one
+// family resolves.
+func TestFallbackDialerSingleFamily(t *testing.T) {
+	calls := 0
+	d := &fallbackDialer{
+		lookup: func(ctx context.Context, host string) ([]net.IPAddr, error) {
+			return []net.IPAddr{{IP: net.ParseIP("192.0.2.1")}}, nil
+		},
+		dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			calls++
+			return nil, timeoutError{}
+		},
+	}
+
+	if _, err := d.DialContext(context.Background(), "tcp", "api.openweathermap.org:443"); err == nil {
+		t.Error("expected error, got nil")
+	}
+	if calls != 1 {
+		t.Errorf("expected 1 dial, got %d", calls)
+	}
+}
+
+// TestWithDualStackFallback will verify the option installs the dialer on
+// a copy of the http client.
+func TestWithDualStackFallback(t *testing.T) {
+	hc := &http.Client{}
+	c, err := NewCurrent("c", "EN", "key", WithDualStackFallback(time.Second), WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.client == hc || c.client.Transport.(*http.Transport).DialContext == nil {
+		t.Error("expected a copy of the client with the fallback dialer")
+	}
+
+	// The transport's own dialer still makes the connections, bound to
+	// the connect timeout.
+	var deadline time.Duration
+	own := &http.Transport{DialContext: func(ctx context.Context, network, address string) (net.Conn, error) {
+		if d, ok := ctx.Deadline(); ok {
+			deadline = time.Until(d)
+		}
+		c, _ := net.Pipe()
+		return c, nil
+	}}
+	c, err = NewCurrent("c", "EN", "key", WithHttpClient(&http.Client{Transport: own}), WithDualStackFallback(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := c.client.Transport.(*http.Transport).DialContext(context.Background(), "tcp", "127.0.0.1:443")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	if deadline <= 0 || deadline > time.Second {
+		t.Errorf("expected the transport's dialer bound to the timeout, got %v", deadline)
+	}
+
+	hc = &http.Client{Transport: rewriteTransport{}}
+	if _, err := NewCurrent("c", "EN", "key", WithHttpClient(hc), WithDualStackFallback(time.Second)); err != errUnsupportedTransport {
+		t.Errorf("expected %v, got %v", errUnsupportedTransport, err)
+	}
+}
@@ -0,0 +1,177 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"sync"
+	"time"
+)
+
+// Event is implemented by every event published on a Bus. Subscribers
+// switch on the concrete type.
+type Event interface {
+	isEvent()
+}
+
+// RequestStarted is published before a request is sent.
+type RequestStarted struct {
+	Endpoint Endpoint
+	Time     time.Time
+}
+
+// RequestFinished is published once a request completed, with Err set if
+// it failed before a response was read.
+type RequestFinished struct {
+	Endpoint   Endpoint
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// DataChanged is published when a successful response body differs from
+// the previous one.
+type DataChanged struct {
+	Endpoint Endpoint
+	Checksum string
+}
+
+// RetryScheduled is published when a failed request will be retried
+// after Delay, with StatusCode or Err set to why the attempt failed.
+type RetryScheduled struct {
+	Endpoint   Endpoint
+	Attempt    int
+	Delay      time.Duration
+	StatusCode int
+	Err        error
+}
+
+// CircuitOpened is published when the breaker set with WithCircuitBreaker
+// opens after a failed request to Endpoint, failing requests for
+// Cooldown.
+type CircuitOpened struct {
+	Endpoint Endpoint
+	Cooldown time.Duration
+}
+
+// CircuitClosed is published when a trial request to Endpoint succeeded
+// and the breaker lets requests through again.
+type CircuitClosed struct {
+	Endpoint Endpoint
+}
+
+// CacheHit is published when a request is served from the cache set
+// with WithCache, under Key. Stale is set for responses past the soft TTL
+// set with WithSoftTTL, served while they are refreshed, and NotFound for
+// 404 responses cached with WithNegativeCache.
+type CacheHit struct {
+	Endpoint Endpoint
+	Key      string
+	Stale    bool
+	NotFound bool
+}
+
+// RefreshFailed is published when the background refresh of a cache
+// entry past the soft TTL set with WithSoftTTL failed, with Err a
+// *PanicError if it panicked. The stale entry is kept until its TTL.
+type RefreshFailed struct {
+	Endpoint Endpoint
+	Key      string
+	Err      error
+}
+
+// ClockSkewDetected is published when the skew between the server's clock
+// and the local one changes by more than 30 seconds, e.g. when it is first
+// detected, with Skew the server's lead over the local clock.
+type ClockSkewDetected struct {
+	Endpoint Endpoint
+	Skew     time.Duration
+}
+
+// AlertStarted is published by an AlertTracker when an alert is first
+// seen.
+type AlertStarted struct {
+	Alert OneCallAlertData
+}
+
+// AlertUpdated is published by an AlertTracker when an active alert is
+// reissued with changes, such as a new end time or description.
+type AlertUpdated struct {
+	Alert    OneCallAlertData
+	Previous OneCallAlertData
+}
+
+// AlertExpired is published by an AlertTracker when an active alert ended
+// or is no longer reported.
+type AlertExpired struct {
+	Alert OneCallAlertData
+}
+
+func (RequestStarted) isEvent()    {}
+func (RequestFinished) isEvent()   {}
+func (DataChanged) isEvent()       {}
+func (RetryScheduled) isEvent()    {}
+func (CircuitOpened) isEvent()     {}
+func (CircuitClosed) isEvent()     {}
+func (CacheHit) isEvent()          {}
+func (RefreshFailed) isEvent()     {}
+func (ClockSkewDetected) isEvent() {}
+func (AlertStarted) isEvent()      {}
+func (AlertUpdated) isEvent()      {}
+func (AlertExpired) isEvent()      {}
+
+// Bus delivers client lifecycle events to its subscribers. A Bus may be
+// shared by several clients and is safe for concurrent use. Subscribers
+// are called synchronously on the requesting goroutine, so they should
+// return quickly.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers []func(Event)
+}
+
+// NewBus returns a new event bus without subscribers.
+func NewBus() *Bus { return &Bus{} }
+
+// Subscribe registers fn to receive every event published on the bus.
+func (b *Bus) Subscribe(fn func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, fn)
+}
+
+// publish hands the event to every subscriber. Publishing on a nil bus
+// is a no-op. Subscribers are called without holding the lock, so they
+// may subscribe in turn; those join from the next event on.
+func (b *Bus) publish(e Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	subscribers := b.subscribers
+	b.mu.RUnlock()
+	for _, fn := range subscribers {
+		fn(e)
+	}
+}
+
+// WithEventBus publishes the client's lifecycle events on the given bus.
+func WithEventBus(b *Bus) Option {
+	return func(s *Settings) error {
+		if b == nil {
+			return errInvalidOption
+		}
+		s.bus = b
+		return nil
+	}
+}
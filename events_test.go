@@ -0,0 +1,74 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// TestWithEventBus will verify requests publish their lifecycle events and
+// DataChanged is only sent when the body changes.
+func TestWithEventBus(t *testing.T) {
+	temp := 1
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":1,"main":{"temp":%d}}`, temp)
+	})
+	defer ts.Close()
+
+	var events []Event
+	bus := NewBus()
+	bus.Subscribe(func(e Event) { events = append(events, e) })
+
+	c, err := NewCurrent("c", "EN", "key", WithHttpClient(hc), WithEventBus(bus))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	counts := func() (started, finished, changed int) {
+		for _, e := range events {
+			switch e := e.(type) {
+			case RequestStarted:
+				started++
+			case RequestFinished:
+				finished++
+				if e.StatusCode != http.StatusOK || e.Err != nil || e.Endpoint != EndpointCurrent {
+					t.Errorf("unexpected finish event %+v", e)
+				}
+			case DataChanged:
+				changed++
+			}
+		}
+		return
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := c.CurrentByID(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	temp = 2
+	if err := c.CurrentByID(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if s, f, ch := counts(); s != 3 || f != 3 || ch != 2 {
+		t.Errorf("expected 3 started, 3 finished and 2 changed, got %d, %d and %d", s, f, ch)
+	}
+	if _, ok := events[0].(RequestStarted); !ok {
+		t.Errorf("expected the first event to be RequestStarted, got %T", events[0])
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"sort"
+	"time"
+)
+
+// Pollutant names a component of the air pollution data.
+type Pollutant string
+
+// Pollutants reported by the air pollution API.
+const (
+	PollutantCO   Pollutant = "co"
+	PollutantNO   Pollutant = "no"
+	PollutantNO2  Pollutant = "no2"
+	PollutantO3   Pollutant = "o3"
+	PollutantSO2  Pollutant = "so2"
+	PollutantPM25 Pollutant = "pm2_5"
+	PollutantPM10 Pollutant = "pm10"
+	PollutantNH3  Pollutant = "nh3"
+)
+
+// WHOGuidelines holds the 2021 WHO air quality guideline levels in μg/m³.
+// They're 24 hour means except for ozone, whose level applies to the
+// highest 8 hour mean of the day. NO and NH3 have no guideline.
+var WHOGuidelines = map[Pollutant]float64{
+	PollutantCO:   4000,
+	PollutantNO2:  25,
+	PollutantO3:   100,
+	PollutantSO2:  40,
+	PollutantPM25: 15,
+	PollutantPM10: 45,
+}
+
+// maxSampleWeight bounds how long a single sample is taken to last, so
+// gaps in polling don't inflate the exposure.
+const maxSampleWeight = time.Hour
+
+// DailyExposure holds the exposure to each pollutant over a day.
+type DailyExposure struct {
+	Date time.Time // midnight starting the day
+	// Covered is how much of the day the samples account for.
+	Covered time.Duration
+	// Mean is the time weighted mean concentration in μg/m³.
+	Mean map[Pollutant]float64
+	// Accumulated is the concentration integrated over time in μg/m³·h.
+	Accumulated map[Pollutant]float64
+	// PeakO3 is the highest 8 hour mean ozone concentration.
+	PeakO3 float64
+	// Exceedances lists the pollutants above their WHO guideline.
+	Exceedances []Pollutant
+}
+
+// Concentrations returns the concentrations of the data by pollutant.
+func (d PollutionData) Concentrations() map[Pollutant]float64 {
+	c := d.Components
+	return map[Pollutant]float64{
+		PollutantCO:   c.Co,
+		PollutantNO:   c.No,
+		PollutantNO2:  c.No2,
+		PollutantO3:   c.O3,
+		PollutantSO2:  c.So2,
+		PollutantPM25: c.Pm25,
+		PollutantPM10: c.Pm10,
+		PollutantNH3:  c.Nh3,
+	}
+}
+
+// DailyExposures accumulates the polled air quality data into days in
+// loc, oldest first. Each sample lasts until the next one, up to an hour.
+func DailyExposures(data []PollutionData, loc *time.Location) []DailyExposure {
+	samples := append([]PollutionData(nil), data...)
+	sort.SliceStable(samples, func(i, j int) bool { return samples[i].Dt < samples[j].Dt })
+
+	var days []DailyExposure
+	var o3 []weightedSample
+	for i, s := range samples {
+		at := time.Unix(int64(s.Dt), 0).In(loc)
+		weight := maxSampleWeight
+		if i+1 < len(samples) {
+			if gap := time.Duration(samples[i+1].Dt-s.Dt) * time.Second; gap < weight {
+				weight = gap
+			}
+		}
+
+		date := time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, loc)
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(date) {
+			if len(days) > 0 {
+				days[len(days)-1].finish(o3)
+			}
+			days = append(days, DailyExposure{
+				Date:        date,
+				Mean:        make(map[Pollutant]float64),
+				Accumulated: make(map[Pollutant]float64),
+			})
+			o3 = o3[:0]
+		}
+
+		d := &days[len(days)-1]
+		d.Covered += weight
+		for p, v := range s.Concentrations() {
+			d.Accumulated[p] += v * weight.Hours()
+		}
+		o3 = append(o3, weightedSample{at: at, weight: weight, value: s.Components.O3})
+	}
+	if len(days) > 0 {
+		days[len(days)-1].finish(o3)
+	}
+	return days
+}
+
+// weightedSample is a concentration lasting for weight from at.
+type weightedSample struct {
+	at     time.Time
+	weight time.Duration
+	value  float64
+}
+
+// finish computes the means and flags the exceedances of the day.
+func (d *DailyExposure) finish(o3 []weightedSample) {
+	if d.Covered > 0 {
+		for p, v := range d.Accumulated {
+			d.Mean[p] = v / d.Covered.Hours()
+		}
+	}
+	d.PeakO3 = peakMean(o3, 8*time.Hour)
+
+	for p, limit := range WHOGuidelines {
+		v := d.Mean[p]
+		if p == PollutantO3 {
+			v = d.PeakO3
+		}
+		if v > limit {
+			d.Exceedances = append(d.Exceedances, p)
+		}
+	}
+	sort.Slice(d.Exceedances, func(i, j int) bool { return d.Exceedances[i] < d.Exceedances[j] })
+}
+
+// peakMean returns the highest time weighted mean of the samples starting
+// within a window of the given length.
+func peakMean(samples []weightedSample, window time.Duration) float64 {
+	var peak float64
+	for i, first := range samples {
+		var sum float64
+		var covered time.Duration
+		for _, s := range samples[i:] {
+			if !s.at.Before(first.at.Add(window)) {
+				break
+			}
+			sum += s.value * s.weight.Hours()
+			covered += s.weight
+		}
+		if covered > 0 && sum/covered.Hours() > peak {
+			peak = sum / covered.Hours()
+		}
+	}
+	return peak
+}
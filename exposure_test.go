@@ -0,0 +1,77 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// pollutionSample returns hourly pollution data with the given PM2.5 and
+// ozone concentrations.
+func pollutionSample(at time.Time, pm25, o3 float64) PollutionData {
+	var d PollutionData
+	d.Dt = int(at.Unix())
+	d.Components.Pm25 = pm25
+	d.Components.O3 = o3
+	return d
+}
+
+// TestDailyExposures will verify samples are split into days, weighted by
+// how long they last and checked against the WHO guidelines.
+func TestDailyExposures(t *testing.T) {
+	day := time.Date(2023, 11, 14, 0, 0, 0, 0, time.UTC)
+	var data []PollutionData
+	for h := 0; h < 24; h++ {
+		o3 := 60.0
+		if h >= 12 && h < 20 {
+			o3 = 120
+		}
+		data = append(data, pollutionSample(day.Add(time.Duration(h)*time.Hour), 10, o3))
+	}
+	// A second, unsorted day sampled every half hour, with a polling gap.
+	next := day.Add(24 * time.Hour)
+	data = append(data,
+		pollutionSample(next.Add(30*time.Minute), 30, 10),
+		pollutionSample(next, 10, 10),
+		pollutionSample(next.Add(6*time.Hour), 20, 10),
+	)
+
+	days := DailyExposures(data, time.UTC)
+	if len(days) != 2 {
+		t.Fatalf("expected 2 days, got %d", len(days))
+	}
+
+	first := days[0]
+	if !first.Date.Equal(day) || first.Covered != 24*time.Hour {
+		t.Errorf("unexpected day %v covering %v", first.Date, first.Covered)
+	}
+	if first.Accumulated[PollutantPM25] != 240 || first.Mean[PollutantPM25] != 10 {
+		t.Errorf("unexpected PM2.5 exposure %v / %v", first.Accumulated[PollutantPM25], first.Mean[PollutantPM25])
+	}
+	if first.PeakO3 != 120 || !reflect.DeepEqual(first.Exceedances, []Pollutant{PollutantO3}) {
+		t.Errorf("expected the ozone peak to exceed, got %v %v", first.PeakO3, first.Exceedances)
+	}
+
+	// Half an hour at 10, the gap capped to an hour at 30 and an hour at 20.
+	second := days[1]
+	if second.Covered != 150*time.Minute || second.Accumulated[PollutantPM25] != 55 {
+		t.Errorf("unexpected weighting %v, %v", second.Covered, second.Accumulated[PollutantPM25])
+	}
+	if !reflect.DeepEqual(second.Exceedances, []Pollutant{PollutantPM25}) {
+		t.Errorf("expected PM2.5 to exceed, got %v", second.Exceedances)
+	}
+}
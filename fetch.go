@@ -0,0 +1,213 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+var (
+	errDuplicateFetch   = errors.New("fetch already added to the group")
+	errUnknownFetch     = errors.New("fetch depends on an unknown fetch")
+	errFetchCycle       = errors.New("fetch dependencies form a cycle")
+	errDependencyFailed = errors.New("dependency failed")
+)
+
+// FetchGroup runs a set of dependent fetches, e.g. current weather
+// followed by the air pollution at the returned coordinates. Independent
+// fetches run concurrently, each one starting once its dependencies
+// succeeded. All fetches share a context which is canceled on the first
+// fatal error.
+type FetchGroup struct {
+	ctx      context.Context
+	fetches  map[string]*fetch
+	order    []string
+	err      error
+	restarts int
+}
+
+// fetch is a single call of a FetchGroup.
+type fetch struct {
+	fn    func(ctx context.Context) error
+	deps  []string
+	fatal bool
+	done  chan struct{}
+	err   error
+}
+
+// NewFetchGroup returns an empty group deriving its shared context from
+// ctx.
+func NewFetchGroup(ctx context.Context) *FetchGroup {
+	return &FetchGroup{ctx: ctx, fetches: make(map[string]*fetch)}
+}
+
+// Add declares a fatal fetch named name, run after the named dependencies
+// succeeded. Its failure cancels every other fetch of the group.
+func (g *FetchGroup) Add(name string, fn func(ctx context.Context) error, deps ...string) {
+	g.add(name, fn, deps, true)
+}
+
+// AddOptional declares a fetch whose failure only skips the fetches
+// depending on it, leaving the rest of the group running.
+func (g *FetchGroup) AddOptional(name string, fn func(ctx context.Context) error, deps ...string) {
+	g.add(name, fn, deps, false)
+}
+
+func (g *FetchGroup) add(name string, fn func(ctx context.Context) error, deps []string, fatal bool) {
+	if _, ok := g.fetches[name]; ok {
+		if g.err == nil {
+			g.err = fmt.Errorf("%s: %w", name, errDuplicateFetch)
+		}
+		return
+	}
+	g.fetches[name] = &fetch{fn: fn, deps: deps, fatal: fatal}
+	g.order = append(g.order, name)
+}
+
+// RestartOnPanic runs a fetch again, up to n times, when it panics. By
+// default, and once the restarts are used up, the panic is recovered and
+// reported as the fetch's *PanicError like any other failure.
+func (g *FetchGroup) RestartOnPanic(n int) {
+	g.restarts = n
+}
+
+// call runs the fetch, recovering panics and restarting it as allowed.
+func (g *FetchGroup) call(ctx context.Context, f *fetch) error {
+	for restarts := 0; ; restarts++ {
+		err := safely(func() error { return f.fn(ctx) })
+		var p *PanicError
+		if !errors.As(err, &p) || restarts >= g.restarts || ctx.Err() != nil {
+			return err
+		}
+	}
+}
+
+// FetchReport holds the outcome of every fetch of a group. Fetches that
+// didn't run because a dependency failed report an error wrapping the
+// dependency's name.
+type FetchReport struct {
+	Errors map[string]error
+}
+
+// Succeeded reports whether the named fetch ran without error.
+func (r *FetchReport) Succeeded(name string) bool {
+	err, ok := r.Errors[name]
+	return ok && err == nil
+}
+
+// Run executes the group and waits for every fetch to finish. The report
+// lists the result of each fetch so partial results can be used, while
+// the error is the first fatal failure, if any. An invalid group, with
+// duplicate names, unknown dependencies or cycles, returns an error
+// without running anything. Calling Run again, once the previous run
+// returned, runs every fetch of the group anew.
+func (g *FetchGroup) Run() (*FetchReport, error) {
+	if err := g.validate(); err != nil {
+		return nil, err
+	}
+	for _, f := range g.fetches {
+		f.done = make(chan struct{})
+		f.err = nil
+	}
+
+	ctx, cancel := context.WithCancel(g.ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		fatalErr error
+	)
+	for _, name := range g.order {
+		f := g.fetches[name]
+		wg.Add(1)
+		go func(name string, f *fetch) {
+			defer wg.Done()
+			defer close(f.done)
+
+			for _, d := range f.deps {
+				dep := g.fetches[d]
+				<-dep.done
+				if dep.err != nil {
+					f.err = fmt.Errorf("%s: %w", d, errDependencyFailed)
+					return
+				}
+			}
+			if err := ctx.Err(); err != nil {
+				f.err = err
+				return
+			}
+			if f.err = g.call(ctx, f); f.err != nil && f.fatal {
+				once.Do(func() {
+					fatalErr = fmt.Errorf("%s: %w", name, f.err)
+					cancel()
+				})
+			}
+		}(name, f)
+	}
+	wg.Wait()
+
+	report := &FetchReport{Errors: make(map[string]error, len(g.order))}
+	for _, name := range g.order {
+		report.Errors[name] = g.fetches[name].err
+	}
+	return report, fatalErr
+}
+
+// validate checks no name was added twice, every dependency exists and
+// that they don't form a cycle.
+func (g *FetchGroup) validate() error {
+	if g.err != nil {
+		return g.err
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int, len(g.fetches))
+
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("%s: %w", name, errFetchCycle)
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		for _, d := range g.fetches[name].deps {
+			if _, ok := g.fetches[d]; !ok {
+				return fmt.Errorf("%s: %w %s", name, errUnknownFetch, d)
+			}
+			if err := visit(d); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+
+	for _, name := range g.order {
+		if err := visit(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestFetchGroup will verify dependent fetches run in order with the
+// results of the fetches they depend on.
+func TestFetchGroup(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "air_pollution") {
+			fmt.Fprint(w, `{"coord":{"lon":-0.13,"lat":51.51},"list":[{"main":{"aqi":2}}]}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":1,"name":"London","coord":{"lon":-0.13,"lat":51.51}}`)
+	})
+	defer ts.Close()
+
+	c, err := NewCurrent("c", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewPollution("key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewFetchGroup(context.Background())
+	g.Add("current", func(ctx context.Context) error { return c.CurrentByID(1) })
+	g.AddOptional("pollution", func(ctx context.Context) error {
+		return p.PollutionByParams(&PollutionParameters{Location: c.GeoPos, Datetime: "current"})
+	}, "current")
+
+	report, err := g.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Succeeded("current") || !report.Succeeded("pollution") {
+		t.Errorf("expected both fetches to succeed, got %v", report.Errors)
+	}
+	if len(p.List) != 1 || p.List[0].Main.Aqi != 2 {
+		t.Errorf("expected pollution data, got %+v", p.List)
+	}
+}
+
+// TestFetchGroupFailures will verify optional failures only skip their
+// dependents while fatal ones cancel the rest of the group.
+func TestFetchGroupFailures(t *testing.T) {
+	errLookup := errors.New("lookup failed")
+
+	g := NewFetchGroup(context.Background())
+	g.AddOptional("geocode", func(ctx context.Context) error { return errLookup })
+	g.Add("current", func(ctx context.Context) error { return nil }, "geocode")
+	g.Add("uv", func(ctx context.Context) error { return nil })
+
+	report, err := g.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Errors["geocode"] != errLookup || !errors.Is(report.Errors["current"], errDependencyFailed) {
+		t.Errorf("unexpected errors %v", report.Errors)
+	}
+	if !report.Succeeded("uv") {
+		t.Errorf("expected independent fetch to succeed, got %v", report.Errors["uv"])
+	}
+
+	// pollution is canceled whether it started before current failed or
+	// not
+	g = NewFetchGroup(context.Background())
+	g.Add("current", func(ctx context.Context) error { return errLookup })
+	g.Add("pollution", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	report, err = g.Run()
+	if !errors.Is(err, errLookup) {
+		t.Errorf("expected the fatal error, got %v", err)
+	}
+	if report.Errors["pollution"] != context.Canceled {
+		t.Errorf("expected pollution to be canceled, got %v", report.Errors["pollution"])
+	}
+}
+
+// TestFetchGroupRerun will verify a group can be run again and that each
+// run starts from a clean state.
+func TestFetchGroupRerun(t *testing.T) {
+	errFirst := errors.New("first run failed")
+	runs := 0
+
+	g := NewFetchGroup(context.Background())
+	g.AddOptional("current", func(ctx context.Context) error {
+		runs++
+		if runs == 1 {
+			return errFirst
+		}
+		return nil
+	})
+	g.Add("forecast", func(ctx context.Context) error { return nil }, "current")
+
+	report, err := g.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !errors.Is(report.Errors["forecast"], errDependencyFailed) {
+		t.Errorf("expected forecast to be skipped, got %v", report.Errors["forecast"])
+	}
+
+	if report, err = g.Run(); err != nil {
+		t.Fatal(err)
+	}
+	if !report.Succeeded("current") || !report.Succeeded("forecast") {
+		t.Errorf("expected both fetches to succeed on the second run, got %v", report.Errors)
+	}
+}
+
+// TestFetchGroupValidate will verify invalid groups aren't run.
+func TestFetchGroupValidate(t *testing.T) {
+	noop := func(ctx context.Context) error { return nil }
+
+	cases := map[string]func(g *FetchGroup){
+		"unknown": func(g *FetchGroup) { g.Add("a", noop, "b") },
+		"cycle": func(g *FetchGroup) {
+			g.Add("a", noop, "b")
+			g.Add("b", noop, "a")
+		},
+		"duplicate": func(g *FetchGroup) {
+			g.Add("a", noop)
+			g.Add("a", noop)
+		},
+	}
+	expected := map[string]error{"unknown": errUnknownFetch, "cycle": errFetchCycle, "duplicate": errDuplicateFetch}
+
+	for name, build := range cases {
+		g := NewFetchGroup(context.Background())
+		build(g)
+		if report, err := g.Run(); !errors.Is(err, expected[name]) || report != nil {
+			t.Errorf("%s: expected %v, got %v", name, expected[name], err)
+		}
+	}
+}
+
+// TestFetchGroupPanics will verify a panicking fetch is reported as an
+// error, restarted when allowed, and doesn't take the group down.
+func TestFetchGroupPanics(t *testing.T) {
+	errPayload := errors.New("bad payload")
+
+	g := NewFetchGroup(context.Background())
+	g.AddOptional("bad", func(ctx context.Context) error { panic(errPayload) })
+	g.Add("good", func(ctx context.Context) error { return nil })
+	report, err := g.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var p *PanicError
+	if !errors.As(report.Errors["bad"], &p) || !errors.Is(report.Errors["bad"], errPayload) || len(p.Stack) == 0 {
+		t.Errorf("expected the panic as an error, got %v", report.Errors["bad"])
+	}
+	if !report.Succeeded("good") {
+		t.Error("expected the other fetch to succeed")
+	}
+
+	calls := 0
+	g = NewFetchGroup(context.Background())
+	g.RestartOnPanic(2)
+	g.Add("flaky", func(ctx context.Context) error {
+		calls++
+		if calls < 3 {
+			panic("index out of range")
+		}
+		return nil
+	})
+	if _, err := g.Run(); err != nil || calls != 3 {
+		t.Errorf("expected success after 2 restarts, got %v after %d calls", err, calls)
+	}
+
+	calls = 0
+	g = NewFetchGroup(context.Background())
+	g.RestartOnPanic(1)
+	g.Add("broken", func(ctx context.Context) error {
+		calls++
+		panic("index out of range")
+	})
+	if _, err := g.Run(); !errors.As(err, &p) || p.Value != "index out of range" || calls != 2 {
+		t.Errorf("expected the panic once restarts ran out, got %v after %d calls", err, calls)
+	}
+}
@@ -15,11 +15,13 @@
 package openweathermap
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/url"
 	"strconv"
-	"strings"
 )
 
 // ForecastSys area population
@@ -44,6 +46,9 @@ type City struct {
 	Coord      Coordinates `json:"coord"`
 	Country    string      `json:"country"`
 	Population int         `json:"population"`
+	Timezone   int         `json:"timezone"`
+	Sunrise    int         `json:"sunrise"`
+	Sunset     int         `json:"sunset"`
 	Sys        ForecastSys `json:"sys"`
 }
 
@@ -60,9 +65,10 @@ type ForecastWeatherJson interface {
 }
 
 type ForecastWeatherData struct {
-	Unit    string
+	Unit    Unit
 	Lang    string
 	Key     string
+	Schema  Schema // shape of the last decoded response
 	baseURL string
 	*Settings
 	ForecastWeatherJson
@@ -71,105 +77,153 @@ type ForecastWeatherData struct {
 // NewForecast returns a new HistoricalWeatherData pointer with
 // the supplied arguments.
 func NewForecast(forecastType, unit, lang, key string, options ...Option) (*ForecastWeatherData, error) {
-	unitChoice := strings.ToUpper(unit)
-	langChoice := strings.ToUpper(lang)
-
 	if forecastType != "16" && forecastType != "5" {
 		return nil, errForecastUnavailable
 	}
-
-	if !ValidDataUnit(unitChoice) {
-		return nil, errUnitUnavailable
-	}
-
-	if !ValidLangCode(langChoice) {
-		return nil, errLangUnavailable
-	}
-
-	settings := NewSettings()
-	if err := setOptions(settings, options); err != nil {
+	c, err := newClient(unit, lang, key, options)
+	if err != nil {
 		return nil, err
 	}
+	if forecastType == "16" {
+		return c.Forecast16(), nil
+	}
+	return c.Forecast5(), nil
+}
 
-	var err error
-	k, err := setKey(key)
+// decode detects the shape of the response and decodes it into the
+// matching forecast type, so a daily payload isn't silently misparsed as a
+// 3 hourly one or the other way around.
+func (f *ForecastWeatherData) decode(r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
 	if err != nil {
-		return nil, err
-	}
-	forecastData := ForecastWeatherData{
-		Unit:     DataUnits[unitChoice],
-		Lang:     langChoice,
-		Key:      k,
-		Settings: settings,
+		return err
 	}
 
-	if forecastType == "16" {
-		forecastData.baseURL = forecast16Base
-		forecastData.ForecastWeatherJson = &Forecast16WeatherData{}
-	} else {
-		forecastData.baseURL = forecast5Base
-		forecastData.ForecastWeatherJson = &Forecast5WeatherData{}
+	f.Schema = DetectSchema(b)
+	switch f.Schema {
+	case SchemaForecast5:
+		if _, ok := f.ForecastWeatherJson.(*Forecast5WeatherData); !ok {
+			f.ForecastWeatherJson = &Forecast5WeatherData{}
+		}
+	case SchemaForecast16:
+		if _, ok := f.ForecastWeatherJson.(*Forecast16WeatherData); !ok {
+			f.ForecastWeatherJson = &Forecast16WeatherData{}
+		}
 	}
 
-	return &forecastData, nil
+	if err := f.ForecastWeatherJson.Decode(bytes.NewReader(b)); err != nil {
+		return err
+	}
+	if f5, ok := f.ForecastWeatherJson.(*Forecast5WeatherData); ok && f.precise {
+		precise, err := preciseEntries(b)
+		if err != nil {
+			return err
+		}
+		for i := range f5.List {
+			if i < len(precise) {
+				f5.List[i].Precise = precise[i]
+			}
+		}
+	}
+	return f.postDecode(f)
 }
 
 // DailyByName will provide a forecast for the location given for the
 // number of days given.
 func (f *ForecastWeatherData) DailyByName(location string, days int) error {
-	response, err := f.client.Get(fmt.Sprintf(f.baseURL, f.Key, fmt.Sprintf("%s=%s", "q", url.QueryEscape(location)), f.Unit, f.Lang, days))
+	return f.DailyByNameCtx(context.Background(), location, days)
+}
+
+// DailyByNameCtx is like DailyByName but the request is bound to ctx,
+// which cancels it or sets its deadline.
+func (f *ForecastWeatherData) DailyByNameCtx(ctx context.Context, location string, days int) error {
+	response, err := f.getByName(ctx, EndpointForecast, location, func(location string) string {
+		return fmt.Sprintf(f.baseURL, f.Key, fmt.Sprintf("%s=%s", "q", url.QueryEscape(location)), f.Unit, f.Lang, days)
+	})
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	return f.ForecastWeatherJson.Decode(response.Body)
+	return f.decode(response.Body)
 }
 
 // DailyByCoordinates will provide a forecast for the coordinates ID give
 // for the number of days given.
 func (f *ForecastWeatherData) DailyByCoordinates(location *Coordinates, days int) error {
-	response, err := f.client.Get(fmt.Sprintf(f.baseURL, f.Key, fmt.Sprintf("lat=%f&lon=%f", location.Latitude, location.Longitude), f.Unit, f.Lang, days))
+	return f.DailyByCoordinatesCtx(context.Background(), location, days)
+}
+
+// DailyByCoordinatesCtx is like DailyByCoordinates but the request is
+// bound to ctx, which cancels it or sets its deadline.
+func (f *ForecastWeatherData) DailyByCoordinatesCtx(ctx context.Context, location *Coordinates, days int) error {
+	response, err := f.get(ctx, EndpointForecast, fmt.Sprintf(f.baseURL, f.Key, fmt.Sprintf("lat=%f&lon=%f", location.Latitude, location.Longitude), f.Unit, f.Lang, days))
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	return f.ForecastWeatherJson.Decode(response.Body)
+	return f.decode(response.Body)
 }
 
 // DailyByID will provide a forecast for the location ID give for the
 // number of days given.
 func (f *ForecastWeatherData) DailyByID(id, days int) error {
-	response, err := f.client.Get(fmt.Sprintf(f.baseURL, f.Key, fmt.Sprintf("%s=%s", "id", strconv.Itoa(id)), f.Unit, f.Lang, days))
+	return f.DailyByIDCtx(context.Background(), id, days)
+}
+
+// DailyByIDCtx is like DailyByID but the request is bound to ctx, which
+// cancels it or sets its deadline.
+func (f *ForecastWeatherData) DailyByIDCtx(ctx context.Context, id, days int) error {
+	response, err := f.get(ctx, EndpointForecast, fmt.Sprintf(f.baseURL, f.Key, fmt.Sprintf("%s=%s", "id", strconv.Itoa(id)), f.Unit, f.Lang, days))
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	return f.ForecastWeatherJson.Decode(response.Body)
+	return f.decode(response.Body)
 }
 
 // DailyByZip will provide a forecast for the provided zip code.
 //
 // Deprecated: use DailyByZipcode instead.
 func (f *ForecastWeatherData) DailyByZip(zip int, countryCode string, days int) error {
-	response, err := f.client.Get(fmt.Sprintf(f.baseURL, f.Key, fmt.Sprintf("zip=%05d,%s", zip, countryCode), f.Unit, f.Lang, days))
+	return f.DailyByZipCtx(context.Background(), zip, countryCode, days)
+}
+
+// DailyByZipCtx is like DailyByZip but the request is bound to ctx,
+// which cancels it or sets its deadline.
+//
+// Deprecated: use DailyByZipcodeCtx instead.
+func (f *ForecastWeatherData) DailyByZipCtx(ctx context.Context, zip int, countryCode string, days int) error {
+	if !ValidLocation(countryCode) {
+		return errInvalidLocation
+	}
+	response, err := f.get(ctx, EndpointForecast, fmt.Sprintf(f.baseURL, f.Key, fmt.Sprintf("zip=%05d,%s", zip, url.QueryEscape(countryCode)), f.Unit, f.Lang, days))
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	return f.ForecastWeatherJson.Decode(response.Body)
+	return f.decode(response.Body)
 }
 
 // DailyByZipcode will provide a forecast for the provided zip code.
 func (f *ForecastWeatherData) DailyByZipcode(zip string, countryCode string, days int) error {
-	response, err := f.client.Get(fmt.Sprintf(f.baseURL, f.Key, fmt.Sprintf("zip=%s,%s", zip, countryCode), f.Unit, f.Lang, days))
+	return f.DailyByZipcodeCtx(context.Background(), zip, countryCode, days)
+}
+
+// DailyByZipcodeCtx is like DailyByZipcode but the request is bound to
+// ctx, which cancels it or sets its deadline.
+func (f *ForecastWeatherData) DailyByZipcodeCtx(ctx context.Context, zip string, countryCode string, days int) error {
+	if !ValidLocation(zip) || !ValidLocation(countryCode) {
+		return errInvalidLocation
+	}
+	response, err := f.get(ctx, EndpointForecast, fmt.Sprintf(f.baseURL, f.Key, fmt.Sprintf("zip=%s,%s", url.QueryEscape(zip), url.QueryEscape(countryCode)), f.Unit, f.Lang, days))
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	return f.ForecastWeatherJson.Decode(response.Body)
+	return f.decode(response.Body)
 }
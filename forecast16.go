@@ -3,20 +3,36 @@ package openweathermap
 import (
 	"encoding/json"
 	"io"
+	"strconv"
+	"strings"
 )
 
+// FeelsLikeTemperature holds the perceived temperature at each part of
+// the day.
+type FeelsLikeTemperature struct {
+	Day   float64 `json:"day"`
+	Night float64 `json:"night"`
+	Eve   float64 `json:"eve"`
+	Morn  float64 `json:"morn"`
+}
+
 // Forecast16WeatherList holds specific query data
 type Forecast16WeatherList struct {
-	Dt       int         `json:"dt"`
-	Temp     Temperature `json:"temp"`
-	Pressure float64     `json:"pressure"`
-	Humidity int         `json:"humidity"`
-	Weather  []Weather   `json:"weather"`
-	Speed    float64     `json:"speed"`
-	Deg      int         `json:"deg"`
-	Clouds   int         `json:"clouds"`
-	Snow     float64     `json:"snow"`
-	Rain     float64     `json:"rain"`
+	Dt        int                  `json:"dt"`
+	Sunrise   int                  `json:"sunrise"`
+	Sunset    int                  `json:"sunset"`
+	Temp      Temperature          `json:"temp"`
+	FeelsLike FeelsLikeTemperature `json:"feels_like"`
+	Pressure  float64              `json:"pressure"`
+	Humidity  int                  `json:"humidity"`
+	Weather   []Weather            `json:"weather"`
+	Speed     float64              `json:"speed"`
+	Deg       int                  `json:"deg"`
+	Gust      float64              `json:"gust"`
+	Clouds    int                  `json:"clouds"`
+	Pop       float64              `json:"pop"`
+	Snow      float64              `json:"snow"`
+	Rain      float64              `json:"rain"`
 }
 
 // Forecast16WeatherData will hold returned data from queries
@@ -28,6 +44,37 @@ type Forecast16WeatherData struct {
 	List    []Forecast16WeatherList `json:"list"`
 }
 
+// UnmarshalJSON decodes the forecast. The API sends cod as a string and
+// message as a number on success, and the other way around on errors, so
+// both forms are accepted.
+func (f *Forecast16WeatherData) UnmarshalJSON(b []byte) error {
+	type plain Forecast16WeatherData
+	aux := struct {
+		*plain
+		COD     json.RawMessage `json:"cod"`
+		Message json.RawMessage `json:"message"`
+	}{plain: (*plain)(f)}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+
+	if len(aux.COD) > 0 {
+		cod, err := strconv.Atoi(strings.Trim(string(aux.COD), `"`))
+		if err != nil {
+			return err
+		}
+		f.COD = cod
+	}
+	if len(aux.Message) > 0 {
+		var message string
+		if json.Unmarshal(aux.Message, &message) != nil {
+			message = string(aux.Message)
+		}
+		f.Message = message
+	}
+	return nil
+}
+
 func (f *Forecast16WeatherData) Decode(r io.Reader) error {
 	if err := json.NewDecoder(r).Decode(&f); err != nil {
 		return err
@@ -11,26 +11,40 @@ type DtTxt struct {
 	time.Time
 }
 
+// dtTxtLayout is the layout of the dt_txt forecast field.
+const dtTxtLayout = "2006-01-02 15:04:05"
+
 func (dt *DtTxt) UnmarshalJSON(b []byte) error {
-	t, err := time.Parse("2006-01-02 15:04:05", strings.Trim(string(b), "\""))
+	t, err := time.Parse(dtTxtLayout, strings.Trim(string(b), "\""))
 	dt.Time = t
 	return err
 }
 
+// MarshalJSON encodes the time in the layout it was received in.
 func (t *DtTxt) MarshalJSON() ([]byte, error) {
-	return json.Marshal(t)
+	return json.Marshal(t.Format(dtTxtLayout))
 }
 
 // Forecast5WeatherList holds specific query data
 type Forecast5WeatherList struct {
-	Dt      int       `json:"dt"`
-	Main    Main      `json:"main"`
-	Weather []Weather `json:"weather"`
-	Clouds  Clouds    `json:"clouds"`
-	Wind    Wind      `json:"wind"`
-	Rain    Rain      `json:"rain"`
-	Snow    Snow      `json:"snow"`
-	DtTxt   DtTxt     `json:"dt_txt"`
+	Dt         int              `json:"dt"`
+	Main       Main             `json:"main"`
+	Weather    []Weather        `json:"weather"`
+	Clouds     Clouds           `json:"clouds"`
+	Wind       Wind             `json:"wind"`
+	Visibility int              `json:"visibility"`
+	Pop        float64          `json:"pop"`
+	Rain       Rain             `json:"rain"`
+	Snow       Snow             `json:"snow"`
+	Sys        Forecast5ListSys `json:"sys"`
+	DtTxt      DtTxt            `json:"dt_txt"`
+	Precise    *PreciseValues   `json:"-"` // with WithPreciseNumbers
+}
+
+// Forecast5ListSys holds the part of the day of a forecast entry, "d" for
+// day or "n" for night.
+type Forecast5ListSys struct {
+	Pod string `json:"pod"`
 }
 
 // Forecast5WeatherData will hold returned data from queries
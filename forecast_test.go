@@ -15,6 +15,7 @@
 package openweathermap
 
 import (
+	"encoding/json"
 	"net/http"
 	"os"
 	"reflect"
@@ -178,3 +179,68 @@ func TestDailyByID(t *testing.T) {
 		}
 	}
 }
+
+// TestForecast5Fields will verify the 5 day forecast decodes the
+// precipitation chance, visibility, part of day and city metadata.
+func TestForecast5Fields(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(readFixture(t, "forecast5.json"))
+	})
+	defer ts.Close()
+
+	f, err := NewForecast("5", "c", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.DailyByID(2643743, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	data := f.ForecastWeatherJson.(*Forecast5WeatherData)
+	first := data.List[0]
+	if first.Pop != 0.42 || first.Visibility != 10000 || first.Sys.Pod != "d" || first.Main.TempKf != -0.45 {
+		t.Errorf("unexpected entry %+v", first)
+	}
+	if data.City.Timezone != 3600 || data.City.Sunrise != 1696226103 || data.City.Sunset != 1696268044 {
+		t.Errorf("unexpected city %+v", data.City)
+	}
+	if _, offset := data.Slots()[0].Start.Zone(); offset != 3600 {
+		t.Errorf("expected slots in the city's timezone, got offset %d", offset)
+	}
+}
+
+// TestForecast16Fields will verify the 16 day forecast decodes a real
+// payload, with its string cod, and passes the number of days as cnt.
+func TestForecast16Fields(t *testing.T) {
+	var cnt string
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		cnt = r.URL.Query().Get("cnt")
+		w.Write(readFixture(t, "forecast16.json"))
+	})
+	defer ts.Close()
+
+	f, err := NewForecast("16", "c", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.DailyByID(2643743, 2); err != nil {
+		t.Fatal(err)
+	}
+	if cnt != "2" {
+		t.Errorf("expected cnt=2, got %s", cnt)
+	}
+
+	data := f.ForecastWeatherJson.(*Forecast16WeatherData)
+	if data.COD != 200 || data.Message != "0.0582" || len(data.List) != 2 {
+		t.Fatalf("unexpected forecast %+v", data)
+	}
+	day := data.List[0]
+	if day.Temp.Morn != 11.5 || day.FeelsLike.Night != 11.6 || day.Pop != 0.62 || day.Gust != 10.6 || day.Sunrise != 1696226103 {
+		t.Errorf("unexpected day %+v", day)
+	}
+
+	var failed Forecast16WeatherData
+	if err := json.Unmarshal([]byte(`{"cod":401,"message":"Invalid API key"}`), &failed); err != nil || failed.COD != 401 || failed.Message != "Invalid API key" {
+		t.Errorf("unexpected error payload decoding %+v: %v", failed, err)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FreshnessStatus reports how old the data of a location is compared to
+// its SLA. Updated is zero if the location was never refreshed, which
+// counts as a violation.
+type FreshnessStatus struct {
+	Location string        `json:"location"`
+	MaxAge   time.Duration `json:"max_age"`
+	Updated  time.Time     `json:"updated"`
+	Age      time.Duration `json:"age"`
+	Violated bool          `json:"violated"`
+}
+
+// Freshness tracks when saved locations were last refreshed against the
+// freshness SLA declared for each, e.g. data at most 15 minutes old, for
+// operational dashboards. It's safe for concurrent use.
+type Freshness struct {
+	mu        sync.Mutex
+	locations map[string]*FreshnessStatus
+	skew      time.Duration
+	now       func() time.Time
+}
+
+// NewFreshness returns a tracker without locations.
+func NewFreshness() *Freshness {
+	return &Freshness{locations: make(map[string]*FreshnessStatus), now: time.Now}
+}
+
+// Declare sets the SLA of the location, keeping when it was last
+// refreshed.
+func (f *Freshness) Declare(location string, maxAge time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s, ok := f.locations[location]; ok {
+		s.MaxAge = maxAge
+		return
+	}
+	f.locations[location] = &FreshnessStatus{Location: location, MaxAge: maxAge}
+}
+
+// Observe records that the location's data is from at, e.g. the time of
+// a result's Dt or of a successful request. Older times than the one
+// recorded are ignored, as are undeclared locations.
+func (f *Freshness) Observe(location string, at time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if s, ok := f.locations[location]; ok && at.After(s.Updated) {
+		s.Updated = at
+	}
+}
+
+// Subscribe marks the location refreshed whenever a request published on
+// the bus succeeds, so a bus per location, e.g. shared by the location's
+// client, keeps it up to date. Clock skew detected on the bus is taken
+// into account from then on, so the times of the API's data can be
+// observed as they are.
+func (f *Freshness) Subscribe(b *Bus, location string) {
+	b.Subscribe(func(e Event) {
+		switch e := e.(type) {
+		case RequestFinished:
+			if e.Err == nil && e.StatusCode == http.StatusOK {
+				f.Observe(location, f.serverTime(f.now()))
+			}
+		case ClockSkewDetected:
+			f.mu.Lock()
+			f.skew = e.Skew
+			f.mu.Unlock()
+		}
+	})
+}
+
+// serverTime converts a local time to the server's clock.
+func (f *Freshness) serverTime(t time.Time) time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return t.Add(f.skew)
+}
+
+// Report returns the status of every declared location at local time
+// now, sorted by location.
+func (f *Freshness) Report(now time.Time) []FreshnessStatus {
+	f.mu.Lock()
+	now = now.Add(f.skew)
+	report := make([]FreshnessStatus, 0, len(f.locations))
+	for _, s := range f.locations {
+		status := *s
+		if !status.Updated.IsZero() {
+			status.Age = now.Sub(status.Updated)
+		}
+		status.Violated = status.Updated.IsZero() || status.Age > status.MaxAge
+		report = append(report, status)
+	}
+	f.mu.Unlock()
+
+	sort.Slice(report, func(i, j int) bool { return report[i].Location < report[j].Location })
+	return report
+}
+
+// Violations returns the locations violating their SLA at now, never
+// refreshed ones first and then the most overdue.
+func (f *Freshness) Violations(now time.Time) []FreshnessStatus {
+	var violations []FreshnessStatus
+	for _, s := range f.Report(now) {
+		if s.Violated {
+			violations = append(violations, s)
+		}
+	}
+	overdue := func(s FreshnessStatus) time.Duration {
+		if s.Updated.IsZero() {
+			return 1<<63 - 1
+		}
+		return s.Age - s.MaxAge
+	}
+	sort.SliceStable(violations, func(i, j int) bool { return overdue(violations[i]) > overdue(violations[j]) })
+	return violations
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestFreshness will verify locations are reported against their SLA,
+// most overdue first.
+func TestFreshness(t *testing.T) {
+	now := time.Date(2023, 11, 16, 12, 0, 0, 0, time.UTC)
+	f := NewFreshness()
+	f.Declare("oslo", 15*time.Minute)
+	f.Declare("paris", 15*time.Minute)
+	f.Declare("rome", time.Hour)
+	f.Declare("lima", 10*time.Minute)
+
+	f.Observe("oslo", now.Add(-20*time.Minute))
+	f.Observe("oslo", now.Add(-40*time.Minute))
+	f.Observe("paris", now.Add(-time.Minute))
+	f.Observe("rome", now.Add(-90*time.Minute))
+	f.Observe("nowhere", now)
+
+	report := f.Report(now)
+	if len(report) != 4 || report[1].Location != "oslo" || report[1].Age != 20*time.Minute || !report[1].Violated {
+		t.Errorf("unexpected report %+v", report)
+	}
+	var got []string
+	for _, v := range f.Violations(now) {
+		got = append(got, v.Location)
+	}
+	if fmt.Sprint(got) != "[lima rome oslo]" {
+		t.Errorf("expected lima, rome and oslo in violation, got %v", got)
+	}
+
+	f.Declare("oslo", time.Hour)
+	if v := f.Violations(now); len(v) != 2 {
+		t.Errorf("expected a looser SLA to clear oslo, got %+v", v)
+	}
+}
+
+// TestFreshnessSubscribe will verify successful requests published on a
+// location's bus refresh it.
+func TestFreshnessSubscribe(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"name":"Oslo"}`)
+	})
+	defer ts.Close()
+
+	now := time.Unix(1700000000, 0)
+	f := NewFreshness()
+	f.now = func() time.Time { return now }
+	f.Declare("oslo", 15*time.Minute)
+	bus := NewBus()
+	f.Subscribe(bus, "oslo")
+
+	c, err := NewCurrent("C", "EN", "key", WithHttpClient(hc), WithEventBus(bus))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CurrentByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if v := f.Violations(now.Add(10 * time.Minute)); len(v) != 0 {
+		t.Errorf("expected oslo to be fresh, got %+v", v)
+	}
+}
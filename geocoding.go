@@ -0,0 +1,135 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// GeoLocation is a place found by the geocoding API.
+type GeoLocation struct {
+	Name       string            `json:"name"`
+	LocalNames map[string]string `json:"local_names,omitempty"` // keyed by ISO 639 language code
+	Latitude   float64           `json:"lat"`
+	Longitude  float64           `json:"lon"`
+	Country    string            `json:"country"`
+	State      string            `json:"state,omitempty"`
+}
+
+// Coordinates returns the coordinates of the location, ready to be used
+// with the other request methods.
+func (l GeoLocation) Coordinates() *Coordinates {
+	return &Coordinates{Longitude: l.Longitude, Latitude: l.Latitude}
+}
+
+// LocalName returns the name of the location in the given language,
+// falling back to its default name.
+func (l GeoLocation) LocalName(lang string) string {
+	if n, ok := l.LocalNames[strings.ToLower(lang)]; ok {
+		return n
+	}
+	return l.Name
+}
+
+// Geocoding resolves place names to coordinates and back.
+type Geocoding struct {
+	List []GeoLocation
+	Key  string
+	*Settings
+}
+
+// NewGeocoding creates a new reference to Geocoding
+func NewGeocoding(key string, options ...Option) (*Geocoding, error) {
+	c, err := NewClient(key, options...)
+	if err != nil {
+		return nil, err
+	}
+	return c.Geocoding(), nil
+}
+
+// GeocodeByName returns up to limit locations matching the city, whose
+// state (US only) and country code may be left empty to widen the search.
+// A limit of 0 leaves it to the API, which returns a single location.
+func (g *Geocoding) GeocodeByName(city, state, country string, limit int) ([]GeoLocation, error) {
+	return g.GeocodeByNameCtx(context.Background(), city, state, country, limit)
+}
+
+// GeocodeByNameCtx is like GeocodeByName but the request is bound to ctx,
+// which cancels it or sets its deadline.
+func (g *Geocoding) GeocodeByNameCtx(ctx context.Context, city, state, country string, limit int) ([]GeoLocation, error) {
+	parts := []string{city}
+	if state != "" {
+		parts = append(parts, state)
+	}
+	if country != "" {
+		parts = append(parts, country)
+	}
+	q := strings.Join(parts, ",")
+	if !ValidLocation(q) {
+		return nil, errInvalidLocation
+	}
+	if q = NormalizeLocation(q); q == "" {
+		return nil, errInvalidLocation
+	}
+
+	v := url.Values{"q": {q}, "appid": {g.Key}}
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+	return g.fetch(ctx, fmt.Sprintf(geoDirectURL, v.Encode()))
+}
+
+// ReverseGeocode returns up to limit named locations near the coordinates.
+func (g *Geocoding) ReverseGeocode(lat, lon float64, limit int) ([]GeoLocation, error) {
+	return g.ReverseGeocodeCtx(context.Background(), lat, lon, limit)
+}
+
+// ReverseGeocodeCtx is like ReverseGeocode but the request is bound to
+// ctx, which cancels it or sets its deadline.
+func (g *Geocoding) ReverseGeocodeCtx(ctx context.Context, lat, lon float64, limit int) ([]GeoLocation, error) {
+	v := url.Values{
+		"lat":   {strconv.FormatFloat(lat, 'f', -1, 64)},
+		"lon":   {strconv.FormatFloat(lon, 'f', -1, 64)},
+		"appid": {g.Key},
+	}
+	if limit > 0 {
+		v.Set("limit", strconv.Itoa(limit))
+	}
+	return g.fetch(ctx, fmt.Sprintf(geoReverseURL, v.Encode()))
+}
+
+// fetch requests the url and decodes the locations into g.List.
+func (g *Geocoding) fetch(ctx context.Context, url string) ([]GeoLocation, error) {
+	response, err := g.get(ctx, EndpointGeocoding, url)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	g.List = nil
+	if err = json.NewDecoder(response.Body).Decode(&g.List); err != nil {
+		return nil, err
+	}
+
+	if err := g.postDecode(g); err != nil {
+		return nil, err
+	}
+	return g.List, nil
+}
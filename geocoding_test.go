@@ -0,0 +1,91 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+const geocodingPayload = `[{"name":"London","local_names":{"en":"London","fr":"Londres"},"lat":51.5073219,"lon":-0.1276474,"country":"GB","state":"England"},{"name":"London","lat":42.9832406,"lon":-81.243372,"country":"CA","state":"Ontario"}]`
+
+// TestGeocodeByName will verify the query is built from the given parts and
+// every candidate is returned.
+func TestGeocodeByName(t *testing.T) {
+	var query string
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/geo/1.0/direct" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		query = r.URL.Query().Get("q") + "/" + r.URL.Query().Get("limit")
+		w.Write([]byte(geocodingPayload))
+	})
+	defer ts.Close()
+
+	g, err := NewGeocoding("key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	locations, err := g.GeocodeByName(" London ", "", "GB", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "London,GB/5" {
+		t.Errorf("unexpected query %q", query)
+	}
+	if len(locations) != 2 || locations[1].State != "Ontario" {
+		t.Fatalf("unexpected locations %+v", locations)
+	}
+	if locations[0].LocalName("FR") != "Londres" || locations[1].LocalName("fr") != "London" {
+		t.Error("unexpected local names")
+	}
+	if c := locations[0].Coordinates(); c.Latitude != 51.5073219 {
+		t.Errorf("unexpected coordinates %+v", c)
+	}
+
+	if _, err := g.GeocodeByName("London\n", "", "", 0); err != errInvalidLocation {
+		t.Errorf("expected %v, got %v", errInvalidLocation, err)
+	}
+}
+
+// TestReverseGeocode will verify the coordinates are sent and an
+// unauthorized key is reported.
+func TestReverseGeocode(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/geo/1.0/reverse" || r.URL.Query().Get("lat") != "51.5" || r.URL.Query().Get("limit") != "" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		if r.URL.Query().Get("appid") == "bad" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(geocodingPayload))
+	})
+	defer ts.Close()
+
+	g, err := NewGeocoding("key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if locations, err := g.ReverseGeocode(51.5, -0.12, 0); err != nil || len(locations) != 2 {
+		t.Fatalf("unexpected result %v, %v", locations, err)
+	}
+
+	g.Key = "bad"
+	if _, err := g.ReverseGeocode(51.5, -0.12, 0); !errors.Is(err, errInvalidKey) {
+		t.Errorf("expected %v, got %v", errInvalidKey, err)
+	}
+}
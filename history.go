@@ -15,11 +15,12 @@
 package openweathermap
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
+	"io"
+	"io/ioutil"
 	"net/url"
-	"strings"
 )
 
 // HistoricalParameters struct holds the (optional) fields to be
@@ -45,12 +46,13 @@ type Snow struct {
 // WeatherHistory struct contains aggregate fields from the above
 // structs.
 type WeatherHistory struct {
-	Main    Main      `json:"main"`
-	Wind    Wind      `json:"wind"`
-	Clouds  Clouds    `json:"clouds"`
-	Weather []Weather `json:"weather"`
-	Rain    Rain      `json:"rain"`
-	Dt      int       `json:"dt"`
+	Main    Main           `json:"main"`
+	Wind    Wind           `json:"wind"`
+	Clouds  Clouds         `json:"clouds"`
+	Weather []Weather      `json:"weather"`
+	Rain    Rain           `json:"rain"`
+	Dt      int            `json:"dt"`
+	Precise *PreciseValues `json:"-"` // with WithPreciseNumbers
 }
 
 // HistoricalWeatherData struct is where the JSON is unmarshaled to
@@ -62,105 +64,119 @@ type HistoricalWeatherData struct {
 	CalcTime float64          `json:"calctime"`
 	Cnt      int              `json:"cnt"`
 	List     []WeatherHistory `json:"list"`
-	Unit     string
+	Unit     Unit
 	Key      string
 	*Settings
 }
 
 // NewHistorical returns a new HistoricalWeatherData pointer with
-//the supplied arguments.
+// the supplied arguments.
 func NewHistorical(unit, key string, options ...Option) (*HistoricalWeatherData, error) {
-	h := &HistoricalWeatherData{
-		Settings: NewSettings(),
+	c, err := newClient(unit, "EN", key, options)
+	if err != nil {
+		return nil, err
 	}
+	return c.History(), nil
+}
 
-	unitChoice := strings.ToUpper(unit)
-	if !ValidDataUnit(unitChoice) {
-		return nil, errUnitUnavailable
+// decode unmarshals the response body into h. When precise numbers were
+// requested the Precise field of every entry is filled as well.
+func (h *HistoricalWeatherData) decode(r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
 	}
-	h.Unit = DataUnits[unitChoice]
-
-	var err error
-	h.Key, err = setKey(key)
+	if err := json.Unmarshal(b, &h); err != nil {
+		return err
+	}
+	if !h.precise {
+		return nil
+	}
+	precise, err := preciseEntries(b)
 	if err != nil {
-		return nil, err
+		return err
 	}
-
-	if err := setOptions(h.Settings, options); err != nil {
-		return nil, err
+	for i := range h.List {
+		if i < len(precise) {
+			h.List[i].Precise = precise[i]
+		}
 	}
-	return h, nil
+	return nil
 }
 
 // HistoryByName will return the history for the provided location
 func (h *HistoricalWeatherData) HistoryByName(location string) error {
-	response, err := h.client.Get(fmt.Sprintf(fmt.Sprintf(historyURL, "city?appid=%s&q=%s"), h.Key, url.QueryEscape(location)))
+	return h.HistoryByNameCtx(context.Background(), location)
+}
+
+// HistoryByNameCtx is like HistoryByName but the request is bound to
+// ctx, which cancels it or sets its deadline.
+func (h *HistoricalWeatherData) HistoryByNameCtx(ctx context.Context, location string) error {
+	response, err := h.getByName(ctx, EndpointHistory, location, func(location string) string {
+		return fmt.Sprintf(fmt.Sprintf(historyURL, "city?appid=%s&q=%s"), h.Key, url.QueryEscape(location))
+	})
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode == http.StatusUnauthorized {
-		return errInvalidKey
-	}
-
-	if err = json.NewDecoder(response.Body).Decode(&h); err != nil {
+	if err = h.decode(response.Body); err != nil {
 		return err
 	}
 
-	return nil
+	return h.postDecode(h)
 }
 
 // HistoryByID will return the history for the provided location ID
 func (h *HistoricalWeatherData) HistoryByID(id int, hp ...*HistoricalParameters) error {
+	return h.HistoryByIDCtx(context.Background(), id, hp...)
+}
+
+// HistoryByIDCtx is like HistoryByID but the request is bound to ctx,
+// which cancels it or sets its deadline.
+func (h *HistoricalWeatherData) HistoryByIDCtx(ctx context.Context, id int, hp ...*HistoricalParameters) error {
 	if len(hp) > 0 {
-		response, err := h.client.Get(fmt.Sprintf(fmt.Sprintf(historyURL, "city?appid=%s&id=%d&type=hour&start%d&end=%d&cnt=%d"), h.Key, id, hp[0].Start, hp[0].End, hp[0].Cnt))
+		response, err := h.get(ctx, EndpointHistory, fmt.Sprintf(fmt.Sprintf(historyURL, "city?appid=%s&id=%d&type=hour&start%d&end=%d&cnt=%d"), h.Key, id, hp[0].Start, hp[0].End, hp[0].Cnt))
 		if err != nil {
 			return err
 		}
 		defer response.Body.Close()
 
-		if response.StatusCode == http.StatusUnauthorized {
-			return errInvalidKey
-		}
-
-		if err = json.NewDecoder(response.Body).Decode(&h); err != nil {
+		if err = h.decode(response.Body); err != nil {
 			return err
 		}
 	}
 
-	response, err := h.client.Get(fmt.Sprintf(fmt.Sprintf(historyURL, "city?appid=%s&id=%d"), h.Key, id))
+	response, err := h.get(ctx, EndpointHistory, fmt.Sprintf(fmt.Sprintf(historyURL, "city?appid=%s&id=%d"), h.Key, id))
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode == http.StatusUnauthorized {
-		return errInvalidKey
-	}
-
-	if err = json.NewDecoder(response.Body).Decode(&h); err != nil {
+	if err = h.decode(response.Body); err != nil {
 		return err
 	}
 
-	return nil
+	return h.postDecode(h)
 }
 
 // HistoryByCoord will return the history for the provided coordinates
 func (h *HistoricalWeatherData) HistoryByCoord(location *Coordinates, hp *HistoricalParameters) error {
-	response, err := h.client.Get(fmt.Sprintf(fmt.Sprintf(historyURL, "appid=%s&lat=%f&lon=%f&start=%d&end=%d"), h.Key, location.Latitude, location.Longitude, hp.Start, hp.End))
+	return h.HistoryByCoordCtx(context.Background(), location, hp)
+}
+
+// HistoryByCoordCtx is like HistoryByCoord but the request is bound to
+// ctx, which cancels it or sets its deadline.
+func (h *HistoricalWeatherData) HistoryByCoordCtx(ctx context.Context, location *Coordinates, hp *HistoricalParameters) error {
+	response, err := h.get(ctx, EndpointHistory, fmt.Sprintf(fmt.Sprintf(historyURL, "appid=%s&lat=%f&lon=%f&start=%d&end=%d"), h.Key, location.Latitude, location.Longitude, hp.Start, hp.End))
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode == http.StatusUnauthorized {
-		return errInvalidKey
-	}
-
-	if err = json.NewDecoder(response.Body).Decode(&h); err != nil {
+	if err = h.decode(response.Body); err != nil {
 		return err
 	}
 
-	return nil
+	return h.postDecode(h)
 }
@@ -0,0 +1,44 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+// PostDecodeHook is called with every decoded result, such as a
+// *CurrentWeatherData or *Pollution, before the request method returns.
+// Returning an error makes the request method fail with it.
+type PostDecodeHook func(result interface{}) error
+
+// WithPostDecodeHook registers a hook run after each response is decoded,
+// giving a single place to normalize or enrich results. Hooks run in the
+// order they were given.
+func WithPostDecodeHook(h PostDecodeHook) Option {
+	return func(s *Settings) error {
+		if h == nil {
+			return errInvalidOption
+		}
+		s.hooks = append(s.hooks, h)
+		return nil
+	}
+}
+
+// postDecode runs the registered hooks on the result, returning a panic
+// of one as a *PanicError.
+func (s *Settings) postDecode(result interface{}) error {
+	for _, h := range s.hooks {
+		if err := safely(func() error { return h(result) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// TestWithPostDecodeHook will verify hooks run in order on every decoded
+// result and that their errors are returned.
+func TestWithPostDecodeHook(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1,"main":{"temp":3.1,"humidity":140}}`)
+	})
+	defer ts.Close()
+
+	clamp := func(result interface{}) error {
+		if w, ok := result.(*CurrentWeatherData); ok && w.Main.Humidity > 100 {
+			w.Main.Humidity = 100
+		}
+		return nil
+	}
+	var seen []int
+	record := func(result interface{}) error {
+		seen = append(seen, result.(*CurrentWeatherData).Main.Humidity)
+		return nil
+	}
+
+	c, err := NewCurrent("c", "EN", "key", WithHttpClient(hc), WithPostDecodeHook(clamp), WithPostDecodeHook(record))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CurrentByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if c.Main.Humidity != 100 {
+		t.Errorf("expected humidity clamped to 100, got %d", c.Main.Humidity)
+	}
+	if len(seen) != 1 || seen[0] != 100 {
+		t.Errorf("expected hooks to run in order once, got %v", seen)
+	}
+
+	errHook := errors.New("rejected")
+	p, err := NewPollution("key", WithHttpClient(hc), WithPostDecodeHook(func(interface{}) error { return errHook }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.PollutionByParams(&PollutionParameters{Location: Coordinates{}, Datetime: "current"}); err != errHook {
+		t.Errorf("expected hook error, got %v", err)
+	}
+
+	if _, err := NewCurrent("c", "EN", "key", WithPostDecodeHook(nil)); err != errInvalidOption {
+		t.Errorf("expected errInvalidOption for a nil hook, got %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+)
+
+var errIndexExists = errors.New("index already registered")
+
+// Index rates how suitable a forecast window is for an everyday activity
+// on a scale from 0, unsuitable, to 10, ideal. Rate receives the slots of
+// the window and the unit system their values are in.
+type Index struct {
+	Name string
+	Rate func(slots []Slot, unit Unit) float64
+}
+
+// Built in lifestyle indices.
+var (
+	// DryingIndex rates drying laundry outdoors: warm, dry and breezy
+	// slots score high while any precipitation ruins the slot.
+	DryingIndex = Index{Name: "drying", Rate: rateDrying}
+
+	// CarWashIndex rates washing a car, which is only worth it if no
+	// precipitation is expected for the whole window.
+	CarWashIndex = Index{Name: "car wash", Rate: rateCarWash}
+
+	// StargazingIndex rates the sky for stargazing from the cloud cover
+	// and humidity. Pass it the slots covering the night.
+	StargazingIndex = Index{Name: "stargazing", Rate: rateStargazing}
+)
+
+var (
+	indicesMu sync.RWMutex
+	indices   = map[string]Index{
+		DryingIndex.Name:     DryingIndex,
+		CarWashIndex.Name:    CarWashIndex,
+		StargazingIndex.Name: StargazingIndex,
+	}
+)
+
+// RegisterIndex adds a custom index so it's computed by RateIndices.
+func RegisterIndex(i Index) error {
+	if i.Name == "" || i.Rate == nil {
+		return errInvalidOption
+	}
+	indicesMu.Lock()
+	defer indicesMu.Unlock()
+	if _, ok := indices[i.Name]; ok {
+		return errIndexExists
+	}
+	indices[i.Name] = i
+	return nil
+}
+
+// Indices returns the names of the registered indices in sorted order.
+func Indices() []string {
+	indicesMu.RLock()
+	defer indicesMu.RUnlock()
+	names := make([]string, 0, len(indices))
+	for name := range indices {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RateIndices computes every registered index for the slots, keyed by
+// index name. Results are rounded to one decimal.
+func RateIndices(slots []Slot, unit Unit) map[string]float64 {
+	indicesMu.RLock()
+	defer indicesMu.RUnlock()
+	ratings := make(map[string]float64, len(indices))
+	for name, i := range indices {
+		ratings[name] = math.Round(clamp(i.Rate(slots, unit), 0, 10)*10) / 10
+	}
+	return ratings
+}
+
+// wet reports whether precipitation is likely during the slot.
+func (s Slot) wet() bool {
+	return s.Precipitation > 0 || s.Pop >= 0.5 || isPrecipitation(s.ConditionID)
+}
+
+func rateDrying(slots []Slot, unit Unit) float64 {
+	if len(slots) == 0 {
+		return 0
+	}
+	var total float64
+	for _, s := range slots {
+		if s.wet() {
+			continue
+		}
+		warmth := clamp((toCelsius(s.Temp, unit)-5)/20, 0, 1)
+		dryness := 1 - float64(clampPercent(s.Humidity))/100
+		breeze := clamp(toMetersPerSecond(s.WindSpeed, unit)/5, 0, 1)
+		total += 0.4*warmth + 0.3*dryness + 0.3*breeze
+	}
+	return 10 * total / float64(len(slots))
+}
+
+func rateCarWash(slots []Slot, unit Unit) float64 {
+	if len(slots) == 0 {
+		return 0
+	}
+	var maxPop float64
+	for _, s := range slots {
+		if s.Precipitation > 0 || isPrecipitation(s.ConditionID) {
+			return 0
+		}
+		maxPop = math.Max(maxPop, s.Pop)
+	}
+	return 10 * (1 - maxPop)
+}
+
+func rateStargazing(slots []Slot, unit Unit) float64 {
+	if len(slots) == 0 {
+		return 0
+	}
+	var total float64
+	for _, s := range slots {
+		clearSky := 1 - float64(clampPercent(s.Clouds))/100
+		// humid air scatters light, dimming faint stars
+		haze := 0.2 * float64(clampPercent(s.Humidity)) / 100
+		total += clearSky * (1 - haze)
+	}
+	return 10 * total / float64(len(slots))
+}
+
+// toMetersPerSecond converts a wind speed in the given OWM unit system to
+// meters per second.
+func toMetersPerSecond(v float64, unit Unit) float64 {
+	return Speed{Value: v, Unit: unit}.ToMs()
+}
@@ -0,0 +1,80 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"testing"
+)
+
+// TestRateIndices will verify the built in indices react to the
+// conditions they depend on.
+func TestRateIndices(t *testing.T) {
+	sunny := []Slot{
+		{Temp: 24, Humidity: 35, WindSpeed: 4, Clouds: 0},
+		{Temp: 26, Humidity: 30, WindSpeed: 5, Clouds: 10},
+	}
+	showery := []Slot{
+		{Temp: 14, Humidity: 85, WindSpeed: 2, Clouds: 90, Pop: 0.7},
+		{Temp: 13, Humidity: 90, WindSpeed: 3, Clouds: 100, Precipitation: 1.1, ConditionID: 500},
+	}
+
+	good, bad := RateIndices(sunny, "metric"), RateIndices(showery, "metric")
+	for _, name := range []string{"drying", "car wash", "stargazing"} {
+		if good[name] <= bad[name] {
+			t.Errorf("%s: expected sunny %v to beat showery %v", name, good[name], bad[name])
+		}
+	}
+	if bad["car wash"] != 0 || bad["drying"] != 0 {
+		t.Errorf("expected rain to rule out drying and car washing, got %v", bad)
+	}
+	if good["car wash"] != 10 {
+		t.Errorf("expected a dry window to be ideal for a car wash, got %v", good["car wash"])
+	}
+
+	f := RateIndices([]Slot{{Temp: 77, Humidity: 35, WindSpeed: 10}}, "imperial")
+	c := RateIndices([]Slot{{Temp: 25, Humidity: 35, WindSpeed: 4.47}}, "metric")
+	if f["drying"] != c["drying"] {
+		t.Errorf("expected the same drying index across units, got %v and %v", f["drying"], c["drying"])
+	}
+}
+
+// TestRegisterIndex will verify custom indices are computed alongside the
+// built in ones.
+func TestRegisterIndex(t *testing.T) {
+	kite := Index{Name: "kite flying", Rate: func(slots []Slot, unit Unit) float64 {
+		return slots[0].WindSpeed
+	}}
+	if err := RegisterIndex(kite); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		indicesMu.Lock()
+		delete(indices, kite.Name)
+		indicesMu.Unlock()
+	}()
+	if err := RegisterIndex(kite); err != errIndexExists {
+		t.Errorf("expected errIndexExists, got %v", err)
+	}
+	if err := RegisterIndex(Index{Name: "empty"}); err != errInvalidOption {
+		t.Errorf("expected errInvalidOption, got %v", err)
+	}
+
+	if r := RateIndices([]Slot{{WindSpeed: 14}}, "metric"); r["kite flying"] != 10 {
+		t.Errorf("expected the custom index clamped to 10, got %v", r["kite flying"])
+	}
+	if names := Indices(); len(names) != 4 || names[0] != "car wash" {
+		t.Errorf("unexpected indices %v", names)
+	}
+}
@@ -0,0 +1,290 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"testing"
+	"time"
+)
+
+// liveKey skips the test unless live API tests were requested with
+// OWM_LIVE_TESTS=1 and returns the API key to use.
+func liveKey(t *testing.T) string {
+	if os.Getenv("OWM_LIVE_TESTS") != "1" {
+		t.Skip("set OWM_LIVE_TESTS=1 to run tests against the live API")
+	}
+	key := os.Getenv("OWM_API_KEY")
+	if key == "" {
+		t.Fatal("OWM_API_KEY is required for live API tests")
+	}
+	return key
+}
+
+// recordingTransport keeps the body of the last response so tests can
+// compare what the API sent with what was decoded.
+type recordingTransport struct {
+	last []byte
+}
+
+func (rt *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	response, err := http.DefaultTransport.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	rt.last = b
+	response.Body = ioutil.NopCloser(bytes.NewReader(b))
+	return response, nil
+}
+
+// newLiveClient returns an http client recording response bodies.
+func newLiveClient() (*http.Client, *recordingTransport) {
+	rt := &recordingTransport{}
+	return &http.Client{Transport: rt, Timeout: 30 * time.Second}, rt
+}
+
+// jsonPaths collects the dotted paths of every object key in the JSON
+// value. Array elements share the path of the array.
+func jsonPaths(v interface{}, prefix string, paths map[string]bool) {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, e := range t {
+			p := k
+			if prefix != "" {
+				p = prefix + "." + k
+			}
+			paths[p] = true
+			jsonPaths(e, p, paths)
+		}
+	case []interface{}:
+		for _, e := range t {
+			jsonPaths(e, prefix, paths)
+		}
+	}
+}
+
+// droppedFields returns the paths present in the raw payload that are lost
+// when it's decoded into v and encoded again, i.e. the fields the structs
+// don't model.
+func droppedFields(raw []byte, v interface{}) ([]string, error) {
+	var in interface{}
+	if err := json.Unmarshal(raw, &in); err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out interface{}
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+
+	have, got := make(map[string]bool), make(map[string]bool)
+	jsonPaths(in, "", have)
+	jsonPaths(out, "", got)
+
+	var dropped []string
+	for p := range have {
+		if !got[p] {
+			dropped = append(dropped, p)
+		}
+	}
+	sort.Strings(dropped)
+	return dropped, nil
+}
+
+// checkCoverage fails the test when the decoded value drops fields of
+// the recorded response.
+func checkCoverage(t *testing.T, rt *recordingTransport, v interface{}) {
+	t.Helper()
+	dropped, err := droppedFields(rt.last, v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dropped) > 0 {
+		t.Errorf("response fields not covered by %T: %v", v, dropped)
+	}
+}
+
+// skipUnlessSubscribed skips endpoints the key's plan doesn't include.
+func skipUnlessSubscribed(t *testing.T, err error) {
+	t.Helper()
+	if errors.Is(err, errInvalidKey) {
+		t.Skip("endpoint not included in the API key's subscription")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+var liveCoordinates = &Coordinates{Longitude: -0.1257, Latitude: 51.5085}
+
+func TestLiveCurrent(t *testing.T) {
+	key := liveKey(t)
+	hc, rt := newLiveClient()
+
+	w, err := NewCurrent("C", "EN", key, WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := map[string]func() error{
+		"name":        func() error { return w.CurrentByName("London,GB") },
+		"coordinates": func() error { return w.CurrentByCoordinates(liveCoordinates) },
+		"id":          func() error { return w.CurrentByID(2643743) },
+		"zipcode":     func() error { return w.CurrentByZipcode("19125", "US") },
+	}
+	for name, call := range calls {
+		if err := call(); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if w.ID == 0 || len(w.Weather) == 0 {
+			t.Errorf("%s: incomplete response %+v", name, w)
+		}
+		checkCoverage(t, rt, w)
+	}
+}
+
+func TestLiveGroup(t *testing.T) {
+	key := liveKey(t)
+	hc, rt := newLiveClient()
+
+	g, err := NewCurrentGroup("C", "EN", key, WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.CurrentByIDs(2643743, 2988507); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.List) != 2 {
+		t.Errorf("expected 2 cities, got %d", len(g.List))
+	}
+
+	if err := g.SearchByName("London", SearchLike); err != nil {
+		t.Fatal(err)
+	}
+	if len(g.List) == 0 {
+		t.Error("expected at least one candidate")
+	}
+	checkCoverage(t, rt, g)
+}
+
+func TestLiveForecast(t *testing.T) {
+	key := liveKey(t)
+
+	for _, kind := range []string{"5", "16"} {
+		hc, rt := newLiveClient()
+		f, err := NewForecast(kind, "C", "EN", key, WithHttpClient(hc))
+		if err != nil {
+			t.Fatal(err)
+		}
+		skipUnlessSubscribed(t, f.DailyByCoordinates(liveCoordinates, 3))
+		if f.Schema == SchemaUnknown {
+			t.Skipf("%s day forecast not included in the API key's subscription", kind)
+		}
+		checkCoverage(t, rt, f.ForecastWeatherJson)
+	}
+}
+
+func TestLiveOneCall(t *testing.T) {
+	key := liveKey(t)
+	hc, rt := newLiveClient()
+
+	o, err := NewOneCall("C", "EN", key, nil, WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	skipUnlessSubscribed(t, o.OneCallByCoordinates(liveCoordinates))
+	if o.Schema == SchemaUnknown {
+		t.Skip("one call not included in the API key's subscription")
+	}
+	checkCoverage(t, rt, o)
+}
+
+func TestLiveHistory(t *testing.T) {
+	key := liveKey(t)
+	hc, rt := newLiveClient()
+
+	h, err := NewHistorical("C", key, WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	end := time.Now().Add(-time.Hour)
+	skipUnlessSubscribed(t, h.HistoryByCoord(liveCoordinates, &HistoricalParameters{Start: end.Add(-3 * time.Hour).Unix(), End: end.Unix()}))
+	checkCoverage(t, rt, h)
+}
+
+func TestLivePollution(t *testing.T) {
+	key := liveKey(t)
+	hc, rt := newLiveClient()
+
+	p, err := NewPollution(key, WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.PollutionByParams(&PollutionParameters{Location: *liveCoordinates, Datetime: "current"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.List) == 0 {
+		t.Error("expected pollution data")
+	}
+	checkCoverage(t, rt, p)
+}
+
+func TestLiveUV(t *testing.T) {
+	key := liveKey(t)
+	hc, rt := newLiveClient()
+
+	u, err := NewUV(key, WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	skipUnlessSubscribed(t, u.Current(liveCoordinates))
+	checkCoverage(t, rt, u)
+}
+
+// TestDroppedFields will verify fields missing from the structs are
+// reported while modeled ones aren't.
+func TestDroppedFields(t *testing.T) {
+	raw := []byte(`{"id":1,"name":"x","main":{"temp":1,"new_field":2},"weather":[{"id":800,"extra":true}],"brand_new":{}}`)
+
+	dropped, err := droppedFields(raw, &CurrentWeatherData{ID: 1, Name: "x", Main: Main{Temp: 1}, Weather: []Weather{{ID: 800}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"brand_new", "main.new_field", "weather.extra"}
+	if len(dropped) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, dropped)
+	}
+	for i := range expected {
+		if dropped[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, dropped)
+		}
+	}
+}
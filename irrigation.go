@@ -0,0 +1,85 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"math"
+	"time"
+)
+
+// IrrigationParameters describes the garden a watering recommendation is
+// made for. Amounts are in mm of water.
+type IrrigationParameters struct {
+	// ET is the evapotranspiration rate, the water the soil and plants
+	// lose per day.
+	ET float64
+	// SoilCapacity is the most water the root zone holds; rain beyond it
+	// drains away.
+	SoilCapacity float64
+	// Threshold is the deficit at which watering is needed.
+	Threshold float64
+	// Lookback and Horizon are how far back rain is counted and how far
+	// ahead forecast rain is relied on.
+	Lookback time.Duration
+	Horizon  time.Duration
+	// MinPop is the probability of precipitation from which forecast rain
+	// is counted, weighted by its probability.
+	MinPop float64
+}
+
+// DefaultIrrigationParameters suits a lawn in a temperate summer.
+var DefaultIrrigationParameters = IrrigationParameters{
+	ET:           4,
+	SoilCapacity: 25,
+	Threshold:    12,
+	Lookback:     72 * time.Hour,
+	Horizon:      24 * time.Hour,
+	MinPop:       0.5,
+}
+
+// IrrigationAdvice is a watering recommendation along with the water
+// balance it's based on.
+type IrrigationAdvice struct {
+	Skip         bool
+	RecentRain   float64
+	ExpectedRain float64
+	// Deficit is the water the soil will be missing at the end of the
+	// horizon if it isn't watered.
+	Deficit float64
+}
+
+// Irrigation recommends whether to skip watering at now given the slots
+// of the recent history (e.g. HistoricalWeatherData.Slots) and of the
+// forecast. Watering is skipped when recent and expected rain keep the
+// deficit below the threshold.
+func Irrigation(history, forecast []Slot, p IrrigationParameters, now time.Time) IrrigationAdvice {
+	var a IrrigationAdvice
+	for _, s := range history {
+		if !s.Start.Before(now.Add(-p.Lookback)) && s.Start.Before(now) {
+			a.RecentRain += s.Precipitation
+		}
+	}
+	for _, s := range forecast {
+		if !s.Start.Before(now) && s.Start.Before(now.Add(p.Horizon)) && s.Pop >= p.MinPop {
+			a.ExpectedRain += s.Precipitation * s.Pop
+		}
+	}
+
+	days := (p.Lookback + p.Horizon).Hours() / 24
+	stored := math.Min(a.RecentRain+a.ExpectedRain, p.SoilCapacity)
+	a.Deficit = math.Max(0, p.ET*days-stored)
+	a.Skip = a.Deficit < p.Threshold
+	return a
+}
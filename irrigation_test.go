@@ -0,0 +1,52 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"testing"
+	"time"
+)
+
+// TestIrrigation will verify recent and expected rain are weighed against
+// evapotranspiration.
+func TestIrrigation(t *testing.T) {
+	now := time.Date(2023, 7, 10, 6, 0, 0, 0, time.UTC)
+	history := []Slot{
+		{Start: now.Add(-96 * time.Hour), Precipitation: 30}, // before the lookback
+		{Start: now.Add(-24 * time.Hour), Precipitation: 2},
+	}
+	forecast := []Slot{
+		{Start: now.Add(3 * time.Hour), Precipitation: 10, Pop: 0.4}, // too unlikely
+		{Start: now.Add(6 * time.Hour), Precipitation: 2, Pop: 1},
+	}
+
+	// 4 days at 4 mm leave a 12 mm deficit after 4 mm of rain.
+	a := Irrigation(history, forecast, DefaultIrrigationParameters, now)
+	if a.Skip || a.RecentRain != 2 || a.ExpectedRain != 2 || a.Deficit != 12 {
+		t.Errorf("expected watering with a 12 mm deficit, got %+v", a)
+	}
+
+	forecast = append(forecast, Slot{Start: now.Add(9 * time.Hour), Precipitation: 40, Pop: 1})
+	a = Irrigation(history, forecast, DefaultIrrigationParameters, now)
+	if !a.Skip || a.Deficit != 0 {
+		t.Errorf("expected a downpour to skip watering, got %+v", a)
+	}
+
+	p := DefaultIrrigationParameters
+	p.SoilCapacity = 3
+	if a := Irrigation(history, forecast, p, now); a.Skip {
+		t.Errorf("expected rain beyond the soil capacity to drain, got %+v", a)
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ErrUpstreamMaintenance is matched with errors.Is by the *MaintenanceError
+// returned when OWM, or a proxy in front of it, answers with an HTML page
+// instead of JSON along with a success or server error status, as it does
+// during maintenance.
+var ErrUpstreamMaintenance = errors.New("openweathermap: upstream maintenance")
+
+// MaintenanceError describes an HTML answer. RetryAfter is the delay
+// suggested by a Retry-After header, or zero if none was sent.
+type MaintenanceError struct {
+	StatusCode int
+	Title      string
+	RetryAfter time.Duration
+}
+
+func (e *MaintenanceError) Error() string {
+	msg := fmt.Sprintf("openweathermap: upstream maintenance (%d", e.StatusCode)
+	if e.Title != "" {
+		msg += " " + e.Title
+	}
+	msg += ")"
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(", retry after %v", e.RetryAfter)
+	}
+	return msg
+}
+
+// Is lets errors.Is match the error with ErrUpstreamMaintenance.
+func (e *MaintenanceError) Is(target error) bool { return target == ErrUpstreamMaintenance }
+
+var htmlTitle = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// maintenanceOf returns the error of a successful or 5xx response holding
+// an HTML page, leaving the body readable otherwise. Client errors are
+// left to APIError, as they aren't caused by maintenance, and so are
+// pages requested with ModeHTML.
+func maintenanceOf(response *http.Response) (*MaintenanceError, bool) {
+	if response.StatusCode >= 300 && response.StatusCode < 500 {
+		return nil, false
+	}
+	if response.Request != nil && response.Request.URL.Query().Get("mode") == string(ModeHTML) {
+		return nil, false
+	}
+	b, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	response.Body = ioutil.NopCloser(bytes.NewReader(b))
+	if err != nil || !isHTML(response.Header.Get("Content-Type"), b) {
+		return nil, false
+	}
+
+	e := &MaintenanceError{StatusCode: response.StatusCode}
+	if m := htmlTitle.FindSubmatch(b); m != nil {
+		e.Title = strings.Join(strings.Fields(html.UnescapeString(string(m[1]))), " ")
+	}
+	if d, ok := retryAfter(response.Header.Get("Retry-After")); ok {
+		e.RetryAfter = d
+	}
+	return e, true
+}
+
+// isHTML reports whether the body is an HTML page.
+func isHTML(contentType string, body []byte) bool {
+	if strings.Contains(strings.ToLower(contentType), "text/html") {
+		return true
+	}
+	start := bytes.ToLower(bytes.TrimSpace(body))
+	if len(start) > 64 {
+		start = start[:64]
+	}
+	return bytes.HasPrefix(start, []byte("<!doctype html")) || bytes.HasPrefix(start, []byte("<html"))
+}
@@ -0,0 +1,74 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestMaintenanceError will verify HTML pages fail with a maintenance
+// error carrying the retry hint, while JSON errors stay API errors.
+func TestMaintenanceError(t *testing.T) {
+	calls := 0
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Header().Set("Retry-After", "120")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, "<html><head><title>Scheduled\n maintenance &amp; upgrade</title></head></html>")
+		case 2:
+			w.Header().Set("Content-Type", "text/plain")
+			fmt.Fprint(w, "  <!DOCTYPE html><html><body>Be right back</body></html>")
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"cod":500,"message":"internal error"}`)
+		}
+	})
+	defer ts.Close()
+
+	c, err := NewCurrent("C", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = c.CurrentByID(1)
+	var m *MaintenanceError
+	if !errors.Is(err, ErrUpstreamMaintenance) || !errors.As(err, &m) {
+		t.Fatalf("expected a maintenance error, got %v", err)
+	}
+	if m.StatusCode != http.StatusServiceUnavailable || m.RetryAfter != 2*time.Minute || m.Title != "Scheduled maintenance & upgrade" {
+		t.Errorf("unexpected maintenance error %+v", m)
+	}
+	expected := "openweathermap: upstream maintenance (503 Scheduled maintenance & upgrade), retry after 2m0s"
+	if err.Error() != expected {
+		t.Errorf("expected %q, got %q", expected, err)
+	}
+
+	if err := c.CurrentByID(1); !errors.Is(err, ErrUpstreamMaintenance) {
+		t.Errorf("expected an HTML page served as 200 to be detected, got %v", err)
+	}
+
+	err = c.CurrentByID(1)
+	var apiErr *APIError
+	if errors.Is(err, ErrUpstreamMaintenance) || !errors.As(err, &apiErr) || apiErr.Message != "internal error" {
+		t.Errorf("expected an API error, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRelativeExpiry is the longest expiry memcached takes in seconds;
+// longer ones must be given as a Unix time.
+const maxRelativeExpiry = 30 * 24 * time.Hour
+
+// MemcacheCache is a Cache backed by one or more memcached servers.
+// Cache keys are hashed with SHA-256, so requests differing only in the
+// order of their query parameters share an entry and keys stay within
+// memcached's length and character limits. Each key is stored on the
+// server chosen by rendezvous hashing, which only moves the keys of a
+// server when it is added or removed. A failing server counts as a miss
+// and failed stores are dropped.
+type MemcacheCache struct {
+	// Prefix is prepended to every hashed key. NewMemcacheCache sets it
+	// to "owm:".
+	Prefix string
+	// Timeout bounds each command. NewMemcacheCache sets it to a second.
+	Timeout time.Duration
+
+	servers []string
+	pools   map[string]*connPool
+	now     func() time.Time
+}
+
+// NewMemcacheCache returns a cache spreading its entries over the
+// memcached servers, e.g. "localhost:11211".
+func NewMemcacheCache(servers ...string) *MemcacheCache {
+	c := &MemcacheCache{
+		Prefix:  "owm:",
+		Timeout: time.Second,
+		servers: servers,
+		pools:   make(map[string]*connPool, len(servers)),
+		now:     time.Now,
+	}
+	for _, s := range servers {
+		c.pools[s] = newConnPool(s)
+	}
+	return c
+}
+
+// key returns the memcached key for the cache key.
+func (c *MemcacheCache) key(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return c.Prefix + hex.EncodeToString(sum[:])
+}
+
+// pool returns the connections of the server with the highest score for
+// the key.
+func (c *MemcacheCache) pool(key string) *connPool {
+	return c.pools[rendezvous(c.servers, key)]
+}
+
+// rendezvous returns the node with the highest score for the key, which
+// only changes for the keys of a node when it is added or removed.
+func rendezvous(nodes []string, key string) string {
+	var (
+		best      string
+		bestScore uint64
+	)
+	for _, n := range nodes {
+		h := fnv.New64a()
+		io.WriteString(h, n)
+		io.WriteString(h, key)
+		if score := h.Sum64(); best == "" || score > bestScore {
+			best, bestScore = n, score
+		}
+	}
+	return best
+}
+
+// Get returns the body stored under key, if it hasn't expired.
+func (c *MemcacheCache) Get(key string) ([]byte, bool) {
+	if len(c.servers) == 0 {
+		return nil, false
+	}
+	k := c.key(key)
+	var body []byte
+	err := c.pool(k).do(c.Timeout, func(conn *poolConn) error {
+		if _, err := fmt.Fprintf(conn, "get %s\r\n", k); err != nil {
+			return err
+		}
+		for {
+			line, err := readLine(conn.r)
+			if err != nil {
+				return err
+			}
+			if line == "END" {
+				return nil
+			}
+			// VALUE <key> <flags> <bytes>
+			f := strings.Fields(line)
+			if len(f) != 4 || f[0] != "VALUE" {
+				return errCacheReply
+			}
+			n, err := strconv.Atoi(f[3])
+			if err != nil {
+				return errCacheReply
+			}
+			b := make([]byte, n+2)
+			if _, err := io.ReadFull(conn.r, b); err != nil {
+				return err
+			}
+			body = b[:n]
+		}
+	})
+	return body, err == nil && body != nil
+}
+
+// Set stores the body under key for ttl, rounded up to the second.
+func (c *MemcacheCache) Set(key string, body []byte, ttl time.Duration) {
+	if len(c.servers) == 0 {
+		return
+	}
+	exp := int64((ttl + time.Second - 1) / time.Second)
+	if exp < 1 {
+		exp = 1
+	}
+	if ttl > maxRelativeExpiry {
+		exp = c.now().Add(ttl).Unix()
+	}
+	k := c.key(key)
+	c.pool(k).do(c.Timeout, func(conn *poolConn) error {
+		if _, err := fmt.Fprintf(conn, "set %s 0 %d %d\r\n%s\r\n", k, exp, len(body), body); err != nil {
+			return err
+		}
+		line, err := readLine(conn.r)
+		if err != nil {
+			return err
+		}
+		if line != "STORED" {
+			return errCacheReply
+		}
+		return nil
+	})
+}
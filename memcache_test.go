@@ -0,0 +1,187 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeMemcache is a memcached server understanding get and set, recording
+// the expiry of every key.
+type fakeMemcache struct {
+	ln      net.Listener
+	mu      sync.Mutex
+	values  map[string]string
+	expires map[string]string
+}
+
+func newFakeMemcache(t *testing.T) *fakeMemcache {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeMemcache{ln: ln, values: make(map[string]string), expires: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeMemcache) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		line, err := readLine(r)
+		if err != nil {
+			return
+		}
+		args := strings.Fields(line)
+		switch args[0] {
+		case "get":
+			f.mu.Lock()
+			if v, ok := f.values[args[1]]; ok {
+				fmt.Fprintf(conn, "VALUE %s 0 %d\r\n%s\r\n", args[1], len(v), v)
+			}
+			f.mu.Unlock()
+			fmt.Fprint(conn, "END\r\n")
+		case "set":
+			n, _ := strconv.Atoi(args[4])
+			b := make([]byte, n+2)
+			if _, err := io.ReadFull(r, b); err != nil {
+				return
+			}
+			f.mu.Lock()
+			f.values[args[1]] = string(b[:n])
+			f.expires[args[1]] = args[3]
+			f.mu.Unlock()
+			fmt.Fprint(conn, "STORED\r\n")
+		default:
+			fmt.Fprint(conn, "ERROR\r\n")
+		}
+	}
+}
+
+// TestMemcacheCache will verify responses are shared through memcached
+// under hashed keys, whatever the order of the query parameters.
+func TestMemcacheCache(t *testing.T) {
+	servers := []*fakeMemcache{newFakeMemcache(t), newFakeMemcache(t)}
+	var addrs []string
+	for _, s := range servers {
+		defer s.ln.Close()
+		addrs = append(addrs, s.ln.Addr().String())
+	}
+
+	calls := 0
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"name":"London","main":{"temp":12}}`)
+	})
+	defer ts.Close()
+
+	cache := NewMemcacheCache(addrs...)
+	for _, key := range []string{"key-a", "key-b"} {
+		w, err := NewCurrent("C", "EN", key, WithHttpClient(hc), WithCache(cache, 90*time.Second))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.CurrentByName("London"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the second lookup from the cache, got %d calls", calls)
+	}
+
+	if cacheKey("http://x/weather?q=London&units=metric") != cacheKey("http://x/weather?units=metric&q=London&appid=k") {
+		t.Error("expected the query order and API key not to change the cache key")
+	}
+	entries := 0
+	for _, s := range servers {
+		s.mu.Lock()
+		for k, v := range s.values {
+			entries++
+			if !strings.HasPrefix(k, "owm:") || len(k) != len("owm:")+64 {
+				t.Errorf("unexpected key %q", k)
+			}
+			if s.expires[k] != "90" || !strings.Contains(v, "London") {
+				t.Errorf("unexpected entry %q expiring in %s", v, s.expires[k])
+			}
+		}
+		s.mu.Unlock()
+	}
+	if entries != 1 {
+		t.Errorf("expected a single entry, got %d", entries)
+	}
+}
+
+// TestMemcacheCacheServers will verify keys are spread over the servers
+// and only move when their server goes away.
+func TestMemcacheCacheServers(t *testing.T) {
+	all := NewMemcacheCache("a:11211", "b:11211", "c:11211")
+	fewer := NewMemcacheCache("a:11211", "b:11211")
+
+	used := make(map[*connPool]bool)
+	for i := 0; i < 100; i++ {
+		k := all.key(fmt.Sprint(i))
+		p := all.pool(k)
+		used[p] = true
+		if p != all.pools["c:11211"] && fewer.pool(k).addr != p.addr {
+			t.Errorf("key %d moved from %s to %s", i, p.addr, fewer.pool(k).addr)
+		}
+	}
+	if len(used) != 3 {
+		t.Errorf("expected keys on every server, got %d", len(used))
+	}
+}
+
+// TestMemcacheCacheExpiry will verify TTLs beyond 30 days are sent as a
+// Unix time.
+func TestMemcacheCacheExpiry(t *testing.T) {
+	s := newFakeMemcache(t)
+	defer s.ln.Close()
+
+	c := NewMemcacheCache(s.ln.Addr().String())
+	c.now = func() time.Time { return time.Unix(1000, 0) }
+	c.Set("long", []byte("v"), 31*24*time.Hour)
+	c.Set("short", []byte("v"), time.Millisecond)
+
+	s.mu.Lock()
+	if exp := s.expires[c.key("long")]; exp != strconv.Itoa(1000+31*24*3600) {
+		t.Errorf("expected an absolute expiry, got %s", exp)
+	}
+	if exp := s.expires[c.key("short")]; exp != "1" {
+		t.Errorf("expected the TTL rounded up to a second, got %s", exp)
+	}
+	s.mu.Unlock()
+
+	if b, ok := c.Get("long"); !ok || string(b) != "v" {
+		t.Errorf("expected the stored body, got %q", b)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"encoding/xml"
+	"errors"
+	"strings"
+	"time"
+)
+
+var errModeUnavailable = errors.New("mode unavailable")
+
+// Mode is the format current weather is requested in.
+type Mode string
+
+// The response formats of the API.
+const (
+	ModeJSON Mode = "json"
+	// ModeXML results are decoded into the same fields as JSON ones. The
+	// XML format lacks the condition's main group, which is set to the
+	// group of its code instead.
+	ModeXML Mode = "xml"
+	// ModeHTML results are kept as is in the HTML field.
+	ModeHTML Mode = "html"
+)
+
+// WithMode requests current weather in the given format, e.g. for legacy
+// XML pipelines. Other endpoints only support JSON and ignore the mode.
+func WithMode(m Mode) Option {
+	return func(s *Settings) error {
+		switch m {
+		case ModeJSON, ModeXML, ModeHTML:
+		default:
+			return errModeUnavailable
+		}
+		s.mode = m
+		return nil
+	}
+}
+
+// withMode adds the requested format to a current weather URI.
+func (s *Settings) withMode(uri string) string {
+	if s.mode == "" || s.mode == ModeJSON {
+		return uri
+	}
+	return uri + "&mode=" + string(s.mode)
+}
+
+// xmlValue is an XML element holding its value in an attribute.
+type xmlValue struct {
+	Value float64 `xml:"value,attr"`
+}
+
+// currentXML is the XML format of current weather.
+type currentXML struct {
+	City struct {
+		ID    int    `xml:"id,attr"`
+		Name  string `xml:"name,attr"`
+		Coord struct {
+			Lon float64 `xml:"lon,attr"`
+			Lat float64 `xml:"lat,attr"`
+		} `xml:"coord"`
+		Country  string `xml:"country"`
+		Timezone int    `xml:"timezone"`
+		Sun      struct {
+			Rise string `xml:"rise,attr"`
+			Set  string `xml:"set,attr"`
+		} `xml:"sun"`
+	} `xml:"city"`
+	Temperature struct {
+		Value float64 `xml:"value,attr"`
+		Min   float64 `xml:"min,attr"`
+		Max   float64 `xml:"max,attr"`
+	} `xml:"temperature"`
+	FeelsLike xmlValue `xml:"feels_like"`
+	Humidity  xmlValue `xml:"humidity"`
+	Pressure  xmlValue `xml:"pressure"`
+	Wind      struct {
+		Speed     xmlValue `xml:"speed"`
+		Gusts     xmlValue `xml:"gusts"`
+		Direction xmlValue `xml:"direction"`
+	} `xml:"wind"`
+	Clouds        xmlValue `xml:"clouds"`
+	Visibility    xmlValue `xml:"visibility"`
+	Precipitation struct {
+		Value float64 `xml:"value,attr"`
+		Mode  string  `xml:"mode,attr"`
+	} `xml:"precipitation"`
+	Weather struct {
+		Number int    `xml:"number,attr"`
+		Value  string `xml:"value,attr"`
+		Icon   string `xml:"icon,attr"`
+	} `xml:"weather"`
+	LastUpdate struct {
+		Value string `xml:"value,attr"`
+	} `xml:"lastupdate"`
+}
+
+// xmlTime converts a UTC time of the XML format to Unix time, or zero
+// when it's missing.
+func xmlTime(v string) int {
+	t, err := time.Parse("2006-01-02T15:04:05", v)
+	if err != nil {
+		return 0
+	}
+	return int(t.Unix())
+}
+
+// decodeXML unmarshals current weather in the XML format into w.
+func (w *CurrentWeatherData) decodeXML(b []byte) error {
+	var x currentXML
+	if err := xml.Unmarshal(b, &x); err != nil {
+		return err
+	}
+	w.ID = x.City.ID
+	w.Name = x.City.Name
+	w.GeoPos = Coordinates{Longitude: x.City.Coord.Lon, Latitude: x.City.Coord.Lat}
+	w.Timezone = x.City.Timezone
+	w.Sys = Sys{Country: x.City.Country, Sunrise: xmlTime(x.City.Sun.Rise), Sunset: xmlTime(x.City.Sun.Set)}
+	w.Main = Main{
+		Temp:      x.Temperature.Value,
+		TempMin:   x.Temperature.Min,
+		TempMax:   x.Temperature.Max,
+		FeelsLike: x.FeelsLike.Value,
+		Pressure:  x.Pressure.Value,
+		Humidity:  int(x.Humidity.Value),
+	}
+	w.Wind = Wind{Speed: x.Wind.Speed.Value, Gust: x.Wind.Gusts.Value, Deg: x.Wind.Direction.Value}
+	w.Clouds = Clouds{All: int(x.Clouds.Value)}
+	w.Visibility = int(x.Visibility.Value)
+	w.Rain, w.Snow = Rain{}, Snow{}
+	switch strings.ToLower(x.Precipitation.Mode) {
+	case "rain":
+		w.Rain.OneH = x.Precipitation.Value
+	case "snow":
+		w.Snow.OneH = x.Precipitation.Value
+	}
+	w.Weather = nil
+	if x.Weather.Number != 0 {
+		w.Weather = []Weather{{ID: x.Weather.Number, Description: x.Weather.Value, Icon: x.Weather.Icon}}
+		if c, ok := LookupCondition(x.Weather.Number); ok {
+			w.Weather[0].Main = string(c.Group)
+		}
+	}
+	w.Dt = xmlTime(x.LastUpdate.Value)
+	w.Cod = 200
+	return nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+)
+
+// TestModeXML will verify XML responses are decoded into the same fields
+// as JSON ones.
+func TestModeXML(t *testing.T) {
+	body, err := ioutil.ReadFile("testdata/current.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mode string
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		mode = r.URL.Query().Get("mode")
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.Write(body)
+	})
+	defer ts.Close()
+
+	c, err := NewCurrent("C", "EN", "key", WithHttpClient(hc), WithMode(ModeXML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CurrentByName("London"); err != nil {
+		t.Fatal(err)
+	}
+	if mode != "xml" {
+		t.Errorf("expected XML to be requested, got %q", mode)
+	}
+	if c.ID != 2643743 || c.Name != "London" || c.Sys.Country != "GB" || c.GeoPos.Latitude != 51.5085 {
+		t.Errorf("unexpected city %+v", c)
+	}
+	if c.Main.Temp != 9.82 || c.Main.Humidity != 87 || c.Wind.Deg != 230 || c.Wind.Gust != 0 || c.Rain.OneH != 0.42 {
+		t.Errorf("unexpected values %+v %+v %+v", c.Main, c.Wind, c.Rain)
+	}
+	if cond, ok := c.FirstCondition(); !ok || cond.ID != 500 || cond.Main != "Rain" || cond.Description != "light rain" {
+		t.Errorf("unexpected condition %+v", cond)
+	}
+	if c.Dt != 1700130112 || c.Sys.Sunrise != 1700119156 {
+		t.Errorf("unexpected times %d %d", c.Dt, c.Sys.Sunrise)
+	}
+}
+
+// TestModeHTML will verify HTML pages are kept as is rather than taken
+// for maintenance pages.
+func TestModeHTML(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<!DOCTYPE html><html><body>London 10°C</body></html>`)
+	})
+	defer ts.Close()
+
+	c, err := NewCurrent("C", "EN", "key", WithHttpClient(hc), WithMode(ModeHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CurrentByID(2643743); err != nil {
+		t.Fatal(err)
+	}
+	if c.HTML == "" {
+		t.Error("expected the page to be kept")
+	}
+
+	if _, err := NewCurrent("C", "EN", "key", WithMode("yaml")); err != errModeUnavailable {
+		t.Errorf("expected errModeUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Descriptions returns the descriptions of the result's conditions in each
+// of the languages, keyed by upper case language code and in the order of
+// Weather. Languages with registered translations of every condition are
+// translated locally; the others are fetched concurrently by requesting
+// the location's ID again in that language. Fetched descriptions are kept
+// by the client, shared by every result it built, so later calls are
+// answered locally; they are not registered globally.
+func (w *CurrentWeatherData) Descriptions(ctx context.Context, langs ...string) (map[string][]string, error) {
+	result := make(map[string][]string, len(langs))
+	var mu sync.Mutex
+	g := NewFetchGroup(ctx)
+
+	for _, lang := range langs {
+		lang = strings.ToUpper(lang)
+		if !ValidLangCode(lang) {
+			return nil, errLangUnavailable
+		}
+		if _, ok := result[lang]; ok {
+			continue
+		}
+		if lang == w.Lang {
+			result[lang] = weatherDescriptions(w.Weather)
+			continue
+		}
+		if d, ok := w.registeredDescriptions(lang); ok {
+			result[lang] = d
+			continue
+		}
+		if w.ID == 0 {
+			return nil, errInvalidLocation
+		}
+
+		result[lang] = nil
+		lang := lang
+		g.Add(lang, func(ctx context.Context) error {
+			d, err := w.fetchDescriptions(ctx, lang)
+			if err != nil {
+				return err
+			}
+			mu.Lock()
+			result[lang] = d
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if _, err := g.Run(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// translations holds the condition descriptions fetched by Descriptions,
+// by language and condition code. It is shared by every copy of the
+// settings.
+type translations struct {
+	mu     sync.Mutex
+	byLang map[string]map[int]string
+}
+
+// get returns the description fetched for the condition code in the
+// language.
+func (t *translations) get(lang string, id int) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	text, ok := t.byLang[lang][id]
+	return text, ok
+}
+
+// add keeps the descriptions fetched in the language.
+func (t *translations) add(lang string, texts map[int]string) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	m, ok := t.byLang[lang]
+	if !ok {
+		m = make(map[int]string, len(texts))
+		t.byLang[lang] = m
+	}
+	for id, text := range texts {
+		m[id] = text
+	}
+}
+
+// registeredDescriptions translates the conditions with the registered
+// or previously fetched descriptions of the language, if all of them have
+// one.
+func (w *CurrentWeatherData) registeredDescriptions(lang string) ([]string, bool) {
+	d := make([]string, 0, len(w.Weather))
+	for _, c := range w.Weather {
+		text, ok := registeredDescription(lang, c.ID)
+		if !ok {
+			text, ok = w.translations.get(lang, c.ID)
+		}
+		if !ok {
+			return nil, false
+		}
+		d = append(d, text)
+	}
+	return d, true
+}
+
+// fetchDescriptions requests the location again in the language and
+// keeps the descriptions it returned. The request uses a copy of the
+// settings without hooks, so the extra result isn't post-processed and w
+// keeps its checksum.
+func (w *CurrentWeatherData) fetchDescriptions(ctx context.Context, lang string) ([]string, error) {
+	s := *w.Settings
+	s.hooks = nil
+	c := &CurrentWeatherData{Unit: w.Unit, Lang: lang, Key: w.Key, Settings: &s}
+	if err := c.CurrentByIDCtx(ctx, w.ID); err != nil {
+		return nil, err
+	}
+
+	fetched := make(map[int]string, len(c.Weather))
+	for _, wc := range c.Weather {
+		fetched[wc.ID] = wc.Description
+	}
+	w.translations.add(lang, fetched)
+
+	// The conditions may have changed since w was fetched; use the
+	// known descriptions where possible so the order matches.
+	if d, ok := w.registeredDescriptions(lang); ok {
+		return d, nil
+	}
+	return weatherDescriptions(c.Weather), nil
+}
+
+// weatherDescriptions returns the description of each condition.
+func weatherDescriptions(conditions []Weather) []string {
+	d := make([]string, 0, len(conditions))
+	for _, c := range conditions {
+		d = append(d, c.Description)
+	}
+	return d
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// TestDescriptions will verify missing languages are fetched concurrently
+// and then answered from the translations kept by the client, without
+// registering them globally.
+func TestDescriptions(t *testing.T) {
+	var mu sync.Mutex
+	requested := map[string]int{}
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		lang := r.URL.Query().Get("lang")
+		mu.Lock()
+		requested[lang]++
+		mu.Unlock()
+		text := map[string][2]string{"DE": {"Leichter Regen", "Nebel"}, "FR": {"légère pluie", "brume"}}[lang]
+		fmt.Fprintf(w, `{"id":2643743,"weather":[{"id":500,"description":%q},{"id":741,"description":%q}]}`, text[0], text[1])
+	})
+	defer ts.Close()
+
+	hooked := 0
+	w, err := NewCurrent("C", "EN", "key", WithHttpClient(hc), WithPostDecodeHook(func(interface{}) error {
+		hooked++
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.ID = 2643743
+	w.Weather = []Weather{{ID: 500, Description: "light rain"}, {ID: 741, Description: "fog"}}
+
+	expected := map[string][]string{
+		"EN": {"light rain", "fog"},
+		"DE": {"Leichter Regen", "Nebel"},
+		"FR": {"légère pluie", "brume"},
+	}
+	for i := 0; i < 2; i++ {
+		d, err := w.Descriptions(context.Background(), "en", "de", "FR", "de")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(d, expected) {
+			t.Errorf("expected %v, got %v", expected, d)
+		}
+	}
+	if requested["DE"] != 1 || requested["FR"] != 1 || len(requested) != 2 {
+		t.Errorf("expected one request per missing language, got %v", requested)
+	}
+	if hooked != 0 || w.Weather[0].Description != "light rain" {
+		t.Error("expected the original result to be left untouched")
+	}
+	if _, ok := registeredDescription("DE", 500); ok {
+		t.Error("expected the fetched translations not to be registered")
+	}
+
+	other, err := NewCurrent("C", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.ID, other.Weather = w.ID, w.Weather
+	if _, err := other.Descriptions(context.Background(), "de"); err != nil {
+		t.Fatal(err)
+	}
+	if requested["DE"] != 2 {
+		t.Errorf("expected another client to fetch its own translations, got %v", requested)
+	}
+
+	if _, err := w.Descriptions(context.Background(), "xx"); err != errLangUnavailable {
+		t.Errorf("expected %v, got %v", errLangUnavailable, err)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// TestNormalizeLocation will verify whitespace and empty parts are removed
+// from location names.
+func TestNormalizeLocation(t *testing.T) {
+	tests := map[string]string{
+		"London":               "London",
+		"  São  Paulo , , BR ": "São Paulo,BR",
+		",Las Vegas,,NV,US,":   "Las Vegas,NV,US",
+		"\tNew\nYork ":         "New York",
+		"":                     "",
+	}
+
+	for in, expected := range tests {
+		if got := NormalizeLocation(in); got != expected {
+			t.Errorf("expected %q for %q, got %q", expected, in, got)
+		}
+	}
+}
+
+// TestFoldASCII will verify diacritics are transliterated to ASCII.
+func TestFoldASCII(t *testing.T) {
+	tests := map[string]string{
+		"São Paulo":   "Sao Paulo",
+		"Kraków":      "Krakow",
+		"Łódź":        "Lodz",
+		"Göteborg":    "Goteborg",
+		"Straße":      "Strasse",
+		"São":        "Sao",
+		"Reykjavík":   "Reykjavik",
+		"Plain ASCII": "Plain ASCII",
+	}
+
+	for in, expected := range tests {
+		if got := FoldASCII(in); got != expected {
+			t.Errorf("expected %q for %q, got %q", expected, in, got)
+		}
+	}
+}
+
+// TestCurrentByNameASCIIFallback will verify a name that isn't found is
+// retried in its ASCII folded form when the fallback is enabled.
+func TestCurrentByNameASCIIFallback(t *testing.T) {
+	var queries []string
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("q")
+		queries = append(queries, q)
+		if q != "Sao Paulo,BR" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"cod":"404","message":"city not found"}`)
+			return
+		}
+		fmt.Fprint(w, `{"id":3448439,"name":"São Paulo","cod":200}`)
+	})
+	defer ts.Close()
+
+	c, err := NewCurrent("c", "EN", "key", WithHttpClient(hc), WithASCIIFallback())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.CurrentByName(" São Paulo , BR"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(queries) != 2 || queries[0] != "São Paulo,BR" || queries[1] != "Sao Paulo,BR" {
+		t.Errorf("unexpected queries %q", queries)
+	}
+	if c.ID != 3448439 {
+		t.Errorf("expected ID 3448439, got %d", c.ID)
+	}
+}
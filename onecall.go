@@ -14,9 +14,11 @@
 package openweathermap
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"strings"
+	"io/ioutil"
+	"time"
 )
 
 // OneCallData struct contains an aggregate view of the structs
@@ -30,12 +32,17 @@ type OneCallData struct {
 	Minutely       []OneCallMinutelyData `json:"minutely,omitempty"`
 	Hourly         []OneCallHourlyData   `json:"hourly,omitempty"`
 	Daily          []OneCallDailyData    `json:"daily,omitempty"`
-	Alerts         []OneCallAlertData    `json:"alerts,omitempty"`
+	Alerts         Alerts                `json:"alerts,omitempty"`
+	Schema         Schema                `json:"-"`
 
-	Unit     string
+	Unit     Unit
 	Lang     string
 	Key      string
 	Excludes string
+
+	// DisplayName is the name of the place at the coordinates, with
+	// WithDisplayNames.
+	DisplayName string `json:"-"`
 	*Settings
 }
 
@@ -109,8 +116,11 @@ type OneCallDailyData struct {
 	Rain      float64   `json:"rain,omitempty"`
 	Snow      float64   `json:"snow,omitempty"`
 	Weather   []Weather `json:"weather"`
+	Summary   string    `json:"summary,omitempty"` // One Call 3.0 only
 }
 
+// OneCallAlertData holds a national weather alert, with Start and End in
+// unix time.
 type OneCallAlertData struct {
 	SenderName  string   `json:"sender_name"`
 	Event       string   `json:"event"`
@@ -120,52 +130,106 @@ type OneCallAlertData struct {
 	Tags        []string `json:"tags"`
 }
 
-// NewCurrent returns a new OneCallData pointer with the supplied parameters
-func NewOneCall(unit, lang, key string, excludes []string, options ...Option) (*OneCallData, error) {
-	unitChoice := strings.ToUpper(unit)
-	langChoice := strings.ToUpper(lang)
+// StartTime returns when the alert takes effect.
+func (a OneCallAlertData) StartTime() time.Time { return time.Unix(int64(a.Start), 0) }
 
-	c := &OneCallData{
-		Settings: NewSettings(),
-	}
+// EndTime returns when the alert ends.
+func (a OneCallAlertData) EndTime() time.Time { return time.Unix(int64(a.End), 0) }
 
-	if !ValidDataUnit(unitChoice) {
-		return nil, errUnitUnavailable
-	}
-	c.Unit = DataUnits[unitChoice]
+// ActiveAt reports whether the alert is in effect at t, from its start up
+// to but excluding its end.
+func (a OneCallAlertData) ActiveAt(t time.Time) bool {
+	return !t.Before(a.StartTime()) && t.Before(a.EndTime())
+}
+
+// Alerts holds the alerts of a one call response.
+type Alerts []OneCallAlertData
 
-	if !ValidLangCode(langChoice) {
-		return nil, errLangUnavailable
+// Active returns the alerts in effect at the given time.
+func (as Alerts) Active(at time.Time) Alerts {
+	var active Alerts
+	for _, a := range as {
+		if a.ActiveAt(at) {
+			active = append(active, a)
+		}
 	}
-	c.Lang = langChoice
+	return active
+}
 
-	var err error
-	c.Excludes, err = ValidExcludes(excludes)
-	if err != nil {
-		return nil, err
+// AtLeast returns the alerts whose inferred severity is s or worse.
+func (as Alerts) AtLeast(s ConditionSeverity) Alerts {
+	var matched Alerts
+	for _, a := range as {
+		if a.Severity() >= s {
+			matched = append(matched, a)
+		}
 	}
+	return matched
+}
 
-	c.Key, err = setKey(key)
-	if err != nil {
-		return nil, err
+// Severest returns the alert of the highest inferred severity, the
+// earliest one on ties, or false if there are none.
+func (as Alerts) Severest() (OneCallAlertData, bool) {
+	if len(as) == 0 {
+		return OneCallAlertData{}, false
+	}
+	severest := as[0]
+	for _, a := range as[1:] {
+		if a.Severity() > severest.Severity() {
+			severest = a
+		}
 	}
+	return severest, true
+}
 
-	err = setOptions(c.Settings, options)
+// NewCurrent returns a new OneCallData pointer with the supplied parameters
+func NewOneCall(unit, lang, key string, excludes []string, options ...Option) (*OneCallData, error) {
+	c, err := newClient(unit, lang, key, options)
 	if err != nil {
 		return nil, err
 	}
+	return c.OneCall(excludes...)
+}
 
-	return c, nil
+// WithOneCall3 makes one call requests use the One Call API 3.0, which
+// is the only version available to keys subscribed to the "One Call by
+// Call" plan. Responses have the same shape, with a daily summary added.
+func WithOneCall3() Option {
+	return func(s *Settings) error {
+		s.oneCall3 = true
+		return nil
+	}
 }
 
 // OneCallByCoordinates will provide the onecall weather with the
 // provided location coordinates.
 func (w *OneCallData) OneCallByCoordinates(location *Coordinates) error {
-	response, err := w.client.Get(fmt.Sprintf(fmt.Sprintf(onecallURL, "appid=%s&lat=%f&lon=%f&units=%s&lang=%s&exclude=%s"), w.Key, location.Latitude, location.Longitude, w.Unit, w.Lang, w.Excludes))
+	return w.OneCallByCoordinatesCtx(context.Background(), location)
+}
+
+// OneCallByCoordinatesCtx is like OneCallByCoordinates but the request
+// is bound to ctx, which cancels it or sets its deadline.
+func (w *OneCallData) OneCallByCoordinatesCtx(ctx context.Context, location *Coordinates) error {
+	uri := onecallURL
+	if w.oneCall3 {
+		uri = onecall3URL
+	}
+	response, err := w.get(ctx, EndpointOneCall, fmt.Sprintf(fmt.Sprintf(uri, "appid=%s&lat=%f&lon=%f&units=%s&lang=%s&exclude=%s"), w.Key, location.Latitude, location.Longitude, w.Unit, w.Lang, w.Excludes))
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	return json.NewDecoder(response.Body).Decode(&w)
+	b, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	w.Current, w.Minutely, w.Hourly, w.Daily, w.Alerts = OneCallCurrentData{}, nil, nil, nil, nil
+	w.DisplayName = w.displayName(ctx, w.Key, w.Lang, location)
+	if err := json.Unmarshal(b, &w); err != nil {
+		return err
+	}
+	w.Schema = DetectSchema(b)
+
+	return w.postDecode(w)
 }
@@ -15,6 +15,7 @@
 package openweathermap
 
 import (
+	"fmt"
 	"net/http"
 	"os"
 	"reflect"
@@ -166,3 +167,108 @@ func TestNewOneCallWithTwoExcludes(t *testing.T) {
 		t.Error("exclude alerts and daily fails")
 	}
 }
+
+// TestWithOneCall3 will verify One Call 3.0 requests hit the 3.0 endpoint
+// and decode every section.
+func TestWithOneCall3(t *testing.T) {
+	var path string
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		fmt.Fprint(w, `{"lat":51.5,"lon":-0.12,"timezone":"Europe/London","timezone_offset":3600,"current":{"dt":1,"temp":11,"weather":[{"id":500}]},
+			"minutely":[{"dt":1,"precipitation":0.2}],"hourly":[{"dt":1,"temp":11,"pop":0.4,"rain":{"1h":0.3}}],
+			"daily":[{"dt":1,"summary":"Rain in the afternoon","temp":{"day":12,"min":8,"max":13,"night":9,"eve":11,"morn":8}}],
+			"alerts":[{"sender_name":"Met Office","event":"Wind warning","start":1,"end":2,"description":"Gusts","tags":["Wind"]}]}`)
+	})
+	defer ts.Close()
+
+	c, err := NewOneCall("c", "en", "key", nil, WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.OneCallByCoordinates(&Coordinates{Latitude: 51.5, Longitude: -0.12}); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/data/2.5/onecall" {
+		t.Errorf("expected the 2.5 endpoint by default, got %s", path)
+	}
+
+	c, err = NewOneCall("c", "en", "key", []string{ExcludeMinutely}, WithHttpClient(hc), WithOneCall3())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.OneCallByCoordinates(&Coordinates{Latitude: 51.5, Longitude: -0.12}); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/data/3.0/onecall" {
+		t.Errorf("expected the 3.0 endpoint, got %s", path)
+	}
+	if c.Schema != SchemaOneCall30 || c.Daily[0].Summary == "" || len(c.Alerts) != 1 || c.Hourly[0].Rain.OneH != 0.3 || len(c.Minutely) != 1 {
+		t.Errorf("unexpected one call data %+v", c)
+	}
+}
+
+// TestOneCallReuse will verify a reused result doesn't keep the sections
+// of the previous response, so lapsed alerts are reported as expired.
+func TestOneCallReuse(t *testing.T) {
+	responses := []string{
+		`{"current":{"dt":1,"temp":11},"hourly":[{"dt":1}],"daily":[{"dt":1}],"alerts":[{"sender_name":"Met Office","event":"Wind","start":1699990000,"end":1700020000}]}`,
+		`{"current":{"dt":2,"temp":12}}`,
+	}
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, responses[0])
+		responses = responses[1:]
+	})
+	defer ts.Close()
+
+	bus := NewBus()
+	var expired []Event
+	bus.Subscribe(func(e Event) {
+		if _, ok := e.(AlertExpired); ok {
+			expired = append(expired, e)
+		}
+	})
+	tracker := NewAlertTracker(bus)
+	tracker.now = func() time.Time { return time.Unix(1700000000, 0) }
+	c, err := NewOneCall("c", "en", "key", nil, WithHttpClient(hc), WithPipeline(tracker.Stage()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := c.OneCallByCoordinates(&Coordinates{Latitude: 51.5, Longitude: -0.12}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(c.Alerts) != 0 || len(c.Hourly) != 0 || len(c.Daily) != 0 || c.Current.Dt != 2 {
+		t.Errorf("expected only the second response, got %+v", c)
+	}
+	if len(expired) != 1 || len(tracker.Active()) != 0 {
+		t.Errorf("expected the alert to expire, got %v", expired)
+	}
+}
+
+// TestAlerts will verify alerts are filtered by time and severity.
+func TestAlerts(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	hour := int(time.Hour / time.Second)
+	alerts := Alerts{
+		{Event: "Wind Advisory", Start: int(now.Unix()) - hour, End: int(now.Unix()) + hour},
+		{Event: "Tornado Warning", Start: int(now.Unix()) + hour, End: int(now.Unix()) + 2*hour},
+		{Event: "Flood Watch", Start: int(now.Unix()) - 2*hour, End: int(now.Unix())},
+	}
+
+	if !alerts[0].StartTime().Equal(now.Add(-time.Hour)) || !alerts[0].EndTime().Equal(now.Add(time.Hour)) {
+		t.Errorf("unexpected times %v %v", alerts[0].StartTime(), alerts[0].EndTime())
+	}
+	if active := alerts.Active(now); len(active) != 1 || active[0].Event != "Wind Advisory" {
+		t.Errorf("expected only the advisory active, got %+v", active)
+	}
+	if severe := alerts.AtLeast(SeverityModerate); len(severe) != 2 {
+		t.Errorf("expected the warning and the watch, got %+v", severe)
+	}
+	if a, ok := alerts.Severest(); !ok || a.Event != "Tornado Warning" {
+		t.Errorf("expected the tornado warning, got %+v", a)
+	}
+	if _, ok := (Alerts{}).Severest(); ok {
+		t.Error("expected no severest alert")
+	}
+}
@@ -15,9 +15,17 @@
 package openweathermap
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io/ioutil"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 )
 
 var (
@@ -29,22 +37,35 @@ var (
 	errForecastUnavailable = errors.New("forecast unavailable")
 	errExcludesUnavailable = errors.New("onecall excludes unavailable")
 	errCountOfCityIDs      = errors.New("count of ids should not be more than 20 per request")
+	errSearchUnavailable   = errors.New("search type unavailable")
+	errInvalidLocation     = errors.New("invalid location")
+	errInvalidBaseURL      = errors.New("invalid base url")
 )
 
 // DataUnits represents the character chosen to represent the temperature notation
 var DataUnits = map[string]string{"C": "metric", "F": "imperial", "K": "internal"}
 var (
-	baseURL        = "https://api.openweathermap.org/data/2.5/weather?%s"
-	onecallURL     = "https://api.openweathermap.org/data/2.5/onecall?%s"
-	iconURL        = "https://openweathermap.org/img/w/%s"
-	groupURL       = "http://api.openweathermap.org/data/2.5/group?%s"
-	stationURL     = "https://api.openweathermap.org/data/2.5/station?id=%d"
-	forecast5Base  = "https://api.openweathermap.org/data/2.5/forecast?appid=%s&%s&mode=json&units=%s&lang=%s&cnt=%d"
-	forecast16Base = "https://api.openweathermap.org/data/2.5/forecast/daily?appid=%s&%s&mode=json&units=%s&lang=%s&cnt=%d"
-	historyURL     = "https://history.openweathermap.org/data/2.5/history/city?%s"
-	pollutionURL   = "https://api.openweathermap.org/data/2.5/air_pollution?appid=%s&lat=%s&lon=%s"
-	uvURL          = "https://api.openweathermap.org/data/2.5/"
-	dataPostURL    = "https://openweathermap.org/data/post"
+	baseURL              = "https://api.openweathermap.org/data/2.5/weather?%s"
+	onecallURL           = "https://api.openweathermap.org/data/2.5/onecall?%s"
+	onecall3URL          = "https://api.openweathermap.org/data/3.0/onecall?%s"
+	timemachineURL       = "https://api.openweathermap.org/data/2.5/onecall/timemachine?%s"
+	timemachine3URL      = "https://api.openweathermap.org/data/3.0/onecall/timemachine?%s"
+	iconURL              = "https://openweathermap.org/img/w/%s"
+	tileURL              = "https://tile.openweathermap.org/map/%s/%d/%d/%d.png?appid=%s"
+	groupURL             = "http://api.openweathermap.org/data/2.5/group?%s"
+	findURL              = "https://api.openweathermap.org/data/2.5/find?%s"
+	boxURL               = "https://api.openweathermap.org/data/2.5/box/city?%s"
+	stationURL           = "https://api.openweathermap.org/data/2.5/station?id=%d"
+	forecast5Base        = "https://api.openweathermap.org/data/2.5/forecast?appid=%s&%s&mode=json&units=%s&lang=%s&cnt=%d"
+	forecast16Base       = "https://api.openweathermap.org/data/2.5/forecast/daily?appid=%s&%s&mode=json&units=%s&lang=%s&cnt=%d"
+	historyURL           = "https://history.openweathermap.org/data/2.5/history/city?%s"
+	pollutionURL         = "https://api.openweathermap.org/data/2.5/air_pollution?appid=%s&lat=%s&lon=%s"
+	pollutionForecastURL = "https://api.openweathermap.org/data/2.5/air_pollution/forecast?appid=%s&lat=%s&lon=%s"
+	pollutionHistoryURL  = "https://api.openweathermap.org/data/2.5/air_pollution/history?appid=%s&lat=%s&lon=%s&start=%d&end=%d"
+	uvURL                = "https://api.openweathermap.org/data/2.5/"
+	dataPostURL          = "https://openweathermap.org/data/post"
+	geoDirectURL         = "https://api.openweathermap.org/geo/1.0/direct?%s"
+	geoReverseURL        = "https://api.openweathermap.org/geo/1.0/reverse?%s"
 )
 
 // LangCodes holds all supported languages to be used
@@ -129,10 +150,54 @@ type Config struct {
 	Password string // Pasword for posting data
 }
 
-// APIError returned on failed API calls.
+// APIError returned on failed API calls, i.e. when OWM answers with a
+// status other than 2xx.
 type APIError struct {
-	Message string `json:"message"`
-	COD     string `json:"cod"`
+	Message    string `json:"message"`
+	COD        string `json:"cod"`
+	StatusCode int    `json:"-"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("openweathermap: %d %s", e.StatusCode, e.Message)
+}
+
+// Is lets errors.Is match a rejected key with errInvalidKey, as returned
+// before requests failed with an APIError.
+func (e *APIError) Is(target error) bool {
+	return target == errInvalidKey && e.StatusCode == http.StatusUnauthorized
+}
+
+// UnmarshalJSON decodes the error payload, which holds cod as a number
+// or a string depending on the endpoint.
+func (e *APIError) UnmarshalJSON(b []byte) error {
+	aux := struct {
+		Message string          `json:"message"`
+		COD     json.RawMessage `json:"cod"`
+	}{}
+	if err := json.Unmarshal(b, &aux); err != nil {
+		return err
+	}
+	e.Message = aux.Message
+	e.COD = strings.Trim(string(aux.COD), `"`)
+	return nil
+}
+
+// newAPIError builds the error of a failed response from its body,
+// falling back to the HTTP status when the body isn't an OWM error.
+func newAPIError(response *http.Response) *APIError {
+	e := &APIError{}
+	if b, err := ioutil.ReadAll(response.Body); err == nil {
+		json.Unmarshal(b, e)
+	}
+	e.StatusCode = response.StatusCode
+	if e.COD == "" {
+		e.COD = strconv.Itoa(response.StatusCode)
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(response.StatusCode)
+	}
+	return e
 }
 
 // Coordinates struct holds longitude and latitude data in returned
@@ -179,6 +244,7 @@ type Main struct {
 	SeaLevel  float64 `json:"sea_level"`
 	GrndLevel float64 `json:"grnd_level"`
 	Humidity  int     `json:"humidity"`
+	TempKf    float64 `json:"temp_kf,omitempty"` // forecast only
 }
 
 // Clouds struct holds data regarding cloud cover.
@@ -251,6 +317,18 @@ func ValidExcludes(e []string) (string, error) {
 	return strings.Join(list, ","), nil
 }
 
+// ValidLocation makes sure the user supplied location string, be it a
+// name, zip code or country code, contains no control characters such
+// as newlines that could be used to tamper with the request.
+func ValidLocation(l string) bool {
+	for _, r := range l {
+		if unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
+
 // ValidAPIKey makes sure that the key given is a valid one
 func ValidAPIKey(key string) error {
 	if len(key) > 64 {
@@ -264,14 +342,55 @@ func (c *Config) CheckAPIKeyExists() bool { return len(c.APIKey) > 1 }
 
 // Settings holds the client settings
 type Settings struct {
-	client *http.Client
+	client        *http.Client
+	timeouts      map[Endpoint]time.Duration
+	asciiFallback bool
+	dualStack     time.Duration
+	checksum      []byte
+	changed       bool
+	checksums     *checksums
+	precise       bool
+	hooks         []PostDecodeHook
+	bus           *Bus
+	oneCall3      bool
+	unit          string
+	lang          string
+	key           string
+	baseURL       *url.URL
+	retry         *RetryPolicy
+	sleep         func(ctx context.Context, d time.Duration) error
+	calendar      *Calendar
+	limiter       *RateLimiter
+	breaker       *CircuitBreaker
+	blocking      bool
+	priority      Priority
+	skew          time.Duration
+	mode          Mode
+	dates         DateConverter
+	cache         Cache
+	cacheTTL      time.Duration
+	cacheSoftTTL  time.Duration
+	notFoundTTL   time.Duration
+	refreshes     *refreshes
+	displayNames  bool
+	translations  *translations
 }
 
-// NewSettings returns a new Setting pointer with default http client.
+// NewSettings returns a new Setting pointer with default http client
+// and request timeouts.
 func NewSettings() *Settings {
-	return &Settings{
-		client: http.DefaultClient,
+	s := &Settings{
+		client:       http.DefaultClient,
+		timeouts:     make(map[Endpoint]time.Duration, len(defaultTimeouts)),
+		checksums:    newChecksums(),
+		refreshes:    &refreshes{running: make(map[string]bool)},
+		translations: &translations{byLang: make(map[string]map[int]string)},
+		sleep:        sleep,
+	}
+	for e, d := range defaultTimeouts {
+		s.timeouts[e] = d
 	}
+	return s
 }
 
 // Optional client settings
@@ -288,6 +407,60 @@ func WithHttpClient(c *http.Client) Option {
 	}
 }
 
+// WithHTTPClient is an alias of WithHttpClient.
+func WithHTTPClient(c *http.Client) Option { return WithHttpClient(c) }
+
+// WithUnit sets the unit of the results, F, C or K, overriding the one
+// given to the constructor.
+func WithUnit(unit string) Option {
+	return func(s *Settings) error {
+		if !ValidDataUnit(strings.ToUpper(unit)) {
+			return errUnitUnavailable
+		}
+		s.unit = unit
+		return nil
+	}
+}
+
+// WithLang sets the language of the results, overriding the one given to
+// the constructor.
+func WithLang(lang string) Option {
+	return func(s *Settings) error {
+		if !ValidLangCode(strings.ToUpper(lang)) {
+			return errLangUnavailable
+		}
+		s.lang = lang
+		return nil
+	}
+}
+
+// WithAPIKey sets the API key, overriding the one given to the
+// constructor.
+func WithAPIKey(key string) Option {
+	return func(s *Settings) error {
+		if err := ValidAPIKey(key); err != nil {
+			return err
+		}
+		s.key = key
+		return nil
+	}
+}
+
+// configured returns the unit, language and key set by options in place
+// of the given constructor arguments.
+func (s *Settings) configured(unit, lang, key string) (string, string, string) {
+	if s.unit != "" {
+		unit = s.unit
+	}
+	if s.lang != "" {
+		lang = s.lang
+	}
+	if s.key != "" {
+		key = s.key
+	}
+	return unit, lang, key
+}
+
 // setOptions sets Optional client settings to the Settings pointer
 func setOptions(settings *Settings, options []Option) error {
 	for _, option := range options {
@@ -299,5 +472,16 @@ func setOptions(settings *Settings, options []Option) error {
 			return err
 		}
 	}
+
+	if settings.notFoundTTL > 0 && settings.cache == nil {
+		return errInvalidOption
+	}
+	if settings.dualStack > 0 {
+		c, err := dualStackClient(settings.client, settings.dualStack)
+		if err != nil {
+			return err
+		}
+		settings.client = c
+	}
 	return nil
 }
@@ -15,9 +15,34 @@
 package openweathermap
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 )
 
+// rewriteTransport sends every request to the test server regardless of
+// the host in the URL built by the code under test.
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (rt rewriteTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.URL.Scheme = rt.target.Scheme
+	r.URL.Host = rt.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// newTestServer starts a test server with the given handler and returns it
+// along with an http client routing all requests to it. The caller is
+// responsible for closing the server.
+func newTestServer(handler http.HandlerFunc) (*httptest.Server, *http.Client) {
+	ts := httptest.NewServer(handler)
+	u, _ := url.Parse(ts.URL)
+	return ts, &http.Client{Transport: rewriteTransport{target: u}}
+}
+
 // TestValidDataUnit tests whether or not ValidDataUnit provides
 // the correct assertion on provided data unit.
 func TestValidDataUnit(t *testing.T) {
@@ -63,3 +88,55 @@ func TestSetOptionsWithEmpty(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+// TestValidLocation will verify control characters are rejected in user
+// supplied location strings.
+func TestValidLocation(t *testing.T) {
+	for _, l := range []string{"London", "São Paulo,BR", "19125", "a&b=c"} {
+		if !ValidLocation(l) {
+			t.Errorf("expected %q to be valid", l)
+		}
+	}
+	for _, l := range []string{"London\n", "US\r\nHost: evil", "a\x00b"} {
+		if ValidLocation(l) {
+			t.Errorf("expected %q to be invalid", l)
+		}
+	}
+}
+
+// TestConfigurationOptions will verify the unit, language and key can be
+// given as options to every constructor, overriding the arguments.
+func TestConfigurationOptions(t *testing.T) {
+	c, err := NewCurrent("", "", "", WithUnit("c"), WithLang("es"), WithAPIKey("key"), WithHTTPClient(http.DefaultClient))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Unit != "metric" || c.Lang != "ES" || c.Key != "key" {
+		t.Errorf("unexpected configuration %s %s %s", c.Unit, c.Lang, c.Key)
+	}
+
+	f, err := NewForecast("5", "F", "EN", "old", WithUnit("K"), WithAPIKey("new"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Unit != "internal" || f.Lang != "EN" || f.Key != "new" {
+		t.Errorf("unexpected configuration %s %s %s", f.Unit, f.Lang, f.Key)
+	}
+
+	if p, err := NewPollution("", WithAPIKey("key")); err != nil || p.Key != "key" {
+		t.Errorf("unexpected pollution key %v", err)
+	}
+	if h, err := NewHistorical("", "", WithUnit("F"), WithAPIKey("key")); err != nil || h.Unit != "imperial" {
+		t.Errorf("unexpected history configuration %v", err)
+	}
+
+	if _, err := NewCurrent("C", "EN", "", WithUnit("X")); err != errUnitUnavailable {
+		t.Errorf("expected %v, got %v", errUnitUnavailable, err)
+	}
+	if _, err := NewUV("", WithLang("XX")); err != errLangUnavailable {
+		t.Errorf("expected %v, got %v", errLangUnavailable, err)
+	}
+	if _, err := NewOneCall("C", "EN", "", nil, WithAPIKey(string(make([]byte, 65)))); err != errInvalidKey {
+		t.Errorf("expected %v, got %v", errInvalidKey, err)
+	}
+}
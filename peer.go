@@ -0,0 +1,218 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PeerCache is a read-through Cache shared by a cluster of instances, in
+// the style of groupcache. Every key is owned by one of the peers, chosen
+// by rendezvous hashing like MemcacheCache does. A local miss is sent to
+// the owner, which answers from its own cache or fetches the response
+// from the API, so a popular city costs a single upstream call however
+// many instances ask for it. Concurrent misses of a key on the owner share
+// that call. When the owner can't be reached or has no response, the
+// instance fetches it itself and keeps it locally.
+type PeerCache struct {
+	// Timeout bounds each request to a peer. NewPeerCache sets it to 30
+	// seconds, leaving the owner time to fetch from the API.
+	Timeout time.Duration
+	// HTTPClient is used to reach the peers. NewPeerCache sets it to
+	// http.DefaultClient.
+	HTTPClient *http.Client
+
+	self  string
+	peers []string
+	local Cache
+
+	mu     sync.Mutex
+	source *Client
+	calls  map[string]*peerCall
+}
+
+// peerCall is a fetch on behalf of every miss of a key.
+type peerCall struct {
+	wg     sync.WaitGroup
+	record []byte
+	ok     bool
+}
+
+// NewPeerCache returns a cache for the instance whose handler is served
+// at the URL self, e.g. "http://10.0.0.1:8080/owm/cache", storing the keys
+// it owns in local. The peers are the handler URLs of every instance,
+// this one included, and must be the same on all of them.
+func NewPeerCache(self string, local Cache, peers ...string) *PeerCache {
+	return &PeerCache{
+		Timeout:    fallbackTimeout,
+		HTTPClient: http.DefaultClient,
+		self:       self,
+		peers:      peers,
+		local:      local,
+		calls:      make(map[string]*peerCall),
+	}
+}
+
+// Handler returns the handler answering the peers at the cache's URL.
+// Responses missing from the cache are fetched with the client, which
+// must use the cache through WithCache; its TTL applies to them.
+func (c *PeerCache) Handler(client *Client) http.Handler {
+	c.mu.Lock()
+	c.source = client
+	c.mu.Unlock()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.URL.Query().Get("key")
+		if key == "" {
+			http.Error(w, "missing key", http.StatusBadRequest)
+			return
+		}
+		record, ok := c.local.Get(key)
+		if !ok {
+			record, ok = c.load(r.Context(), key)
+		}
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(record)
+	})
+}
+
+// Get returns the body stored under key, asking its owner on a local miss.
+func (c *PeerCache) Get(key string) ([]byte, bool) {
+	if record, ok := c.local.Get(key); ok {
+		return record, true
+	}
+	owner := rendezvous(c.peers, key)
+	if owner == "" || owner == c.self {
+		return c.load(context.Background(), key)
+	}
+	return c.ask(owner, key)
+}
+
+// Set stores the body under key for ttl in the local cache.
+func (c *PeerCache) Set(key string, body []byte, ttl time.Duration) {
+	c.local.Set(key, body, ttl)
+}
+
+// ask requests the record of the key from the peer, counting any failure
+// as a miss.
+func (c *PeerCache) ask(peer, key string) ([]byte, bool) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if c.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), c.Timeout)
+	}
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, peer+"?key="+url.QueryEscape(key), nil)
+	if err != nil {
+		return nil, false
+	}
+	response, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, false
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, false
+	}
+	record, err := ioutil.ReadAll(response.Body)
+	return record, err == nil
+}
+
+// load fetches the response of the key from the API with the handler's
+// client, once for all concurrent misses, and returns its record. It
+// reports a miss without a client or when the response wasn't cached,
+// e.g. because the request failed.
+func (c *PeerCache) load(ctx context.Context, key string) ([]byte, bool) {
+	c.mu.Lock()
+	if call, ok := c.calls[key]; ok {
+		c.mu.Unlock()
+		call.wg.Wait()
+		return call.record, call.ok
+	}
+	source := c.source
+	if source == nil {
+		c.mu.Unlock()
+		return nil, false
+	}
+	call := &peerCall{}
+	call.wg.Add(1)
+	c.calls[key] = call
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		delete(c.calls, key)
+		c.mu.Unlock()
+		call.wg.Done()
+	}()
+
+	s := source.newSettings()
+	s.cache = c.local
+	if s.cacheTTL <= 0 {
+		return nil, false
+	}
+	u, err := url.Parse(key)
+	if err != nil {
+		return nil, false
+	}
+	q := u.Query()
+	q.Set("appid", source.key)
+	u.RawQuery = q.Encode()
+
+	safely(func() error {
+		response, err := s.fetch(ctx, peerEndpoint(u.Path), u.String())
+		if err != nil {
+			return err
+		}
+		return response.Body.Close()
+	})
+	call.record, call.ok = c.local.Get(key)
+	return call.record, call.ok
+}
+
+// peerPaths maps API paths to their endpoint family, for the timeout of
+// fetches on behalf of peers. Other paths are current weather lookups.
+var peerPaths = []struct {
+	prefix   string
+	endpoint Endpoint
+}{
+	{"/data/2.5/air_pollution", EndpointPollution},
+	{"/data/2.5/onecall", EndpointOneCall},
+	{"/data/3.0/onecall", EndpointOneCall},
+	{"/data/2.5/forecast", EndpointForecast},
+	{"/data/2.5/group", EndpointGroup},
+	{"/data/2.5/history", EndpointHistory},
+	{"/data/2.5/uvi", EndpointUV},
+	{"/geo/", EndpointGeocoding},
+}
+
+// peerEndpoint returns the endpoint family of the API path.
+func peerEndpoint(path string) Endpoint {
+	for _, p := range peerPaths {
+		if strings.HasPrefix(path, p.prefix) {
+			return p.endpoint
+		}
+	}
+	return EndpointCurrent
+}
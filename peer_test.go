@@ -0,0 +1,117 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestPeerCache will verify a cluster of instances sends a single request
+// to the API for a location, whichever instances ask and however often.
+func TestPeerCache(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		calls int
+	)
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		if r.URL.Query().Get("appid") == "" {
+			t.Error("expected the owner's API key")
+		}
+		time.Sleep(20 * time.Millisecond)
+		fmt.Fprint(w, `{"name":"London","main":{"temp":12}}`)
+	})
+	defer ts.Close()
+
+	handlers := make([]http.Handler, 3)
+	var urls []string
+	for i := range handlers {
+		i := i
+		peer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handlers[i].ServeHTTP(w, r)
+		}))
+		defer peer.Close()
+		urls = append(urls, peer.URL+"/owm/cache")
+	}
+	var clients []*Client
+	for i, u := range urls {
+		cache := NewPeerCache(u, NewMemoryCache(), urls...)
+		c, err := NewClient("key", WithHttpClient(hc), WithCache(cache, time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+		handlers[i] = cache.Handler(c)
+		clients = append(clients, c)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 12; i++ {
+		wg.Add(1)
+		go func(c *Client) {
+			defer wg.Done()
+			w, err := c.CurrentByName("London")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if w.Name != "London" || w.Main.Temp != 12 {
+				t.Errorf("unexpected result %+v", w)
+			}
+		}(clients[i%len(clients)])
+	}
+	wg.Wait()
+	mu.Lock()
+	defer mu.Unlock()
+	if calls != 1 {
+		t.Errorf("expected a single request cluster-wide, got %d", calls)
+	}
+}
+
+// TestPeerCacheUnreachable will verify an instance fetches the response
+// itself when the owner of the key can't be reached.
+func TestPeerCacheUnreachable(t *testing.T) {
+	calls := 0
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"name":"London","main":{"temp":12}}`)
+	})
+	defer ts.Close()
+
+	gone := httptest.NewServer(http.NotFoundHandler())
+	gone.Close()
+	cache := NewPeerCache("http://self/owm/cache", NewMemoryCache(), gone.URL)
+	c, err := NewClient("key", WithHttpClient(hc), WithCache(cache, time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := c.CurrentByName("London"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the response to be kept locally, got %d calls", calls)
+	}
+	if e := peerEndpoint("/data/2.5/air_pollution/forecast"); e != EndpointPollution {
+		t.Errorf("unexpected endpoint %s", e)
+	}
+}
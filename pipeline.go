@@ -0,0 +1,95 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"fmt"
+)
+
+// Stage is a named step of an enrichment pipeline, such as computing
+// derived metrics, localizing text or flagging suspect values.
+type Stage struct {
+	Name string
+	Run  PostDecodeHook
+}
+
+// Pipeline runs its stages in order on every decoded result.
+type Pipeline []Stage
+
+// Run passes the result through each stage, stopping at the first error
+// which is annotated with the name of the failing stage. A panicking
+// stage fails with a *PanicError.
+func (p Pipeline) Run(result interface{}) error {
+	for _, s := range p {
+		if err := safely(func() error { return s.Run(result) }); err != nil {
+			return fmt.Errorf("%s stage: %w", s.Name, err)
+		}
+	}
+	return nil
+}
+
+// WithPipeline registers an enrichment pipeline made of the given stages.
+// It runs as a post-decode hook, after any hook registered before it.
+func WithPipeline(stages ...Stage) Option {
+	return func(s *Settings) error {
+		for _, st := range stages {
+			if st.Run == nil {
+				return errInvalidOption
+			}
+		}
+		s.hooks = append(s.hooks, Pipeline(stages).Run)
+		return nil
+	}
+}
+
+// CurrentStage returns a stage applying fn to current weather results,
+// including every entry of a group result. Other results pass through.
+func CurrentStage(name string, fn func(w *CurrentWeatherData) error) Stage {
+	return Stage{
+		Name: name,
+		Run: func(result interface{}) error {
+			switch r := result.(type) {
+			case *CurrentWeatherData:
+				return fn(r)
+			case *CurrentWeatherGroup:
+				for _, w := range r.List {
+					if err := fn(w); err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// SanitizeStage clamps percentages reported out of range, like a humidity
+// above 100, back into the 0 to 100 range.
+var SanitizeStage = CurrentStage("sanitize", func(w *CurrentWeatherData) error {
+	w.Main.Humidity = clampPercent(w.Main.Humidity)
+	w.Clouds.All = clampPercent(w.Clouds.All)
+	return nil
+})
+
+// clampPercent limits v to the 0 to 100 range.
+func clampPercent(v int) int {
+	if v < 0 {
+		return 0
+	}
+	if v > 100 {
+		return 100
+	}
+	return v
+}
@@ -0,0 +1,65 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestWithPipeline will verify stages run in order on every entry of a
+// group and that failures name the stage.
+func TestWithPipeline(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"cnt":2,"list":[{"id":1,"name":"A","main":{"humidity":130}},{"id":2,"name":"B","main":{"humidity":-4},"clouds":{"all":120}}]}`)
+	})
+	defer ts.Close()
+
+	var names []string
+	label := CurrentStage("label", func(w *CurrentWeatherData) error {
+		names = append(names, fmt.Sprintf("%s:%d", w.Name, w.Main.Humidity))
+		return nil
+	})
+
+	g, err := NewCurrentGroup("c", "EN", "key", WithHttpClient(hc), WithPipeline(SanitizeStage, label))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CurrentByIDs(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(names, ","); got != "A:100,B:0" {
+		t.Errorf("expected sanitized values labeled in order, got %s", got)
+	}
+	if g.List[1].Clouds.All != 100 {
+		t.Errorf("expected clouds clamped to 100, got %d", g.List[1].Clouds.All)
+	}
+
+	failing := Stage{Name: "lookup", Run: func(interface{}) error { return errors.New("unavailable") }}
+	g, err = NewCurrentGroup("c", "EN", "key", WithHttpClient(hc), WithPipeline(failing))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.CurrentByIDs(1, 2); err == nil || err.Error() != "lookup stage: unavailable" {
+		t.Errorf("expected stage error, got %v", err)
+	}
+
+	if _, err := NewCurrentGroup("c", "EN", "key", WithPipeline(Stage{Name: "empty"})); err != errInvalidOption {
+		t.Errorf("expected errInvalidOption, got %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"time"
+)
+
+// maxPollBackoff caps the delay between polls after repeated failures.
+const maxPollBackoff = time.Hour
+
+// Poll calls fetch every interval until ctx is done, sending the result
+// on the returned channel whenever its data changed since the last one
+// sent. fetch makes the request, e.g.
+//
+//	updates := w.Poll(ctx, 10*time.Minute, func(ctx context.Context, w *owm.CurrentWeatherData) error {
+//		return w.CurrentByIDCtx(ctx, 2643743)
+//	})
+//
+// Each call gets a fresh copy of w, so results sent aren't changed by later
+// polls and w itself can still be used. After a failure, which is only
+// reported by the events published for the request, the delay doubles for
+// each consecutive failure up to an hour; a panic in fetch counts as a
+// failure. The channel is closed once ctx is done. An interval that isn't
+// positive would poll the API in a tight loop, so the channel is then
+// closed right away without fetching anything.
+func (w *CurrentWeatherData) Poll(ctx context.Context, interval time.Duration, fetch func(ctx context.Context, w *CurrentWeatherData) error) <-chan *CurrentWeatherData {
+	updates := make(chan *CurrentWeatherData)
+	if interval <= 0 {
+		close(updates)
+		return updates
+	}
+	settings := *w.Settings
+	go func() {
+		defer close(updates)
+		last := ""
+		failures := 0
+		for {
+			s := settings
+			next := &CurrentWeatherData{Unit: w.Unit, Lang: w.Lang, Key: w.Key, Settings: &s}
+			err := safely(func() error { return fetch(ctx, next) })
+			settings = s
+
+			delay := interval
+			if err != nil {
+				failures++
+				delay = pollBackoff(interval, failures)
+			} else {
+				failures = 0
+			}
+			if err == nil && s.Checksum() != last {
+				last = s.Checksum()
+				select {
+				case updates <- next:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if settings.sleep(ctx, delay) != nil {
+				return
+			}
+		}
+	}()
+	return updates
+}
+
+// pollBackoff returns the delay before polling again after the given
+// number of consecutive failures.
+func pollBackoff(interval time.Duration, failures int) time.Duration {
+	d := interval
+	for i := 0; i < failures && d < maxPollBackoff; i++ {
+		d *= 2
+	}
+	if d > maxPollBackoff && interval < maxPollBackoff {
+		d = maxPollBackoff
+	}
+	return d
+}
@@ -0,0 +1,92 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestPoll will verify only changed data is sent and failures back off.
+func TestPoll(t *testing.T) {
+	responses := []string{`{"main":{"temp":1}}`, `{"main":{"temp":1}}`, "", "", `{"main":{"temp":2}}`, "panic"}
+	calls := 0
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		body := responses[calls%len(responses)]
+		calls++
+		if body == "" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, body)
+	})
+	defer ts.Close()
+
+	c, err := NewCurrent("C", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var delays []time.Duration
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	updates := c.Poll(ctx, time.Minute, func(ctx context.Context, w *CurrentWeatherData) error {
+		if calls == 5 {
+			calls++
+			panic("bad payload")
+		}
+		return w.CurrentByIDCtx(ctx, 1)
+	})
+
+	first := <-updates
+	second := <-updates
+	cancel()
+	for range updates {
+	}
+	if first.Main.Temp != 1 || second.Main.Temp != 2 {
+		t.Errorf("expected temperatures 1 and 2, got %v and %v", first.Main.Temp, second.Main.Temp)
+	}
+	if c.Checksum() != "" {
+		t.Error("expected polling to leave the original untouched")
+	}
+	want := []time.Duration{time.Minute, time.Minute, 2 * time.Minute, 4 * time.Minute}
+	if len(delays) < len(want) || fmt.Sprint(delays[:len(want)]) != fmt.Sprint(want) {
+		t.Errorf("expected delays %v, got %v", want, delays)
+	}
+
+	if d := pollBackoff(20*time.Minute, 3); d != time.Hour {
+		t.Errorf("expected the backoff to be capped at an hour, got %v", d)
+	}
+
+	for _, interval := range []time.Duration{0, -time.Minute} {
+		fetched := false
+		for range c.Poll(context.Background(), interval, func(ctx context.Context, w *CurrentWeatherData) error {
+			fetched = true
+			return nil
+		}) {
+			t.Error("expected no updates")
+		}
+		if fetched {
+			t.Errorf("expected an interval of %v not to poll", interval)
+		}
+	}
+}
@@ -1,10 +1,11 @@
 package openweathermap
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"strconv"
+	"time"
 )
 
 // DateTimeAliases holds the alias the pollution API supports in lieu
@@ -60,41 +61,89 @@ type PollutionData struct {
 
 // NewPollution creates a new reference to Pollution
 func NewPollution(key string, options ...Option) (*Pollution, error) {
-	k, err := setKey(key)
+	c, err := NewClient(key, options...)
 	if err != nil {
 		return nil, err
 	}
-	p := &Pollution{
-		Key:      k,
-		Settings: NewSettings(),
-	}
-
-	if err := setOptions(p.Settings, options); err != nil {
-		return nil, err
-	}
-	return p, nil
+	return c.Pollution(), nil
 }
 
 // PollutionByParams gets the pollution data based on the given parameters
 func (p *Pollution) PollutionByParams(params *PollutionParameters) error {
-	url := fmt.Sprintf(pollutionURL,
+	return p.PollutionByParamsCtx(context.Background(), params)
+}
+
+// PollutionByParamsCtx is like PollutionByParams but the request is
+// bound to ctx, which cancels it or sets its deadline.
+func (p *Pollution) PollutionByParamsCtx(ctx context.Context, params *PollutionParameters) error {
+	return p.fetch(ctx, fmt.Sprintf(pollutionURL,
 		p.Key,
 		strconv.FormatFloat(params.Location.Latitude, 'f', -1, 64),
 		strconv.FormatFloat(params.Location.Longitude, 'f', -1, 64),
-	)
-	response, err := p.client.Get(url)
+	))
+}
+
+// Forecast gets the hourly air quality forecast for the next days at the
+// coordinates.
+func (p *Pollution) Forecast(coord *Coordinates) error {
+	return p.ForecastCtx(context.Background(), coord)
+}
+
+// ForecastCtx is like Forecast but the request is bound to ctx, which
+// cancels it or sets its deadline.
+func (p *Pollution) ForecastCtx(ctx context.Context, coord *Coordinates) error {
+	return p.fetch(ctx, fmt.Sprintf(pollutionForecastURL,
+		p.Key,
+		strconv.FormatFloat(coord.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(coord.Longitude, 'f', -1, 64),
+	))
+}
+
+// Historical gets the hourly air quality at the coordinates between start
+// and end.
+func (p *Pollution) Historical(coord *Coordinates, start, end time.Time) error {
+	return p.HistoricalCtx(context.Background(), coord, start, end)
+}
+
+// HistoricalCtx is like Historical but the request is bound to ctx, which
+// cancels it or sets its deadline.
+func (p *Pollution) HistoricalCtx(ctx context.Context, coord *Coordinates, start, end time.Time) error {
+	return p.fetch(ctx, fmt.Sprintf(pollutionHistoryURL,
+		p.Key,
+		strconv.FormatFloat(coord.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(coord.Longitude, 'f', -1, 64),
+		start.Unix(),
+		end.Unix(),
+	))
+}
+
+// fetch requests the url and decodes the response into p.
+func (p *Pollution) fetch(ctx context.Context, url string) error {
+	response, err := p.get(ctx, EndpointPollution, url)
 	if err != nil {
 		return err
 	}
 	defer response.Body.Close()
 
-	if response.StatusCode == http.StatusUnauthorized {
-		return errInvalidKey
-	}
-
 	if err = json.NewDecoder(response.Body).Decode(&p); err != nil {
 		return err
 	}
 
-	return nil
+	return p.postDecode(p)
+}
+
+// AQIQualities maps the air quality index to its qualitative name as
+// defined by OWM.
+var AQIQualities = map[int]string{
+	1: "Good",
+	2: "Fair",
+	3: "Moderate",
+	4: "Poor",
+	5: "Very Poor",
+}
+
+// Quality returns the qualitative name of the air quality index, or an
+// empty string if the index is unknown.
+func (d PollutionData) Quality() string {
+	return AQIQualities[int(d.Main.Aqi)]
 }
@@ -108,3 +108,38 @@ func TestPollutionByParams(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+// TestPollutionForecastAndHistory will verify the forecast and history
+// endpoints are requested with the coordinates and time range and decoded.
+func TestPollutionForecastAndHistory(t *testing.T) {
+	var paths []string
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path+"?"+r.URL.Query().Get("lat")+","+r.URL.Query().Get("start"))
+		w.Write([]byte(`{"coord":{"lon":10,"lat":50},"list":[{"dt":1700000000,"main":{"aqi":2},"components":{"co":201.94,"pm2_5":3.2}},{"dt":1700003600,"main":{"aqi":4},"components":{"pm10":60}}]}`))
+	})
+	defer ts.Close()
+
+	p, err := NewPollution("key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	coord := &Coordinates{Latitude: 50, Longitude: 10}
+	if err := p.Forecast(coord); err != nil {
+		t.Fatal(err)
+	}
+	if len(p.List) != 2 || p.List[0].Components.Co != 201.94 || p.List[1].Quality() != "Poor" {
+		t.Errorf("unexpected forecast %+v", p.List)
+	}
+
+	start := time.Unix(1700000000, 0)
+	if err := p.Historical(coord, start, start.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"/data/2.5/air_pollution/forecast?50,", "/data/2.5/air_pollution/history?50,1700000000"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected requests %v, got %v", expected, paths)
+	}
+	if (PollutionData{}).Quality() != "" {
+		t.Error("expected no quality for an unknown index")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"encoding/json"
+)
+
+// PreciseValues holds pressure and precipitation values exactly as sent by
+// the API. Unlike the float64 fields they can be written back out without
+// picking up floating point noise, which keeps diffs of exported data
+// clean. An empty Number means the API didn't send the value.
+type PreciseValues struct {
+	Main PrecisePressure      `json:"main"`
+	Rain PrecisePrecipitation `json:"rain"`
+	Snow PrecisePrecipitation `json:"snow"`
+}
+
+// PrecisePressure holds the exact pressure readings.
+type PrecisePressure struct {
+	Pressure  json.Number `json:"pressure,omitempty"`
+	SeaLevel  json.Number `json:"sea_level,omitempty"`
+	GrndLevel json.Number `json:"grnd_level,omitempty"`
+}
+
+// PrecisePrecipitation holds the exact precipitation volumes.
+type PrecisePrecipitation struct {
+	OneH   json.Number `json:"1h,omitempty"`
+	ThreeH json.Number `json:"3h,omitempty"`
+}
+
+// WithPreciseNumbers makes requests also fill the Precise field with the
+// exact pressure and precipitation values: of current weather, of every
+// city of a CurrentWeatherGroup, of every entry of the 5 day forecast and
+// of every entry of the history. The other endpoints don't send these
+// values in this layout and their results have no Precise field.
+func WithPreciseNumbers() Option {
+	return func(s *Settings) error {
+		s.precise = true
+		return nil
+	}
+}
+
+// preciseEntries decodes the precise values of every entry of the list of
+// a response, in order.
+func preciseEntries(b []byte) ([]*PreciseValues, error) {
+	var v struct {
+		List []*PreciseValues `json:"list"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return v.List, nil
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+// TestWithPreciseNumbers will verify the exact pressure and precipitation
+// values are kept when requested.
+func TestWithPreciseNumbers(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"main":{"temp":3.1,"pressure":1013.30,"sea_level":1013.3},"rain":{"1h":0.10}}`)
+	})
+	defer ts.Close()
+
+	c, err := NewCurrent("c", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CurrentByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if c.Precise != nil {
+		t.Error("expected no precise values unless requested")
+	}
+
+	c, err = NewCurrent("c", "EN", "key", WithHttpClient(hc), WithPreciseNumbers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CurrentByID(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.Precise == nil {
+		t.Fatal("expected precise values")
+	}
+	if c.Precise.Main.Pressure != "1013.30" || c.Precise.Rain.OneH != "0.10" {
+		t.Errorf("unexpected precise values %+v", c.Precise)
+	}
+	if c.Precise.Main.GrndLevel != "" || c.Precise.Snow.ThreeH != "" {
+		t.Errorf("expected missing values to be empty, got %+v", c.Precise)
+	}
+	if c.Main.Pressure != 1013.3 {
+		t.Errorf("expected float pressure 1013.3, got %v", c.Main.Pressure)
+	}
+}
+
+// TestPreciseNumbersLists will verify the exact values are kept for every
+// entry of group, forecast and history responses.
+func TestPreciseNumbersLists(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"cnt":2,"list":[{"id":1,"dt":1,"main":{"pressure":1013.30},"rain":{"3h":0.10}},{"id":2,"dt":2,"main":{"pressure":998.0}}]}`)
+	})
+	defer ts.Close()
+
+	c, err := NewClient("key", WithHttpClient(hc), WithPreciseNumbers())
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(name string, precise []*PreciseValues) {
+		if len(precise) != 2 || precise[0] == nil || precise[1] == nil {
+			t.Fatalf("%s: expected precise values for every entry, got %v", name, precise)
+		}
+		if precise[0].Main.Pressure != "1013.30" || precise[0].Rain.ThreeH != "0.10" || precise[1].Main.Pressure != "998.0" {
+			t.Errorf("%s: unexpected precise values %+v %+v", name, precise[0], precise[1])
+		}
+	}
+
+	g := c.CurrentGroup()
+	if err := g.CurrentByIDs(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	check("group", []*PreciseValues{g.List[0].Precise, g.List[1].Precise})
+
+	f := c.Forecast5()
+	if err := f.DailyByID(1, 2); err != nil {
+		t.Fatal(err)
+	}
+	list := f.ForecastWeatherJson.(*Forecast5WeatherData).List
+	check("forecast", []*PreciseValues{list[0].Precise, list[1].Precise})
+
+	h := c.History()
+	if err := h.HistoryByID(1); err != nil {
+		t.Fatal(err)
+	}
+	check("history", []*PreciseValues{h.List[0].Precise, h.List[1].Precise})
+}
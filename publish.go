@@ -0,0 +1,145 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Publisher sends a message to a subject or topic. It matches the Publish
+// method of a NATS connection and is a one line adapter for Kafka
+// producers.
+type Publisher interface {
+	Publish(subject string, data []byte) error
+}
+
+// SnapshotPublisher publishes decoded results and change events for event
+// driven consumers. Results go to "<prefix>.<kind>", e.g. weather.current,
+// and DataChanged events to "<prefix>.changed".
+type SnapshotPublisher struct {
+	// Encode serializes the published values and defaults to JSON.
+	Encode func(v interface{}) ([]byte, error)
+
+	pub    Publisher
+	prefix string
+}
+
+// NewSnapshotPublisher returns a publisher using subjects starting with
+// prefix.
+func NewSnapshotPublisher(pub Publisher, prefix string) *SnapshotPublisher {
+	return &SnapshotPublisher{Encode: json.Marshal, pub: pub, prefix: prefix}
+}
+
+// Stage returns a pipeline stage publishing every result as it's decoded.
+// A failed publish is returned by the request method, with the result
+// still populated.
+func (p *SnapshotPublisher) Stage() Stage {
+	return Stage{
+		Name: "publish",
+		Run: func(result interface{}) error {
+			return p.publish(snapshotKind(result), withoutKey(result))
+		},
+	}
+}
+
+// Subscribe publishes the DataChanged events of the bus. Failures are
+// dropped as there is no request to report them to.
+func (p *SnapshotPublisher) Subscribe(b *Bus) {
+	b.Subscribe(func(e Event) {
+		if c, ok := e.(DataChanged); ok {
+			p.publish("changed", c)
+		}
+	})
+}
+
+// publish encodes v and sends it to the subject of the given kind.
+func (p *SnapshotPublisher) publish(kind string, v interface{}) error {
+	b, err := p.Encode(v)
+	if err != nil {
+		return err
+	}
+	subject := kind
+	if p.prefix != "" {
+		subject = p.prefix + "." + kind
+	}
+	return p.pub.Publish(subject, b)
+}
+
+// snapshotKind names the subject suffix for a result.
+func snapshotKind(result interface{}) string {
+	switch result.(type) {
+	case *CurrentWeatherData:
+		return "current"
+	case *CurrentWeatherGroup:
+		return "group"
+	case *ForecastWeatherData:
+		return "forecast"
+	case *OneCallData:
+		return "onecall"
+	case *HistoricalWeatherData:
+		return "history"
+	case *Pollution:
+		return "pollution"
+	case *UV:
+		return "uv"
+	case *TimeMachineData:
+		return "timemachine"
+	case *Geocoding:
+		return "geocoding"
+	}
+	return "snapshot"
+}
+
+// withoutKey returns a copy of the result with the API key cleared so it
+// isn't published along with the data.
+func withoutKey(result interface{}) interface{} {
+	return withoutFields(result, "Key")
+}
+
+// withoutFields returns a copy of the result, a pointer to a struct, with
+// the named fields zeroed. Slices of such results, e.g. the members of a
+// CurrentWeatherGroup, are copied the same way. Any other value is
+// returned as is.
+func withoutFields(result interface{}, names ...string) interface{} {
+	v := reflect.ValueOf(result)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return result
+	}
+	c := reflect.New(v.Elem().Type())
+	c.Elem().Set(v.Elem())
+
+	s := c.Elem()
+	for _, name := range names {
+		// only the struct's own fields, not those promoted from Settings
+		if f, ok := s.Type().FieldByName(name); ok && len(f.Index) == 1 && f.PkgPath == "" {
+			s.Field(f.Index[0]).Set(reflect.Zero(f.Type))
+		}
+	}
+	for i := 0; i < s.NumField(); i++ {
+		f := s.Field(i)
+		t := f.Type()
+		if t.Kind() != reflect.Slice || f.IsNil() || !f.CanSet() ||
+			t.Elem().Kind() != reflect.Ptr || t.Elem().Elem().Kind() != reflect.Struct {
+			continue
+		}
+		l := reflect.MakeSlice(t, f.Len(), f.Len())
+		for j := 0; j < f.Len(); j++ {
+			l.Index(j).Set(reflect.ValueOf(withoutFields(f.Index(j).Interface(), names...)))
+		}
+		f.Set(l)
+	}
+	return c.Interface()
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// memoryPublisher records published messages by subject.
+type memoryPublisher map[string][]string
+
+func (m memoryPublisher) Publish(subject string, data []byte) error {
+	m[subject] = append(m[subject], string(data))
+	return nil
+}
+
+// TestSnapshotPublisher will verify results and change events are
+// published without the API key.
+func TestSnapshotPublisher(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"id":1,"name":"Oslo","main":{"temp":-2}}`)
+	})
+	defer ts.Close()
+
+	pub := memoryPublisher{}
+	sp := NewSnapshotPublisher(pub, "weather")
+	bus := NewBus()
+	sp.Subscribe(bus)
+
+	c, err := NewCurrent("c", "EN", "secret", WithHttpClient(hc), WithEventBus(bus), WithPipeline(sp.Stage()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := c.CurrentByID(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(pub["weather.current"]) != 2 || len(pub["weather.changed"]) != 1 {
+		t.Fatalf("unexpected messages %v", pub)
+	}
+	msg := pub["weather.current"][0]
+	if !strings.Contains(msg, `"name":"Oslo"`) || strings.Contains(msg, "secret") {
+		t.Errorf("unexpected snapshot %s", msg)
+	}
+	if c.Key != "secret" {
+		t.Error("expected the client key to be left untouched")
+	}
+}
+
+// TestWithoutKeyCoversEveryResult will verify no result type built by a
+// Client, now or added later, is published with its API key.
+func TestWithoutKeyCoversEveryResult(t *testing.T) {
+	c, err := NewClient("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v := reflect.ValueOf(c)
+	for i := 0; i < v.NumMethod(); i++ {
+		m := v.Type().Method(i)
+		if m.Type.NumIn() != 1 && !(m.Type.NumIn() == 2 && m.Type.IsVariadic()) {
+			continue
+		}
+		r := v.Method(i).Call(nil)[0].Interface()
+		if s := fmt.Sprintf("%+v", r); !strings.Contains(s, "secret") {
+			continue
+		}
+		if s := fmt.Sprintf("%+v", withoutKey(r)); strings.Contains(s, "secret") {
+			t.Errorf("%s: %T still holds the key: %s", m.Name, r, s)
+		}
+		if s := fmt.Sprintf("%+v", r); !strings.Contains(s, "secret") {
+			t.Errorf("%s: expected the key of the result to be left untouched", m.Name)
+		}
+	}
+
+	g := c.CurrentGroup()
+	g.List = []*CurrentWeatherData{c.Current()}
+	s := withoutKey(g).(*CurrentWeatherGroup)
+	if s.Key != "" || s.List[0].Key != "" {
+		t.Errorf("expected the group and its members without the key, got %+v", s)
+	}
+	if g.List[0].Key != "secret" {
+		t.Error("expected the group members to be left untouched")
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// The tests below exercise the types shared between goroutines, and are
+// meant to be run with go test -race.
+
+// parallel runs fn on n goroutines at once and waits for them.
+func parallel(n int, fn func(i int)) {
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			fn(i)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+}
+
+// TestConcurrentClients will verify clients sharing an http client, bus,
+// rate limiter and cache can be used from many goroutines.
+func TestConcurrentClients(t *testing.T) {
+	var calls int32
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		fmt.Fprintf(w, `{"id":1,"name":%q,"weather":[{"id":500,"description":"light rain"}]}`, r.URL.Query().Get("q"))
+	})
+	defer ts.Close()
+
+	bus := NewBus()
+	var events int32
+	bus.Subscribe(func(Event) { atomic.AddInt32(&events, 1) })
+	cache := NewMemoryCache()
+	limiter := NewRateLimiter(1000, time.Second)
+	options := []Option{WithHttpClient(hc), WithEventBus(bus), WithCache(cache, time.Minute), WithRateLimiter(limiter, true)}
+
+	errs := make(chan error, 64)
+	parallel(64, func(i int) {
+		c, err := NewCurrent("C", "EN", "key", options...)
+		if err != nil {
+			errs <- err
+			return
+		}
+		city := fmt.Sprint("City", i%8)
+		if err := c.CurrentByName(city); err != nil {
+			errs <- err
+			return
+		}
+		if c.Name != city {
+			errs <- fmt.Errorf("expected %s, got %s", city, c.Name)
+		}
+		bus.Subscribe(func(Event) {})
+	})
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if n := atomic.LoadInt32(&calls); n < 8 || n > 64 {
+		t.Errorf("unexpected number of requests %d", n)
+	}
+	if atomic.LoadInt32(&events) == 0 {
+		t.Error("expected events to be published")
+	}
+}
+
+// TestConcurrentBusSubscribe will verify subscribers may subscribe from
+// within a delivery without deadlocking.
+func TestConcurrentBusSubscribe(t *testing.T) {
+	bus := NewBus()
+	var nested int32
+	bus.Subscribe(func(Event) {
+		bus.Subscribe(func(Event) { atomic.AddInt32(&nested, 1) })
+	})
+
+	done := make(chan struct{})
+	go func() {
+		parallel(8, func(int) { bus.publish(RequestStarted{Endpoint: EndpointCurrent}) })
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("publishing deadlocked")
+	}
+	bus.publish(RequestStarted{})
+	if atomic.LoadInt32(&nested) < 8 {
+		t.Errorf("expected nested subscribers to receive later events, got %d", nested)
+	}
+}
+
+// TestConcurrentSharedState will verify the registry, cache, limiter,
+// trackers and digester stay consistent under parallel use.
+func TestConcurrentSharedState(t *testing.T) {
+	cache := NewMemoryCache()
+	limiter := NewRateLimiter(100, time.Hour)
+	tracker := NewAlertTracker(NewBus())
+	router, err := NewAlertRouter(time.UTC, AlertRule{Name: "all", Route: "log"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var routed int32
+	router.Handle("log", func(OneCallAlertData) error {
+		atomic.AddInt32(&routed, 1)
+		router.Handle("other", func(OneCallAlertData) error { return nil })
+		return nil
+	})
+	digester := NewDigester(time.Minute, func(Digest) error { return nil })
+	var allowed int32
+	now := time.Now()
+
+	parallel(32, func(i int) {
+		key := fmt.Sprint(i % 4)
+		cache.Set(key, []byte(key), time.Minute)
+		cache.Get(key)
+		if limiter.Allow() {
+			atomic.AddInt32(&allowed, 1)
+		}
+		RegisterConditionDescriptions("DE", map[int]string{500 + i%4: "leichter Regen"})
+		LookupCondition(500)
+		registeredDescription("DE", 500)
+
+		alert := OneCallAlertData{Event: fmt.Sprint("Alert", i%4), Start: int(now.Unix()), End: int(now.Add(time.Hour).Unix())}
+		tracker.Observe([]OneCallAlertData{alert}, now)
+		tracker.Active()
+		router.Route(alert, now)
+
+		digester.Add(key, DigestItem{})
+		digester.Flush(now)
+	})
+	if allowed != 32 {
+		t.Errorf("expected every request allowed, got %d", allowed)
+	}
+	if routed != 32 {
+		t.Errorf("expected every alert routed, got %d", routed)
+	}
+	if err := digester.FlushAll(); err != nil {
+		t.Error(err)
+	}
+}
+
+// TestConcurrentFetchGroup will verify a group's fetches run concurrently
+// without racing on the report.
+func TestConcurrentFetchGroup(t *testing.T) {
+	g := NewFetchGroup(context.Background())
+	for i := 0; i < 16; i++ {
+		g.AddOptional(fmt.Sprint(i), func(context.Context) error { return nil })
+	}
+	g.Add("last", func(context.Context) error { return nil }, "0", "1", "2")
+	report, err := g.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Succeeded("last") {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
+
+// TestConcurrentClientQueries will verify a single client can be queried
+// from many goroutines, each getting its own result.
+func TestConcurrentClientQueries(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":%s,"name":"City %[1]s"}`, r.URL.Query().Get("id"))
+	})
+	defer ts.Close()
+
+	c, err := NewClient("key", WithHttpClient(hc), WithEventBus(NewBus()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := make([]*CurrentWeatherData, 16)
+	parallel(len(results), func(i int) {
+		w, err := c.CurrentByID(i + 1)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		results[i] = w
+	})
+	for i, w := range results {
+		if w == nil || w.ID != i+1 || w.Name != fmt.Sprintf("City %d", i+1) {
+			t.Errorf("unexpected result %d: %+v", i, w)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var errRateLimited = errors.New("client rate limit exceeded")
+
+// RateLimiter is a token bucket keeping requests under an API quota. A
+// RateLimiter may be shared by every client using the same key, through
+// WithRateLimiter, and is safe for concurrent use.
+type RateLimiter struct {
+	mu       sync.Mutex
+	tokens   float64
+	burst    float64
+	interval time.Duration
+	last     time.Time
+	now      func() time.Time
+}
+
+// NewRateLimiter returns a limiter allowing calls requests per period,
+// which may all be sent in a burst. Free tier keys allow 60 calls a
+// minute.
+func NewRateLimiter(calls int, per time.Duration) *RateLimiter {
+	if calls < 1 {
+		calls = 1
+	}
+	return &RateLimiter{
+		tokens:   float64(calls),
+		burst:    float64(calls),
+		interval: per / time.Duration(calls),
+		now:      time.Now,
+	}
+}
+
+// WithRateLimiter makes the client take a token from the limiter before
+// each request, retries included. Blocking clients wait for a token to be
+// available, or for their context to be done; the others fail with
+// errRateLimited without sending the request.
+func WithRateLimiter(l *RateLimiter, blocking bool) Option {
+	return func(s *Settings) error {
+		if l == nil {
+			return errInvalidOption
+		}
+		s.limiter = l
+		s.blocking = blocking
+		return nil
+	}
+}
+
+// Priority ranks requests waiting for a blocking rate limiter.
+type Priority int
+
+// Request priorities. Interactive requests, the default, take the next
+// token as soon as the quota allows, ahead of background requests such as
+// grid sampling or history backfills, which only take tokens nobody else
+// claimed.
+const (
+	PriorityInteractive Priority = iota
+	PriorityBackground
+)
+
+type priorityKey struct{}
+
+// WithPriority sets the priority of the client's requests.
+func WithPriority(p Priority) Option {
+	return func(s *Settings) error {
+		if p != PriorityInteractive && p != PriorityBackground {
+			return errInvalidOption
+		}
+		s.priority = p
+		return nil
+	}
+}
+
+// ContextWithPriority returns a context overriding the client's priority
+// for the requests bound to it.
+func ContextWithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// priorityOf returns the priority of a request bound to ctx.
+func (s *Settings) priorityOf(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return p
+	}
+	return s.priority
+}
+
+// refill adds the tokens accrued since the last call. The caller must
+// hold the lock.
+func (l *RateLimiter) refill() {
+	now := l.now()
+	if !l.last.IsZero() && l.interval > 0 {
+		l.tokens += float64(now.Sub(l.last)) / float64(l.interval)
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+}
+
+// Allow takes a token if one is available.
+func (l *RateLimiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// claim takes a token if one is free, leaving the tokens reserved ahead
+// of time by interactive requests alone. Otherwise it returns how long
+// until one may be.
+func (l *RateLimiter) claim() (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - l.tokens) * float64(l.interval)), false
+}
+
+// reserve takes a token, possibly ahead of time, and returns how long to
+// wait before using it.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill()
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens * float64(l.interval))
+}
+
+// release gives back a token reserved but not used.
+func (l *RateLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.tokens++
+}
+
+// throttle takes a token from the configured limiter, if any, waiting
+// for it when blocking. Interactive requests reserve the next token and
+// wait for it, while background ones wait until a token is free.
+func (s *Settings) throttle(ctx context.Context) error {
+	if s.limiter == nil {
+		return nil
+	}
+	if !s.blocking {
+		if !s.limiter.Allow() {
+			return errRateLimited
+		}
+		return nil
+	}
+	if s.priorityOf(ctx) == PriorityBackground {
+		for {
+			d, ok := s.limiter.claim()
+			if ok {
+				return nil
+			}
+			if err := s.sleep(ctx, d); err != nil {
+				return err
+			}
+		}
+	}
+	d := s.limiter.reserve()
+	if d <= 0 {
+		return nil
+	}
+	if err := s.sleep(ctx, d); err != nil {
+		s.limiter.release()
+		return err
+	}
+	return nil
+}
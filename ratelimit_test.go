@@ -0,0 +1,168 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestRateLimiter will verify tokens are spent and refilled at the
+// configured rate.
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewRateLimiter(2, time.Minute)
+	l.now = func() time.Time { return now }
+
+	if !l.Allow() || !l.Allow() {
+		t.Fatal("expected the burst to be allowed")
+	}
+	if l.Allow() {
+		t.Fatal("expected the bucket to be empty")
+	}
+	now = now.Add(30 * time.Second)
+	if !l.Allow() || l.Allow() {
+		t.Error("expected a single token after half a minute")
+	}
+	if d := l.reserve(); d != 30*time.Second {
+		t.Errorf("expected to wait 30s, got %v", d)
+	}
+	if d := l.reserve(); d != time.Minute {
+		t.Errorf("expected to wait 1m, got %v", d)
+	}
+}
+
+// TestWithRateLimiter will verify clients sharing a limiter share its
+// quota, failing or waiting once it is used up.
+func TestWithRateLimiter(t *testing.T) {
+	calls := 0
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"name":"Oslo"}`)
+	})
+	defer ts.Close()
+
+	now := time.Unix(0, 0)
+	l := NewRateLimiter(1, time.Minute)
+	l.now = func() time.Time { return now }
+
+	c, err := NewCurrent("C", "EN", "key", WithHttpClient(hc), WithRateLimiter(l, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewForecast("5", "C", "EN", "key", WithHttpClient(hc), WithRateLimiter(l, false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CurrentByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.DailyByID(1, 1); err != errRateLimited {
+		t.Errorf("expected %v, got %v", errRateLimited, err)
+	}
+	if calls != 1 {
+		t.Errorf("expected a single request sent, got %d", calls)
+	}
+
+	b, err := NewCurrent("C", "EN", "key", WithHttpClient(hc), WithRateLimiter(l, true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var slept []time.Duration
+	b.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return ctx.Err()
+	}
+	if err := b.CurrentByID(1); err != nil || calls != 2 {
+		t.Fatalf("expected the blocking client to wait and send, got %v", err)
+	}
+	if len(slept) != 1 || slept[0] != time.Minute {
+		t.Errorf("expected to wait 1m, got %v", slept)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.CurrentByIDCtx(ctx, 1); err != context.Canceled || calls != 2 {
+		t.Errorf("expected the wait to be canceled, got %v", err)
+	}
+	if d := l.reserve(); d != 2*time.Minute {
+		t.Errorf("expected the canceled token to be released, got %v", d)
+	}
+
+	if _, err := NewCurrent("C", "EN", "key", WithRateLimiter(nil, true)); err != errInvalidOption {
+		t.Errorf("expected %v, got %v", errInvalidOption, err)
+	}
+}
+
+// TestRateLimiterPriority will verify interactive requests take the next
+// token ahead of background requests waiting for it.
+func TestRateLimiterPriority(t *testing.T) {
+	var order []string
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, r.URL.Query().Get("id"))
+		fmt.Fprint(w, `{"name":"Oslo"}`)
+	})
+	defer ts.Close()
+
+	now := time.Unix(0, 0)
+	l := NewRateLimiter(1, time.Minute)
+	l.now = func() time.Time { return now }
+
+	options := []Option{WithHttpClient(hc), WithRateLimiter(l, true)}
+	interactive, err := NewCurrent("C", "EN", "key", options...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	interactive.sleep = func(ctx context.Context, d time.Duration) error { return nil }
+	background, err := NewCurrent("C", "EN", "key", append(options, WithPriority(PriorityBackground))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var slept []time.Duration
+	background.sleep = func(ctx context.Context, d time.Duration) error {
+		if len(slept) == 0 {
+			if err := interactive.CurrentByID(2); err != nil {
+				t.Error(err)
+			}
+		}
+		slept = append(slept, d)
+		now = now.Add(d)
+		return nil
+	}
+
+	if err := interactive.CurrentByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if err := background.CurrentByID(3); err != nil {
+		t.Fatal(err)
+	}
+	if fmt.Sprint(order) != "[1 2 3]" {
+		t.Errorf("expected the interactive request first, got %v", order)
+	}
+	if fmt.Sprint(slept) != "[1m0s 1m0s]" {
+		t.Errorf("expected the background request to wait twice, got %v", slept)
+	}
+
+	ctx := ContextWithPriority(context.Background(), PriorityBackground)
+	if background.priorityOf(ctx) != PriorityBackground || interactive.priorityOf(ctx) != PriorityBackground {
+		t.Error("expected the context to set the priority")
+	}
+	if _, err := NewCurrent("C", "EN", "key", WithPriority(Priority(7))); err != errInvalidOption {
+		t.Errorf("expected %v, got %v", errInvalidOption, err)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+// TestPanicError will verify a recovered panic unwraps to its value when
+// that is an error.
+func TestPanicError(t *testing.T) {
+	err := safely(func() error { panic(io.ErrUnexpectedEOF) })
+	var p *PanicError
+	if !errors.As(err, &p) || len(p.Stack) == 0 {
+		t.Fatalf("expected a *PanicError with its stack, got %v", err)
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) || p.Unwrap() != io.ErrUnexpectedEOF {
+		t.Errorf("expected the panic value to be unwrapped, got %v", p.Unwrap())
+	}
+	if err.Error() != "panic: unexpected EOF" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+
+	err = safely(func() error { panic("bad payload") })
+	if !errors.As(err, &p) || p.Unwrap() != nil || p.Value != "bad payload" {
+		t.Errorf("expected a panic value that isn't an error not to unwrap, got %v", err)
+	}
+	if err := safely(func() error { return io.EOF }); err != io.EOF {
+		t.Errorf("expected the error of fn, got %v", err)
+	}
+}
+
+// TestPanickingHooks will verify panics of post-decode hooks and pipeline
+// stages are returned as a *PanicError.
+func TestPanickingHooks(t *testing.T) {
+	s := NewSettings()
+	s.hooks = []PostDecodeHook{func(interface{}) error { panic(io.ErrUnexpectedEOF) }}
+	var p *PanicError
+	if err := s.postDecode(nil); !errors.As(err, &p) || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("expected the hook's panic, got %v", err)
+	}
+
+	pipeline := Pipeline{{Name: "enrich", Run: func(interface{}) error { panic("nil map") }}}
+	err := pipeline.Run(nil)
+	if !errors.As(err, &p) || err.Error() != "enrich stage: panic: nil map" {
+		t.Errorf("expected the stage's panic, got %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var errCacheReply = errors.New("unexpected cache server reply")
+
+// RedisCache is a Cache backed by a Redis server, letting the instances of
+// a horizontally scaled service share cached responses. Entries are
+// stored under Prefix followed by the cache key and expire with the TTL
+// given to Set. A failing server counts as a miss, so requests go to the
+// API instead, and failed stores are dropped.
+type RedisCache struct {
+	// Prefix is prepended to every key, e.g. to share a server with other
+	// applications. NewRedisCache sets it to "owm:".
+	Prefix string
+	// Timeout bounds each command. NewRedisCache sets it to a second.
+	Timeout time.Duration
+
+	pool *connPool
+}
+
+// NewRedisCache returns a cache using the Redis server at addr, e.g.
+// "localhost:6379".
+func NewRedisCache(addr string) *RedisCache {
+	return &RedisCache{Prefix: "owm:", Timeout: time.Second, pool: newConnPool(addr)}
+}
+
+// Get returns the body stored under key, if it hasn't expired.
+func (c *RedisCache) Get(key string) ([]byte, bool) {
+	var body []byte
+	err := c.pool.do(c.Timeout, func(conn *poolConn) error {
+		if err := writeRedisCommand(conn, "GET", c.Prefix+key); err != nil {
+			return err
+		}
+		var err error
+		body, err = readRedisReply(conn.r)
+		return err
+	})
+	return body, err == nil && body != nil
+}
+
+// Set stores the body under key for ttl, rounded up to the millisecond.
+func (c *RedisCache) Set(key string, body []byte, ttl time.Duration) {
+	ms := (ttl + time.Millisecond - 1) / time.Millisecond
+	if ms < 1 {
+		ms = 1
+	}
+	c.pool.do(c.Timeout, func(conn *poolConn) error {
+		err := writeRedisCommand(conn, "SET", c.Prefix+key, string(body), "PX", strconv.FormatInt(int64(ms), 10))
+		if err != nil {
+			return err
+		}
+		_, err = readRedisReply(conn.r)
+		return err
+	})
+}
+
+// writeRedisCommand sends the command as an array of bulk strings.
+func writeRedisCommand(w io.Writer, args ...string) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, a := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(a), a)
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// readRedisReply reads a simple string, integer or bulk string reply. A
+// nil bulk string is returned as a nil slice and error replies as an
+// error.
+func readRedisReply(r *bufio.Reader) ([]byte, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, errCacheReply
+	}
+	switch line[0] {
+	case '+', ':':
+		return []byte(line[1:]), nil
+	case '-':
+		return nil, fmt.Errorf("redis: %s", line[1:])
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, errCacheReply
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		body := make([]byte, n+2)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return nil, err
+		}
+		return body[:n], nil
+	}
+	return nil, errCacheReply
+}
+
+// readLine reads a line terminated by CRLF, without it.
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", errCacheReply
+	}
+	return line[:len(line)-2], nil
+}
+
+// maxIdleConns is the number of idle connections kept per cache server.
+const maxIdleConns = 4
+
+// connPool keeps idle connections to a cache server for reuse.
+type connPool struct {
+	addr string
+	mu   sync.Mutex
+	idle []*poolConn
+}
+
+// poolConn is a pooled connection with its buffered reader.
+type poolConn struct {
+	net.Conn
+	r *bufio.Reader
+}
+
+func newConnPool(addr string) *connPool {
+	return &connPool{addr: addr}
+}
+
+// do runs fn on an idle or new connection, bounded by timeout. The
+// connection is reused unless fn failed, as the protocol state is then
+// unknown.
+func (p *connPool) do(timeout time.Duration, fn func(c *poolConn) error) error {
+	c, err := p.get(timeout)
+	if err != nil {
+		return err
+	}
+	if timeout > 0 {
+		c.SetDeadline(time.Now().Add(timeout))
+	}
+	if err := fn(c); err != nil {
+		c.Close()
+		return err
+	}
+	p.put(c)
+	return nil
+}
+
+func (p *connPool) get(timeout time.Duration) (*poolConn, error) {
+	p.mu.Lock()
+	if n := len(p.idle); n > 0 {
+		c := p.idle[n-1]
+		p.idle = p.idle[:n-1]
+		p.mu.Unlock()
+		return c, nil
+	}
+	p.mu.Unlock()
+
+	conn, err := net.DialTimeout("tcp", p.addr, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return &poolConn{Conn: conn, r: bufio.NewReader(conn)}, nil
+}
+
+func (p *connPool) put(c *poolConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.idle) >= maxIdleConns {
+		c.Close()
+		return
+	}
+	p.idle = append(p.idle, c)
+}
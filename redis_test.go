@@ -0,0 +1,159 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeRedis is a Redis server understanding GET and SET with PX, recording
+// the expiry of every key.
+type fakeRedis struct {
+	ln     net.Listener
+	mu     sync.Mutex
+	values map[string]string
+	px     map[string]string
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := &fakeRedis{ln: ln, values: make(map[string]string), px: make(map[string]string)}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go f.serve(conn)
+		}
+	}()
+	return f
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		line, err := readLine(r)
+		if err != nil {
+			return
+		}
+		n, _ := strconv.Atoi(line[1:])
+		args := make([]string, n)
+		for i := range args {
+			line, err := readLine(r)
+			if err != nil {
+				return
+			}
+			size, _ := strconv.Atoi(line[1:])
+			b := make([]byte, size+2)
+			if _, err := io.ReadFull(r, b); err != nil {
+				return
+			}
+			args[i] = string(b[:size])
+		}
+
+		f.mu.Lock()
+		switch args[0] {
+		case "GET":
+			if v, ok := f.values[args[1]]; ok {
+				fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(v), v)
+			} else {
+				fmt.Fprint(conn, "$-1\r\n")
+			}
+		case "SET":
+			f.values[args[1]] = args[2]
+			f.px[args[1]] = args[4]
+			fmt.Fprint(conn, "+OK\r\n")
+		default:
+			fmt.Fprint(conn, "-ERR unknown command\r\n")
+		}
+		f.mu.Unlock()
+	}
+}
+
+// TestRedisCache will verify responses are shared through Redis under the
+// key prefix, with the TTL mapped to milliseconds.
+func TestRedisCache(t *testing.T) {
+	redis := newFakeRedis(t)
+	defer redis.ln.Close()
+
+	calls := 0
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		fmt.Fprint(w, `{"name":"London","main":{"temp":12}}`)
+	})
+	defer ts.Close()
+
+	cache := NewRedisCache(redis.ln.Addr().String())
+	cache.Prefix = "weather:"
+	for _, key := range []string{"key-a", "key-b"} {
+		w, err := NewCurrent("C", "EN", key, WithHttpClient(hc), WithCache(cache, 1500*time.Microsecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.CurrentByName("London"); err != nil {
+			t.Fatal(err)
+		}
+		if w.Name != "London" || w.Main.Temp != 12 {
+			t.Errorf("unexpected result %+v", w)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected the second instance to use the cache, got %d calls", calls)
+	}
+
+	redis.mu.Lock()
+	defer redis.mu.Unlock()
+	if len(redis.values) != 1 {
+		t.Fatalf("expected a single entry, got %v", redis.values)
+	}
+	for key := range redis.values {
+		if key[:len("weather:")] != "weather:" {
+			t.Errorf("expected the key prefix, got %q", key)
+		}
+		if px := redis.px[key]; px != "2" {
+			t.Errorf("expected the TTL rounded up to 2ms, got %s", px)
+		}
+	}
+}
+
+// TestRedisCacheUnavailable will verify an unreachable server counts as a
+// miss.
+func TestRedisCacheUnavailable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	c := NewRedisCache(addr)
+	c.Set("k", []byte("v"), time.Minute)
+	if _, ok := c.Get("k"); ok {
+		t.Error("expected a miss")
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ConditionGroup is the family a weather condition code belongs to.
+type ConditionGroup string
+
+// Condition groups as named by OWM.
+const (
+	GroupThunderstorm ConditionGroup = "Thunderstorm"
+	GroupDrizzle      ConditionGroup = "Drizzle"
+	GroupRain         ConditionGroup = "Rain"
+	GroupSnow         ConditionGroup = "Snow"
+	GroupAtmosphere   ConditionGroup = "Atmosphere"
+	GroupClear        ConditionGroup = "Clear"
+	GroupClouds       ConditionGroup = "Clouds"
+	GroupExtreme      ConditionGroup = "Extreme"
+	GroupAdditional   ConditionGroup = "Additional"
+)
+
+// ConditionSeverity ranks how disruptive a condition typically is, from
+// none to extreme, so apps can filter or color code them.
+type ConditionSeverity int
+
+// Condition severities in increasing order.
+const (
+	SeverityNone ConditionSeverity = iota
+	SeverityMinor
+	SeverityModerate
+	SeveritySevere
+	SeverityExtreme
+)
+
+var severityNames = [...]string{"none", "minor", "moderate", "severe", "extreme"}
+
+func (s ConditionSeverity) String() string {
+	if s < 0 || int(s) >= len(severityNames) {
+		return "unknown"
+	}
+	return severityNames[s]
+}
+
+// MarshalText encodes the severity as its name.
+func (s ConditionSeverity) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText decodes a severity from its name, e.g. in a config file.
+func (s *ConditionSeverity) UnmarshalText(b []byte) error {
+	for i, n := range severityNames {
+		if strings.EqualFold(n, string(b)) {
+			*s = ConditionSeverity(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown severity %q", b)
+}
+
+// ConditionInfo describes a weather condition code.
+type ConditionInfo struct {
+	ID          int
+	Group       ConditionGroup
+	Description string
+	DayIcon     string
+	NightIcon   string
+	Severity    ConditionSeverity
+}
+
+// conditionSeverities holds the typical severity of codes above
+// SeverityNone, which is the default.
+var conditionSeverities = map[int]ConditionSeverity{
+	200: SeverityModerate, 201: SeverityModerate, 202: SeveritySevere,
+	210: SeverityModerate, 211: SeverityModerate, 212: SeveritySevere,
+	221: SeveritySevere, 230: SeverityModerate, 231: SeverityModerate,
+	232: SeverityModerate,
+	300: SeverityMinor, 301: SeverityMinor, 302: SeverityMinor,
+	310: SeverityMinor, 311: SeverityMinor, 312: SeverityMinor,
+	313: SeverityMinor, 314: SeverityMinor, 321: SeverityMinor,
+	500: SeverityMinor, 501: SeverityMinor, 502: SeverityModerate,
+	503: SeverityModerate, 504: SeveritySevere, 511: SeveritySevere,
+	520: SeverityMinor, 521: SeverityMinor, 522: SeverityModerate,
+	531: SeverityMinor,
+	600: SeverityMinor, 601: SeverityModerate, 602: SeveritySevere,
+	611: SeverityMinor, 612: SeverityMinor, 615: SeverityMinor,
+	616: SeverityModerate, 620: SeverityMinor, 621: SeverityModerate,
+	622: SeveritySevere,
+	701: SeverityMinor, 711: SeverityMinor, 721: SeverityMinor,
+	731: SeverityModerate, 741: SeverityMinor, 751: SeverityModerate,
+	761: SeverityModerate, 762: SeveritySevere, 771: SeveritySevere,
+	781: SeverityExtreme,
+	900: SeverityExtreme, 901: SeverityExtreme, 902: SeverityExtreme,
+	903: SeverityModerate, 904: SeverityModerate, 905: SeverityModerate,
+	906: SeveritySevere,
+	957: SeverityMinor, 958: SeverityModerate, 959: SeveritySevere,
+	960: SeveritySevere, 961: SeverityExtreme, 962: SeverityExtreme,
+}
+
+var (
+	conditionTable = buildConditionTable()
+
+	descriptionsMu sync.RWMutex
+	descriptions   = make(map[string]map[int]string)
+)
+
+// buildConditionTable indexes the condition lists by code.
+func buildConditionTable() map[int]ConditionInfo {
+	groups := []struct {
+		group ConditionGroup
+		list  []*ConditionData
+	}{
+		{GroupThunderstorm, ThunderstormConditions},
+		{GroupDrizzle, DrizzleConditions},
+		{GroupRain, RainConditions},
+		{GroupSnow, SnowConditions},
+		{GroupAtmosphere, AtmosphereConditions},
+		{GroupClouds, CloudConditions},
+		{GroupExtreme, ExtremeConditions},
+		{GroupAdditional, AdditionalConditions},
+	}
+
+	table := make(map[int]ConditionInfo)
+	for _, g := range groups {
+		for _, c := range g.list {
+			info := ConditionInfo{
+				ID:          c.ID,
+				Group:       g.group,
+				Description: c.Meaning,
+				DayIcon:     strings.TrimSpace(c.Icon1),
+				Severity:    conditionSeverities[c.ID],
+			}
+			if info.ID == 800 {
+				info.Group = GroupClear
+			}
+			// night icons share the day icon's number with an n suffix
+			info.NightIcon = strings.Replace(info.DayIcon, "d.", "n.", 1)
+			table[c.ID] = info
+		}
+	}
+	return table
+}
+
+// LookupCondition returns the metadata of the condition code.
+func LookupCondition(id int) (ConditionInfo, bool) {
+	c, ok := conditionTable[id]
+	return c, ok
+}
+
+// Conditions returns every known condition sorted by code.
+func Conditions() []ConditionInfo {
+	list := make([]ConditionInfo, 0, len(conditionTable))
+	for _, c := range conditionTable {
+		list = append(list, c)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+// ConditionsByGroup returns the conditions of the group sorted by code.
+func ConditionsByGroup(g ConditionGroup) []ConditionInfo {
+	var list []ConditionInfo
+	for _, c := range Conditions() {
+		if c.Group == g {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+// ConditionsAtLeast returns the conditions with a severity of at least s
+// sorted by code, e.g. to build a filter for disruptive weather.
+func ConditionsAtLeast(s ConditionSeverity) []ConditionInfo {
+	var list []ConditionInfo
+	for _, c := range Conditions() {
+		if c.Severity >= s {
+			list = append(list, c)
+		}
+	}
+	return list
+}
+
+// RegisterConditionDescriptions adds translated descriptions, keyed by
+// condition code, for the language code. Registering a language again
+// merges the descriptions.
+//
+// Only the English descriptions are built in. The translations are OWM's
+// own, returned in the Description of responses requested with a lang,
+// and aren't published as a table, so they are registered instead of
+// guessed: by the caller, or by CurrentWeatherData.Descriptions as it
+// fetches them.
+func RegisterConditionDescriptions(lang string, d map[int]string) error {
+	lang = strings.ToUpper(lang)
+	if !ValidLangCode(lang) {
+		return errLangUnavailable
+	}
+
+	descriptionsMu.Lock()
+	defer descriptionsMu.Unlock()
+	if descriptions[lang] == nil {
+		descriptions[lang] = make(map[int]string, len(d))
+	}
+	for id, text := range d {
+		descriptions[lang][id] = text
+	}
+	return nil
+}
+
+// Localized returns the description in the language, falling back to
+// the built in English one when no translation was registered.
+func (c ConditionInfo) Localized(lang string) string {
+	if text, ok := registeredDescription(lang, c.ID); ok {
+		return text
+	}
+	return c.Description
+}
+
+// registeredDescription returns the translation registered for the
+// condition code in the language.
+func registeredDescription(lang string, id int) (string, bool) {
+	descriptionsMu.RLock()
+	defer descriptionsMu.RUnlock()
+	text, ok := descriptions[strings.ToUpper(lang)][id]
+	return text, ok
+}
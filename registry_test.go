@@ -0,0 +1,79 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"testing"
+)
+
+// TestLookupCondition will verify the metadata of known codes.
+func TestLookupCondition(t *testing.T) {
+	c, ok := LookupCondition(781)
+	if !ok || c.Group != GroupAtmosphere || c.Description != "tornado" || c.Severity != SeverityExtreme {
+		t.Errorf("unexpected tornado metadata %+v", c)
+	}
+
+	c, ok = LookupCondition(801)
+	if !ok || c.Group != GroupClouds || c.DayIcon != "02d.png" || c.NightIcon != "02n.png" {
+		t.Errorf("unexpected few clouds metadata %+v", c)
+	}
+	if c, _ := LookupCondition(800); c.Group != GroupClear || c.Severity != SeverityNone {
+		t.Errorf("unexpected clear sky metadata %+v", c)
+	}
+
+	if _, ok := LookupCondition(999); ok {
+		t.Error("expected unknown code to be missing")
+	}
+}
+
+// TestConditions will verify listing and filtering the registry.
+func TestConditions(t *testing.T) {
+	all := Conditions()
+	for i := 1; i < len(all); i++ {
+		if all[i-1].ID >= all[i].ID {
+			t.Fatalf("expected conditions sorted by code, got %d before %d", all[i-1].ID, all[i].ID)
+		}
+	}
+
+	if clouds := ConditionsByGroup(GroupClouds); len(clouds) != 4 {
+		t.Errorf("expected 4 cloud conditions, got %d", len(clouds))
+	}
+	for _, c := range ConditionsAtLeast(SeveritySevere) {
+		if c.Severity < SeveritySevere {
+			t.Errorf("unexpected %s condition %d", c.Severity, c.ID)
+		}
+	}
+	if SeverityModerate.String() != "moderate" {
+		t.Errorf("unexpected severity name %s", SeverityModerate)
+	}
+}
+
+// TestRegisterConditionDescriptions will verify translations are used and
+// English is the fallback.
+func TestRegisterConditionDescriptions(t *testing.T) {
+	if err := RegisterConditionDescriptions("de", map[int]string{800: "Klarer Himmel"}); err != nil {
+		t.Fatal(err)
+	}
+	c, _ := LookupCondition(800)
+	if got := c.Localized("DE"); got != "Klarer Himmel" {
+		t.Errorf("expected German description, got %s", got)
+	}
+	if got := c.Localized("FR"); got != "clear sky" {
+		t.Errorf("expected English fallback, got %s", got)
+	}
+	if err := RegisterConditionDescriptions("xx", nil); err != errLangUnavailable {
+		t.Errorf("expected errLangUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// TrafficRecord is a request of a recorded traffic profile: the current
+// weather at Location asked for at At.
+type TrafficRecord struct {
+	At       time.Time
+	Location string
+}
+
+// ReplayReport sums up a replayed traffic profile, e.g. to size a cache or
+// a rate limit before deploying them.
+type ReplayReport struct {
+	Requests    int // requests replayed
+	Upstream    int // requests sent to the server, retries included
+	CacheHits   int // requests served from the cache
+	RateLimited int // requests refused by a non-blocking rate limiter
+	Failures    int // requests failing otherwise, panics included
+	Duration    time.Duration
+}
+
+// replayCalls is the context key of the counter of requests a replayed
+// request sent to the server.
+type replayCalls struct{}
+
+// countingTransport counts the requests sent on behalf of a replayed
+// request.
+type countingTransport struct {
+	base http.RoundTripper
+}
+
+func (t countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if n, ok := req.Context().Value(replayCalls{}).(*int32); ok {
+		atomic.AddInt32(n, 1)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// Replay replays the traffic profile through the client at the given
+// speed, 2 replaying it twice as fast as recorded and 0 or less as fast as
+// possible. Each request is made at its offset from the first one, on its
+// own goroutine, so a blocking rate limiter delays requests without
+// shifting the rest of the schedule. A request panicking, e.g. in a hook,
+// counts as a failure. Point the client at a mock server
+// with WithBaseURL to plan the capacity of its cache and rate limiter
+// without using the API. Replay returns once every request finished; if
+// the context is done first, it stops scheduling requests and returns its
+// error.
+func (c *Client) Replay(ctx context.Context, profile []TrafficRecord, speed float64) (*ReplayReport, error) {
+	records := append([]TrafficRecord(nil), profile...)
+	sort.SliceStable(records, func(i, j int) bool { return records[i].At.Before(records[j].At) })
+
+	replay := *c
+	hc := *c.settings.client
+	base := hc.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	hc.Transport = countingTransport{base: base}
+	replay.settings.client = &hc
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		report = &ReplayReport{}
+	)
+	start := time.Now()
+	for _, r := range records {
+		if speed > 0 {
+			offset := time.Duration(float64(r.At.Sub(records[0].At)) / speed)
+			sleep(ctx, offset-time.Since(start))
+		}
+		if err := ctx.Err(); err != nil {
+			wg.Wait()
+			return nil, err
+		}
+		wg.Add(1)
+		go func(location string) {
+			defer wg.Done()
+			var calls int32
+			err := safely(func() error {
+				_, err := replay.CurrentByNameCtx(context.WithValue(ctx, replayCalls{}, &calls), location)
+				return err
+			})
+
+			sent := int(atomic.LoadInt32(&calls))
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Requests++
+			report.Upstream += sent
+			switch {
+			case errors.Is(err, errRateLimited):
+				report.RateLimited++
+			case err != nil:
+				report.Failures++
+			case sent == 0:
+				report.CacheHits++
+			}
+		}(r.Location)
+	}
+	wg.Wait()
+	report.Duration = time.Since(start)
+	return report, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestReplay will verify a replayed profile reports how the cache and rate
+// limiter handled it.
+func TestReplay(t *testing.T) {
+	var calls int32
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Query().Get("q") == "Nowhere" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"cod":"404","message":"city not found"}`)
+			return
+		}
+		fmt.Fprintf(w, `{"name":%q}`, r.URL.Query().Get("q"))
+	})
+	defer ts.Close()
+
+	start := time.Unix(1700000000, 0)
+	var profile []TrafficRecord
+	for i, location := range []string{"Oslo", "Paris", "Oslo", "Oslo", "Nowhere", "Rome", "Lima"} {
+		profile = append(profile, TrafficRecord{At: start.Add(time.Duration(i) * time.Minute), Location: location})
+	}
+
+	c, err := NewClient("key", WithHttpClient(hc), WithCache(NewMemoryCache(), time.Hour), WithRateLimiter(NewRateLimiter(4, time.Hour), false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// replayed at 6000 times the recorded speed, a minute takes 10ms
+	r, err := c.Replay(context.Background(), profile, 6000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := ReplayReport{Requests: 7, Upstream: 4, CacheHits: 2, RateLimited: 1, Failures: 1}
+	got := *r
+	got.Duration = 0
+	if got != want || int(calls) != want.Upstream {
+		t.Errorf("expected %+v, got %+v with %d calls", want, got, calls)
+	}
+	if r.Duration < 50*time.Millisecond {
+		t.Errorf("expected the replay to follow the recorded schedule, took %v", r.Duration)
+	}
+
+	c, err = NewClient("key", WithHttpClient(hc), WithPostDecodeHook(func(result interface{}) error {
+		if result.(*CurrentWeatherData).Name == "Paris" {
+			panic("hook failed")
+		}
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, err = c.Replay(context.Background(), profile[:3], 0); err != nil {
+		t.Fatal(err)
+	}
+	if r.Requests != 3 || r.Failures != 1 {
+		t.Errorf("expected the panic to count as a failure, got %+v", r)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.Replay(ctx, profile, 1); err != context.Canceled {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+}
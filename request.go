@@ -0,0 +1,341 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoint identifies a family of API endpoints sharing the same request
+// defaults.
+type Endpoint string
+
+// Endpoint families used to look up per endpoint settings.
+const (
+	EndpointCurrent   Endpoint = "current"
+	EndpointGroup     Endpoint = "group"
+	EndpointForecast  Endpoint = "forecast"
+	EndpointOneCall   Endpoint = "onecall"
+	EndpointHistory   Endpoint = "history"
+	EndpointPollution Endpoint = "pollution"
+	EndpointUV        Endpoint = "uv"
+	EndpointGeocoding Endpoint = "geocoding"
+	EndpointTiles     Endpoint = "tiles"
+	EndpointStation   Endpoint = "station"
+)
+
+// defaultTimeouts holds how long a request to each endpoint family may
+// take by default. Single location lookups are expected to be quick while
+// multi city and historical queries are given more time.
+var defaultTimeouts = map[Endpoint]time.Duration{
+	EndpointCurrent:   10 * time.Second,
+	EndpointGroup:     20 * time.Second,
+	EndpointForecast:  15 * time.Second,
+	EndpointOneCall:   15 * time.Second,
+	EndpointHistory:   30 * time.Second,
+	EndpointPollution: 10 * time.Second,
+	EndpointUV:        10 * time.Second,
+	EndpointGeocoding: 10 * time.Second,
+	EndpointTiles:     10 * time.Second,
+	EndpointStation:   10 * time.Second,
+}
+
+// fallbackTimeout is used for endpoints without a default.
+const fallbackTimeout = 30 * time.Second
+
+// WithTimeout sets the request timeout for every endpoint. A zero duration
+// disables the timeout, leaving it to the http client.
+func WithTimeout(d time.Duration) Option {
+	return func(s *Settings) error {
+		for e := range defaultTimeouts {
+			s.timeouts[e] = d
+		}
+		return nil
+	}
+}
+
+// WithEndpointTimeout overrides the request timeout for the given endpoint
+// family. A zero duration disables the timeout, leaving it to the http
+// client.
+func WithEndpointTimeout(e Endpoint, d time.Duration) Option {
+	return func(s *Settings) error {
+		s.timeouts[e] = d
+		return nil
+	}
+}
+
+// WithBaseURL sends every request to the given base URL instead of the
+// OWM hosts, e.g. an httptest server, a proxy or a mock service. The path
+// of each endpoint is kept and appended to the base path, so with
+// "http://localhost:8080/owm" current weather is requested from
+// "http://localhost:8080/owm/data/2.5/weather".
+func WithBaseURL(base string) Option {
+	return func(s *Settings) error {
+		u, err := url.Parse(base)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return errInvalidBaseURL
+		}
+		s.baseURL = u
+		return nil
+	}
+}
+
+// rebase points the URL at the configured base URL, if any.
+func (s *Settings) rebase(u *url.URL) {
+	if s.baseURL == nil {
+		return
+	}
+	u.Scheme = s.baseURL.Scheme
+	u.Host = s.baseURL.Host
+	u.User = s.baseURL.User
+	u.Path = strings.TrimSuffix(s.baseURL.Path, "/") + u.Path
+	u.RawPath = ""
+}
+
+// timeout returns the request timeout configured for the endpoint.
+func (s *Settings) timeout(e Endpoint) time.Duration {
+	if d, ok := s.timeouts[e]; ok {
+		return d
+	}
+	if d, ok := defaultTimeouts[e]; ok {
+		return d
+	}
+	return fallbackTimeout
+}
+
+// Changed reports whether the body of the last successful response
+// differed from the previous response to the same query, letting pollers
+// skip storing data OWM hasn't updated. The previous response is looked up
+// across every result sharing the settings, e.g. those built by one
+// Client. The first response to a query always counts as changed.
+func (s *Settings) Changed() bool { return s.changed }
+
+// Checksum returns the hex encoded SHA-256 of the body of the last
+// successful response, or an empty string if there was none yet.
+func (s *Settings) Checksum() string {
+	if s.checksum == nil {
+		return ""
+	}
+	return hex.EncodeToString(s.checksum)
+}
+
+// maxChecksums is the number of queries whose last checksum is kept.
+const maxChecksums = 1024
+
+// checksums holds the checksum of the last successful response to the
+// most recently made queries, dropping the least recently made one past
+// maxChecksums; a query made again after that counts as changed. It is
+// shared by every copy of the settings.
+type checksums struct {
+	mu    sync.Mutex
+	last  map[string]*list.Element
+	order *list.List // of *checksum, most recent first
+}
+
+type checksum struct {
+	key string
+	sum []byte
+}
+
+func newChecksums() *checksums {
+	return &checksums{last: make(map[string]*list.Element), order: list.New()}
+}
+
+// swap records the checksum for the query and returns the previous one.
+func (c *checksums) swap(key string, sum []byte) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.last[key]; ok {
+		c.order.MoveToFront(e)
+		entry := e.Value.(*checksum)
+		prev := entry.sum
+		entry.sum = sum
+		return prev
+	}
+	c.last[key] = c.order.PushFront(&checksum{key: key, sum: sum})
+	if c.order.Len() > maxChecksums {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.last, oldest.Value.(*checksum).key)
+	}
+	return nil
+}
+
+// track records the checksum of a successful response body to the URL.
+func (s *Settings) track(e Endpoint, uri string, body []byte) {
+	sum := sha256.Sum256(body)
+	prev := s.checksum
+	if s.checksums != nil {
+		prev = s.checksums.swap(cacheKey(uri), sum[:])
+	}
+	s.changed = !bytes.Equal(prev, sum[:])
+	s.checksum = sum[:]
+	if s.changed {
+		s.bus.publish(DataChanged{Endpoint: e, Checksum: s.Checksum()})
+	}
+}
+
+// get issues a GET request for the given URL bound to ctx and the
+// endpoint's timeout. The body is read in full before returning so the
+// timeout covers it; the caller must still close it. Responses signaling
+// failure are returned as an *APIError, or a *MaintenanceError for HTML
+// pages.
+func (s *Settings) get(ctx context.Context, e Endpoint, uri string) (*http.Response, error) {
+	return checked(s.do(ctx, e, uri))
+}
+
+// checked turns an HTML page into a *MaintenanceError and a response
+// with a status other than 2xx into an *APIError, closing its body.
+func checked(response *http.Response, err error) (*http.Response, error) {
+	if err != nil {
+		return nil, err
+	}
+	if e, ok := maintenanceOf(response); ok {
+		return nil, e
+	}
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return response, nil
+	}
+	defer response.Body.Close()
+	return nil, newAPIError(response)
+}
+
+// do is like get but returns every response as is.
+func (s *Settings) do(ctx context.Context, e Endpoint, uri string) (*http.Response, error) {
+	if response, ok := s.cached(e, uri); ok {
+		return response, nil
+	}
+	return s.fetch(ctx, e, uri)
+}
+
+// fetch sends the request, bypassing the cache, and caches a successful
+// response.
+func (s *Settings) fetch(ctx context.Context, e Endpoint, uri string) (*http.Response, error) {
+	response, body, err := s.send(ctx, e, http.MethodGet, uri, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK:
+		s.track(e, uri, body)
+		s.store(uri, body)
+	case http.StatusNotFound:
+		s.storeNotFound(uri, body)
+	}
+
+	return response, nil
+}
+
+// post sends the form to the URL like get, without caching or tracking
+// the response.
+func (s *Settings) post(ctx context.Context, e Endpoint, uri string, form url.Values) (*http.Response, error) {
+	response, _, err := s.send(ctx, e, http.MethodPost, uri, []byte(form.Encode()))
+	return checked(response, err)
+}
+
+// send issues the request through the breaker, rate limiter and retry
+// policy, publishing its lifecycle events. A payload is sent as a url
+// encoded form.
+func (s *Settings) send(ctx context.Context, e Endpoint, method, uri string, payload []byte) (response *http.Response, body []byte, err error) {
+	start := time.Now()
+	s.bus.publish(RequestStarted{Endpoint: e, Time: start})
+	defer func() {
+		finished := RequestFinished{Endpoint: e, Duration: time.Since(start), Err: err}
+		if response != nil {
+			finished.StatusCode = response.StatusCode
+		}
+		s.bus.publish(finished)
+	}()
+
+	for attempt := 1; ; attempt++ {
+		if err = s.guard(); err != nil {
+			return nil, nil, err
+		}
+		if err = s.throttle(ctx); err != nil {
+			if s.breaker != nil {
+				s.breaker.abort()
+			}
+			return nil, nil, err
+		}
+		response, body, err = s.attempt(ctx, e, method, uri, payload)
+		s.settle(ctx, e, response, err)
+		delay, ok := s.retry.next(ctx, attempt, response, err)
+		if !ok {
+			break
+		}
+		retry := RetryScheduled{Endpoint: e, Attempt: attempt, Delay: delay, Err: err}
+		if response != nil {
+			retry.StatusCode = response.StatusCode
+		}
+		s.bus.publish(retry)
+		if err = s.sleep(ctx, delay); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return response, body, nil
+}
+
+// attempt sends the request once, bound to the endpoint's timeout, and
+// reads the body in full.
+func (s *Settings) attempt(ctx context.Context, e Endpoint, method, uri string, payload []byte) (*http.Response, []byte, error) {
+	var cancel context.CancelFunc
+	if d := s.timeout(e); d > 0 {
+		ctx, cancel = context.WithTimeout(ctx, d)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	var reader io.Reader
+	if payload != nil {
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, uri, reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	s.rebase(req.URL)
+
+	response, err := s.client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.measureSkew(e, response.Header.Get("Date"), time.Now())
+	body, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return response, body, nil
+}
@@ -0,0 +1,281 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWithEndpointTimeout will verify that the per endpoint timeout is
+// applied to requests and can be overridden.
+func TestWithEndpointTimeout(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		fmt.Fprint(w, `{"cod":200}`)
+	})
+	defer ts.Close()
+
+	c, err := NewCurrent("c", "EN", "key", WithHttpClient(hc), WithEndpointTimeout(EndpointCurrent, 20*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CurrentByID(1); err == nil {
+		t.Error("expected timeout error, got nil")
+	}
+
+	c, err = NewCurrent("c", "EN", "key", WithHttpClient(hc), WithTimeout(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CurrentByID(1); err != nil {
+		t.Errorf("expected no error without timeout, got %v", err)
+	}
+}
+
+// TestDefaultTimeouts will verify every endpoint family gets a default and
+// that bulk endpoints get more time than single lookups.
+func TestDefaultTimeouts(t *testing.T) {
+	s := NewSettings()
+	if s.timeout(EndpointHistory) <= s.timeout(EndpointCurrent) {
+		t.Error("expected history timeout to be longer than current timeout")
+	}
+	if d := s.timeout(Endpoint("unknown")); d != fallbackTimeout {
+		t.Errorf("expected fallback timeout %v, got %v", fallbackTimeout, d)
+	}
+}
+
+// TestChanged will verify identical response bodies are reported as
+// unchanged between refreshes.
+func TestChanged(t *testing.T) {
+	body := `{"id":1,"dt":100}`
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	})
+	defer ts.Close()
+
+	c, err := NewCurrent("c", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Checksum() != "" {
+		t.Error("expected no checksum before the first request")
+	}
+
+	for i, expected := range []bool{true, false, true} {
+		if i == 2 {
+			body = `{"id":1,"dt":200}`
+		}
+		if err := c.CurrentByID(1); err != nil {
+			t.Fatal(err)
+		}
+		if c.Changed() != expected {
+			t.Errorf("refresh %d: expected changed %v, got %v", i, expected, c.Changed())
+		}
+	}
+
+	if len(c.Checksum()) != 64 {
+		t.Errorf("unexpected checksum %q", c.Checksum())
+	}
+}
+
+// TestChangedPerQuery will verify responses are compared with the last
+// response to the same query, whichever result of a Client fetched it.
+func TestChangedPerQuery(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":%s,"dt":100}`, r.URL.Query().Get("id"))
+	})
+	defer ts.Close()
+
+	c, err := NewCurrent("c", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []bool{true, true, false, false} {
+		if err := c.CurrentByID(i%2 + 1); err != nil {
+			t.Fatal(err)
+		}
+		if c.Changed() != expected {
+			t.Errorf("alternating query %d: expected changed %v, got %v", i, expected, c.Changed())
+		}
+	}
+
+	client, err := NewClient("key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []bool{true, false} {
+		w, err := client.CurrentByID(1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if w.Changed() != expected {
+			t.Errorf("client result %d: expected changed %v, got %v", i, expected, w.Changed())
+		}
+	}
+}
+
+// TestChecksumsBounded will verify only the checksums of the most
+// recently made queries are kept.
+func TestChecksumsBounded(t *testing.T) {
+	c := newChecksums()
+	for i := 0; i <= maxChecksums; i++ {
+		c.swap(fmt.Sprint(i), []byte{byte(i)})
+		if i == maxChecksums/2 {
+			c.swap("0", []byte{1})
+		}
+	}
+	if len(c.last) != maxChecksums || c.order.Len() != maxChecksums {
+		t.Errorf("expected %d checksums, got %d", maxChecksums, len(c.last))
+	}
+	if prev := c.swap("1", []byte{1}); prev != nil {
+		t.Errorf("expected the least recent query dropped, got %v", prev)
+	}
+	if prev := c.swap("0", []byte{2}); len(prev) != 1 || prev[0] != 1 {
+		t.Errorf("expected a repeated query kept, got %v", prev)
+	}
+}
+
+// TestContextCancellation will verify the Ctx variants stop waiting for a
+// response once their context is done.
+func TestContextCancellation(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	})
+	defer ts.Close()
+
+	c, err := NewCurrent("c", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := c.CurrentByIDCtx(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to be exceeded, got %v", err)
+	}
+
+	f, err := NewForecast("5", "c", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if err := f.DailyByNameCtx(ctx, "London", 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected the request to be canceled, got %v", err)
+	}
+}
+
+// TestAPIError will verify failed responses are returned as an *APIError
+// by every kind of request instead of being decoded.
+func TestAPIError(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("appid") {
+		case "bad":
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"cod":401,"message":"Invalid API key. Please see https://openweathermap.org/faq#error401 for more info."}`)
+		case "busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+			fmt.Fprint(w, `{"cod":"429","message":"rate limited"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<html>not found</html>`)
+		}
+	})
+	defer ts.Close()
+
+	c, err := NewCurrent("C", "EN", "bad", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.CurrentByName("London")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized || apiErr.COD != "401" {
+		t.Fatalf("expected a 401 APIError, got %v", err)
+	}
+	if !errors.Is(err, errInvalidKey) {
+		t.Error("expected a rejected key to match errInvalidKey")
+	}
+
+	f, err := NewForecast("5", "C", "EN", "busy", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = f.DailyByID(1, 1)
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests || apiErr.Message != "rate limited" {
+		t.Fatalf("expected a 429 APIError, got %v", err)
+	}
+	if errors.Is(err, errInvalidKey) {
+		t.Error("expected only 401 to match errInvalidKey")
+	}
+
+	o, err := NewOneCall("C", "EN", "missing", nil, WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = o.OneCallByCoordinates(&Coordinates{})
+	if !errors.As(err, &apiErr) || apiErr.COD != "404" || apiErr.Message != "Not Found" {
+		t.Fatalf("expected a 404 APIError, got %v", err)
+	}
+	if err.Error() != "openweathermap: 404 Not Found" {
+		t.Errorf("unexpected message %q", err)
+	}
+}
+
+// TestWithBaseURL will verify requests are sent to the base URL with the
+// endpoint path appended.
+func TestWithBaseURL(t *testing.T) {
+	var paths []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		fmt.Fprint(w, `{"cod":200}`)
+	}))
+	defer ts.Close()
+
+	c, err := NewCurrent("C", "EN", "key", WithBaseURL(ts.URL+"/owm/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.CurrentByName("London"); err != nil {
+		t.Fatal(err)
+	}
+	h, err := NewHistorical("C", "key", WithBaseURL(ts.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := h.HistoryByID(1); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"/owm/data/2.5/weather", "/data/2.5/history/city"}
+	if len(paths) != 2 || paths[0] != expected[0] || !strings.HasPrefix(paths[1], expected[1]) {
+		t.Errorf("expected %v, got %v", expected, paths)
+	}
+
+	for _, base := range []string{"", "localhost:8080", "://bad"} {
+		if _, err := NewCurrent("C", "EN", "key", WithBaseURL(base)); err != errInvalidBaseURL {
+			t.Errorf("%q: expected %v, got %v", base, errInvalidBaseURL, err)
+		}
+	}
+}
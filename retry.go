@@ -0,0 +1,126 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy describes how failed requests are retried. Network errors,
+// 429 Too Many Requests and 5xx responses are retried with exponential
+// backoff, unless the caller's context is done.
+type RetryPolicy struct {
+	// MaxAttempts is the most times a request is sent, including the
+	// first one.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry, doubled for each
+	// further one up to MaxDelay.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// Jitter randomizes a backoff delay so clients don't retry in lock
+	// step. It defaults to a random delay between half and all of it.
+	Jitter func(d time.Duration) time.Duration
+}
+
+// DefaultRetryPolicy retries twice, after about half a second and a
+// second.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// WithRetry retries failed requests following the policy. A Retry-After
+// header sent along with a 429 or 503 response takes precedence over the
+// backoff.
+func WithRetry(p RetryPolicy) Option {
+	return func(s *Settings) error {
+		if p.MaxAttempts < 1 || p.BaseDelay < 0 || p.MaxDelay < 0 {
+			return errInvalidOption
+		}
+		if p.Jitter == nil {
+			p.Jitter = halfJitter
+		}
+		s.retry = &p
+		return nil
+	}
+}
+
+// next reports whether the attempt should be retried and after how long.
+// A nil policy never retries.
+func (p *RetryPolicy) next(ctx context.Context, attempt int, response *http.Response, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+	if err == nil && response.StatusCode != http.StatusTooManyRequests && response.StatusCode < 500 {
+		return 0, false
+	}
+	if response != nil {
+		if d, ok := retryAfter(response.Header.Get("Retry-After")); ok {
+			return d, true
+		}
+	}
+
+	d := p.BaseDelay
+	for i := 1; i < attempt && (p.MaxDelay == 0 || d < p.MaxDelay); i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	return p.Jitter(d), true
+}
+
+// retryAfter parses a Retry-After header holding either seconds or an
+// HTTP date.
+func retryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// halfJitter returns a random duration between half and all of d.
+func halfJitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// sleep waits for d or until ctx is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestWithRetry will verify 429 and 5xx responses are retried with
+// backoff and Retry-After is honored.
+func TestWithRetry(t *testing.T) {
+	calls := 0
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			w.WriteHeader(http.StatusBadGateway)
+		case 2:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			fmt.Fprint(w, `{"id":1,"name":"Oslo"}`)
+		}
+	})
+	defer ts.Close()
+
+	bus := NewBus()
+	var retries []RetryScheduled
+	bus.Subscribe(func(e Event) {
+		if r, ok := e.(RetryScheduled); ok {
+			retries = append(retries, r)
+		}
+	})
+	policy := RetryPolicy{MaxAttempts: 4, BaseDelay: time.Second, MaxDelay: 3 * time.Second, Jitter: func(d time.Duration) time.Duration { return d }}
+	c, err := NewCurrent("C", "EN", "key", WithHttpClient(hc), WithEventBus(bus), WithRetry(policy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var slept []time.Duration
+	c.sleep = func(ctx context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+
+	if err := c.CurrentByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if c.Name != "Oslo" || calls != 4 {
+		t.Fatalf("expected success on the fourth attempt, got %d calls", calls)
+	}
+	expected := []time.Duration{time.Second, 7 * time.Second, 3 * time.Second}
+	if fmt.Sprint(slept) != fmt.Sprint(expected) {
+		t.Errorf("expected delays %v, got %v", expected, slept)
+	}
+	if len(retries) != 3 || retries[1].StatusCode != http.StatusTooManyRequests || retries[2].Attempt != 3 {
+		t.Errorf("unexpected retry events %+v", retries)
+	}
+}
+
+// TestWithRetryGivesUp will verify client errors aren't retried and the
+// last failure is returned once the attempts run out.
+func TestWithRetryGivesUp(t *testing.T) {
+	calls := 0
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Query().Get("appid") == "bad" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	defer ts.Close()
+
+	for key, expected := range map[string]int{"bad": 1, "key": 2} {
+		calls = 0
+		c, err := NewCurrent("C", "EN", key, WithHttpClient(hc), WithRetry(RetryPolicy{MaxAttempts: 2}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var apiErr *APIError
+		if err := c.CurrentByID(1); !errors.As(err, &apiErr) {
+			t.Errorf("%s: expected an APIError, got %v", key, err)
+		}
+		if calls != expected {
+			t.Errorf("%s: expected %d attempts, got %d", key, expected, calls)
+		}
+	}
+
+	if _, err := NewCurrent("C", "EN", "key", WithRetry(RetryPolicy{})); err != errInvalidOption {
+		t.Errorf("expected %v, got %v", errInvalidOption, err)
+	}
+}
+
+// TestRetryStopsOnCancel will verify waiting for a retry ends with the
+// caller's context.
+func TestRetryStopsOnCancel(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer ts.Close()
+
+	c, err := NewCurrent("C", "EN", "key", WithHttpClient(hc), WithRetry(RetryPolicy{MaxAttempts: 5, BaseDelay: time.Hour}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := c.CurrentByIDCtx(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the deadline to end the retries, got %v", err)
+	}
+}
+
+// TestRetryAfter will verify both forms of the header are parsed.
+func TestRetryAfter(t *testing.T) {
+	if d, ok := retryAfter("120"); !ok || d != 2*time.Minute {
+		t.Errorf("unexpected delay %v", d)
+	}
+	if d, ok := retryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)); !ok || d < 59*time.Minute {
+		t.Errorf("unexpected delay %v", d)
+	}
+	if _, ok := retryAfter("soon"); ok {
+		t.Error("expected an invalid header to be ignored")
+	}
+	for i := 0; i < 100; i++ {
+		if d := halfJitter(time.Second); d < 500*time.Millisecond || d > time.Second {
+			t.Fatalf("jitter out of range: %v", d)
+		}
+	}
+}
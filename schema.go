@@ -0,0 +1,81 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"encoding/json"
+)
+
+// Schema identifies the API version and shape a payload corresponds to.
+type Schema string
+
+// Payload shapes recognized by DetectSchema.
+const (
+	SchemaUnknown    Schema = "unknown"
+	SchemaCurrent    Schema = "weather/2.5"
+	SchemaForecast5  Schema = "forecast/2.5"
+	SchemaForecast16 Schema = "forecast/daily/2.5"
+	SchemaOneCall25  Schema = "onecall/2.5"
+	SchemaOneCall30  Schema = "onecall/3.0"
+)
+
+// DetectSchema inspects a JSON payload and reports which OWM endpoint
+// version and shape produced it. One Call 3.0 payloads are told apart by
+// the daily summary 2.5 doesn't send, so a 3.0 payload with the daily
+// section excluded is reported as 2.5.
+func DetectSchema(b []byte) Schema {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(b, &top); err != nil {
+		return SchemaUnknown
+	}
+
+	if _, ok := top["timezone_offset"]; ok {
+		var daily []map[string]json.RawMessage
+		if raw, ok := top["daily"]; ok && json.Unmarshal(raw, &daily) == nil {
+			for _, d := range daily {
+				if _, ok := d["summary"]; ok {
+					return SchemaOneCall30
+				}
+			}
+		}
+		return SchemaOneCall25
+	}
+
+	if raw, ok := top["list"]; ok {
+		if _, ok := top["city"]; !ok {
+			return SchemaUnknown
+		}
+		var list []map[string]json.RawMessage
+		if json.Unmarshal(raw, &list) != nil || len(list) == 0 {
+			return SchemaUnknown
+		}
+		if _, ok := list[0]["main"]; ok {
+			return SchemaForecast5
+		}
+		var temp map[string]json.RawMessage
+		if json.Unmarshal(list[0]["temp"], &temp) == nil && temp != nil {
+			return SchemaForecast16
+		}
+		return SchemaUnknown
+	}
+
+	if _, ok := top["main"]; ok {
+		if _, ok := top["coord"]; ok {
+			return SchemaCurrent
+		}
+	}
+
+	return SchemaUnknown
+}
@@ -0,0 +1,73 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+const (
+	testForecast5JSON  = `{"cod":"200","cnt":1,"list":[{"dt":1,"main":{"temp":3},"dt_txt":"2022-01-01 00:00:00"}],"city":{"id":1}}`
+	testForecast16JSON = `{"cod":200,"cnt":1,"list":[{"dt":1,"temp":{"day":3,"min":1,"max":4}}],"city":{"id":1}}`
+)
+
+// TestDetectSchema will verify payload shapes are recognized.
+func TestDetectSchema(t *testing.T) {
+	tests := map[string]Schema{
+		`{"coord":{"lat":1,"lon":2},"main":{"temp":3}}`: SchemaCurrent,
+		testForecast5JSON:  SchemaForecast5,
+		testForecast16JSON: SchemaForecast16,
+		`{"lat":1,"lon":2,"timezone_offset":0,"daily":[{"dt":1}]}`:                  SchemaOneCall25,
+		`{"lat":1,"lon":2,"timezone_offset":0,"daily":[{"dt":1,"summary":"Rain"}]}`: SchemaOneCall30,
+		`{"cod":"404","message":"city not found"}`:                                  SchemaUnknown,
+		`not json`: SchemaUnknown,
+	}
+
+	for payload, expected := range tests {
+		if got := DetectSchema([]byte(payload)); got != expected {
+			t.Errorf("expected %s for %s, got %s", expected, payload, got)
+		}
+	}
+}
+
+// TestForecastSchemaRouting will verify a daily payload is decoded as a
+// daily forecast even when a 3 hourly one was expected.
+func TestForecastSchemaRouting(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testForecast16JSON)
+	})
+	defer ts.Close()
+
+	f, err := NewForecast("5", "c", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.DailyByID(1, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Schema != SchemaForecast16 {
+		t.Errorf("expected schema %s, got %s", SchemaForecast16, f.Schema)
+	}
+	daily, ok := f.ForecastWeatherJson.(*Forecast16WeatherData)
+	if !ok {
+		t.Fatalf("expected daily forecast data, got %T", f.ForecastWeatherJson)
+	}
+	if len(daily.List) != 1 || daily.List[0].Temp.Max != 4 {
+		t.Errorf("unexpected daily forecast %+v", daily.List)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"net/http"
+	"time"
+)
+
+// clockSkewTolerance is the difference between the server's Date header
+// and the local clock below which the clocks are considered in sync, as
+// the header only has a resolution of a second and responses take time
+// to arrive.
+const clockSkewTolerance = 30 * time.Second
+
+// measureSkew records how far the server's clock is ahead of the local
+// one from the Date header of a response received at local time at.
+func (s *Settings) measureSkew(e Endpoint, date string, at time.Time) {
+	t, err := http.ParseTime(date)
+	if err != nil {
+		return
+	}
+	skew := t.Sub(at).Round(time.Second)
+	if abs(skew) < clockSkewTolerance {
+		skew = 0
+	}
+	if abs(skew-s.skew) >= clockSkewTolerance {
+		s.bus.publish(ClockSkewDetected{Endpoint: e, Skew: skew})
+	}
+	s.skew = skew
+}
+
+func abs(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// ClockSkew returns how far the server's clock, as reported by the Date
+// header of the last response, is ahead of the local one, or zero when
+// they agree within 30 seconds. It is negative when the local clock is
+// ahead, e.g. on a device with an unreliable real time clock.
+func (s *Settings) ClockSkew() time.Duration { return s.skew }
+
+// Now returns the current time corrected by the clock skew, to compare
+// with the timestamps of the API's data.
+func (s *Settings) Now() time.Time { return time.Now().Add(s.skew) }
+
+// Age returns how old data timestamped t by the API, such as a result's
+// Dt, is despite clock skew.
+func (s *Settings) Age(t time.Time) time.Duration { return s.Now().Sub(t) }
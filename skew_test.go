@@ -0,0 +1,88 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestClockSkew will verify the skew between the server's Date header and
+// the local clock is detected once it is large and reported on the bus.
+func TestClockSkew(t *testing.T) {
+	ahead := time.Hour
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", time.Now().Add(ahead).UTC().Format(http.TimeFormat))
+		fmt.Fprint(w, `{"name":"Oslo"}`)
+	})
+	defer ts.Close()
+
+	bus := NewBus()
+	var detected []ClockSkewDetected
+	bus.Subscribe(func(e Event) {
+		if e, ok := e.(ClockSkewDetected); ok {
+			detected = append(detected, e)
+		}
+	})
+	c, err := NewCurrent("C", "EN", "key", WithHttpClient(hc), WithEventBus(bus))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := c.CurrentByID(1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if skew := c.ClockSkew(); skew < ahead-time.Minute || skew > ahead+time.Minute {
+		t.Errorf("expected a skew of about an hour, got %v", skew)
+	}
+	if len(detected) != 1 || detected[0].Endpoint != EndpointCurrent {
+		t.Errorf("expected the skew to be reported once, got %+v", detected)
+	}
+	if age := c.Age(time.Now().Add(ahead)); age > time.Minute || age < -time.Minute {
+		t.Errorf("expected data just issued by the server to be fresh, got an age of %v", age)
+	}
+
+	ahead = 5 * time.Second
+	if err := c.CurrentByID(1); err != nil {
+		t.Fatal(err)
+	}
+	if skew := c.ClockSkew(); skew != 0 {
+		t.Errorf("expected a small skew to be ignored, got %v", skew)
+	}
+	if len(detected) != 2 || detected[1].Skew != 0 {
+		t.Errorf("expected the clocks agreeing again to be reported, got %+v", detected)
+	}
+}
+
+// TestFreshnessClockSkew will verify data times observed from the server
+// are aged against its clock once skew is detected.
+func TestFreshnessClockSkew(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	bus := NewBus()
+	f := NewFreshness()
+	f.now = func() time.Time { return now }
+	f.Declare("oslo", 15*time.Minute)
+	f.Subscribe(bus, "oslo")
+
+	f.Observe("oslo", now.Add(2*time.Hour))
+	bus.publish(ClockSkewDetected{Endpoint: EndpointCurrent, Skew: 2 * time.Hour})
+	report := f.Report(now.Add(5 * time.Minute))
+	if len(report) != 1 || report[0].Age != 5*time.Minute || report[0].Violated {
+		t.Errorf("unexpected report %+v", report)
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Slot is a forecast period considered for an outdoor activity such as a
+// run or a ride. Temperatures and wind speeds are in the unit system the
+// forecast was requested in.
+type Slot struct {
+	Start         time.Time
+	Duration      time.Duration
+	Temp          float64
+	WindSpeed     float64
+	Pop           float64 // probability of precipitation from 0 to 1
+	Precipitation float64 // rain and snow in mm
+	Humidity      int
+	Clouds        int
+	ConditionID   int
+}
+
+// ActivityPreferences holds the conditions acceptable for an activity.
+// Slots breaking any of them are left out; the remaining ones are ranked
+// by how close they are to the middle of the temperature range, how calm
+// the wind is and how unlikely precipitation is.
+type ActivityPreferences struct {
+	MinTemp         float64
+	MaxTemp         float64
+	MaxWind         float64
+	MaxPop          float64
+	NoPrecipitation bool
+	// EarliestHour and LatestHour bound the local start hour of a slot,
+	// e.g. 6 and 20 for daylight hours. Both zero allows any hour.
+	EarliestHour int
+	LatestHour   int
+}
+
+// RankedSlot is a slot along with its score from 0, barely acceptable, to
+// 1, ideal.
+type RankedSlot struct {
+	Slot
+	Score float64
+}
+
+// BestSlots returns the slots starting between from and to that meet the
+// preferences, best first.
+func BestSlots(slots []Slot, p ActivityPreferences, from, to time.Time) []RankedSlot {
+	var ranked []RankedSlot
+	for _, s := range slots {
+		if s.Start.Before(from) || !s.Start.Before(to) || !p.accepts(s) {
+			continue
+		}
+		ranked = append(ranked, RankedSlot{Slot: s, Score: p.score(s)})
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Score > ranked[j].Score })
+	return ranked
+}
+
+// accepts reports whether the slot meets every preference.
+func (p ActivityPreferences) accepts(s Slot) bool {
+	if s.Temp < p.MinTemp || s.Temp > p.MaxTemp || s.WindSpeed > p.MaxWind || s.Pop > p.MaxPop {
+		return false
+	}
+	if p.NoPrecipitation && (s.Precipitation > 0 || isPrecipitation(s.ConditionID)) {
+		return false
+	}
+	if p.EarliestHour != 0 || p.LatestHour != 0 {
+		if h := s.Start.Hour(); h < p.EarliestHour || h > p.LatestHour {
+			return false
+		}
+	}
+	return true
+}
+
+// score rates an accepted slot: half the score comes from the
+// temperature, 30% from the wind and 20% from the chance of precipitation.
+func (p ActivityPreferences) score(s Slot) float64 {
+	score := 1.0
+	if half := (p.MaxTemp - p.MinTemp) / 2; half > 0 {
+		score -= 0.5 * math.Abs(s.Temp-(p.MinTemp+half)) / half
+	}
+	if p.MaxWind > 0 {
+		score -= 0.3 * s.WindSpeed / p.MaxWind
+	}
+	return score - 0.2*s.Pop
+}
+
+// isPrecipitation reports whether the condition code is a thunderstorm,
+// drizzle, rain or snow.
+func isPrecipitation(id int) bool {
+	return id >= 200 && id < 700
+}
+
+// Slots returns the hourly forecast as slots in the location's timezone.
+func (w *OneCallData) Slots() []Slot {
+	loc := time.FixedZone(w.Timezone, w.TimezoneOffset)
+	slots := make([]Slot, 0, len(w.Hourly))
+	for _, h := range w.Hourly {
+		s := Slot{
+			Start:         time.Unix(int64(h.Dt), 0).In(loc),
+			Duration:      time.Hour,
+			Temp:          h.Temp,
+			WindSpeed:     h.WindSpeed,
+			Pop:           h.Pop,
+			Precipitation: h.Rain.OneH + h.Snow.OneH,
+			Humidity:      h.Humidity,
+			Clouds:        h.Clouds,
+		}
+		if c, ok := h.FirstCondition(); ok {
+			s.ConditionID = c.ID
+		}
+		slots = append(slots, s)
+	}
+	return slots
+}
+
+// Slots returns the 3 hourly forecast as slots in the city's timezone.
+func (f *Forecast5WeatherData) Slots() []Slot {
+	loc := time.FixedZone(f.City.Name, f.City.Timezone)
+	slots := make([]Slot, 0, len(f.List))
+	for _, l := range f.List {
+		s := Slot{
+			Start:         time.Unix(int64(l.Dt), 0).In(loc),
+			Duration:      3 * time.Hour,
+			Temp:          l.Main.Temp,
+			WindSpeed:     l.Wind.Speed,
+			Pop:           l.Pop,
+			Precipitation: l.Rain.ThreeH + l.Snow.ThreeH,
+			Humidity:      l.Main.Humidity,
+			Clouds:        l.Clouds.All,
+		}
+		if c, ok := l.FirstCondition(); ok {
+			s.ConditionID = c.ID
+		}
+		slots = append(slots, s)
+	}
+	return slots
+}
@@ -0,0 +1,49 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"testing"
+	"time"
+)
+
+// TestBestSlots will verify unacceptable slots are dropped and the rest
+// ranked best first.
+func TestBestSlots(t *testing.T) {
+	day := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	o := &OneCallData{Timezone: "UTC", Hourly: []OneCallHourlyData{
+		{Dt: int(day.Add(5 * time.Hour).Unix()), Temp: 14, WindSpeed: 1},                                 // too early
+		{Dt: int(day.Add(7 * time.Hour).Unix()), Temp: 16, WindSpeed: 2},                                 // good
+		{Dt: int(day.Add(12 * time.Hour).Unix()), Temp: 19, WindSpeed: 1},                                // ideal
+		{Dt: int(day.Add(15 * time.Hour).Unix()), Temp: 27, WindSpeed: 1},                                // too hot
+		{Dt: int(day.Add(17 * time.Hour).Unix()), Temp: 20, WindSpeed: 9},                                // too windy
+		{Dt: int(day.Add(18 * time.Hour).Unix()), Temp: 19, Pop: 0.8, Rain: Rain{OneH: 1.2}},             // rain
+		{Dt: int(day.Add(19 * time.Hour).Unix()), Temp: 18, WindSpeed: 3, Weather: []Weather{{ID: 211}}}, // thunderstorm
+		{Dt: int(day.Add(32 * time.Hour).Unix()), Temp: 19, WindSpeed: 1},                                // outside the window
+	}}
+
+	p := ActivityPreferences{MinTemp: 10, MaxTemp: 25, MaxWind: 8, MaxPop: 0.3, NoPrecipitation: true, EarliestHour: 6, LatestHour: 20}
+	best := BestSlots(o.Slots(), p, day, day.Add(24*time.Hour))
+
+	if len(best) != 2 {
+		t.Fatalf("expected 2 acceptable slots, got %+v", best)
+	}
+	if best[0].Start.Hour() != 12 || best[1].Start.Hour() != 7 {
+		t.Errorf("expected noon first, got %v then %v", best[0].Start, best[1].Start)
+	}
+	if best[0].Score <= best[1].Score || best[0].Score > 1 {
+		t.Errorf("unexpected scores %v and %v", best[0].Score, best[1].Score)
+	}
+}
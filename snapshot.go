@@ -0,0 +1,98 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"time"
+)
+
+var (
+	errSnapshotFormat  = errors.New("not a weather snapshot")
+	errSnapshotVersion = errors.New("unsupported snapshot version")
+)
+
+// snapshotMagic starts every encoded snapshot.
+const snapshotMagic = "OWMS"
+
+// The snapshot format version. Decoders accept any minor version of their
+// major version: gob matches fields by name, so fields added by a newer
+// minor version are skipped and fields missing from an older one are left
+// zero. A new major version is only needed when a field changes meaning.
+const (
+	SnapshotMajor = 1
+	SnapshotMinor = 1
+)
+
+// Snapshot is the weather of a location at a point in time as exchanged
+// between gateway devices and servers. It encodes to a compact binary form
+// with MarshalBinary: a 4 byte magic, the major and minor version bytes
+// and the gob encoding of the snapshot.
+type Snapshot struct {
+	Location string
+	Taken    time.Time
+	Weather  CompactWeather
+	// Major and Minor are the version the snapshot was decoded from and
+	// are ignored when encoding.
+	Major, Minor uint8
+}
+
+// snapshotBody is encoded with gob in place of Snapshot, which would
+// otherwise be encoded with its own MarshalBinary.
+type snapshotBody struct {
+	Location string
+	Taken    time.Time
+	Weather  CompactWeather
+}
+
+// MarshalBinary encodes the snapshot in the current version.
+func (s *Snapshot) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString(snapshotMagic)
+	b.WriteByte(SnapshotMajor)
+	b.WriteByte(SnapshotMinor)
+	body := snapshotBody{Location: s.Location, Taken: s.Taken, Weather: s.Weather}
+	if err := gob.NewEncoder(&b).Encode(body); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes a snapshot of any minor version of the current
+// major version.
+func (s *Snapshot) UnmarshalBinary(data []byte) error {
+	header := len(snapshotMagic) + 2
+	if len(data) < header || string(data[:len(snapshotMagic)]) != snapshotMagic {
+		return errSnapshotFormat
+	}
+	major, minor := data[header-2], data[header-1]
+	if major != SnapshotMajor {
+		return errSnapshotVersion
+	}
+	var body snapshotBody
+	if err := gob.NewDecoder(bytes.NewReader(data[header:])).Decode(&body); err != nil {
+		return err
+	}
+	*s = Snapshot{Location: body.Location, Taken: body.Taken, Weather: body.Weather, Major: major, Minor: minor}
+	return nil
+}
+
+// Snapshot returns a snapshot of the current weather for the location,
+// taken at the time of the data.
+func (w *CurrentWeatherData) Snapshot(location string) *Snapshot {
+	return &Snapshot{Location: location, Taken: time.Unix(int64(w.Dt), 0).UTC(), Weather: w.Compact()}
+}
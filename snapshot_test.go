@@ -0,0 +1,76 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+	"time"
+)
+
+// TestSnapshotRoundTrip will verify snapshots decode to what was encoded.
+func TestSnapshotRoundTrip(t *testing.T) {
+	w := &CurrentWeatherData{ID: 3143244, Dt: 1700000000, Name: "Oslo", Main: Main{Temp: 3.5, Humidity: 81}}
+	b, err := w.Snapshot("oslo").MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b[:4]) != "OWMS" || b[4] != SnapshotMajor || b[5] != SnapshotMinor {
+		t.Errorf("unexpected header % x", b[:6])
+	}
+
+	var s Snapshot
+	if err := s.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	if s.Location != "oslo" || !s.Taken.Equal(time.Unix(1700000000, 0)) || s.Weather != w.Compact() || s.Major != SnapshotMajor {
+		t.Errorf("unexpected snapshot %+v", s)
+	}
+}
+
+// TestSnapshotVersions will verify snapshots of other minor versions
+// decode and other major versions are refused.
+func TestSnapshotVersions(t *testing.T) {
+	encode := func(major, minor byte, body interface{}) []byte {
+		b := bytes.NewBufferString("OWMS")
+		b.WriteByte(major)
+		b.WriteByte(minor)
+		if err := gob.NewEncoder(b).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+		return b.Bytes()
+	}
+
+	newer := struct {
+		Location string
+		Weather  CompactWeather
+		Hourly   []CompactWeather
+	}{"oslo", CompactWeather{ID: 1}, []CompactWeather{{ID: 2}}}
+	var s Snapshot
+	if err := s.UnmarshalBinary(encode(SnapshotMajor, 7, newer)); err != nil {
+		t.Fatal(err)
+	}
+	if s.Location != "oslo" || s.Weather.ID != 1 || !s.Taken.IsZero() || s.Minor != 7 {
+		t.Errorf("unexpected snapshot from a newer minor version %+v", s)
+	}
+
+	if err := s.UnmarshalBinary(encode(SnapshotMajor+1, 0, newer)); err != errSnapshotVersion {
+		t.Errorf("expected errSnapshotVersion, got %v", err)
+	}
+	if err := s.UnmarshalBinary([]byte(`{"location":"oslo"}`)); err != errSnapshotFormat {
+		t.Errorf("expected errSnapshotFormat, got %v", err)
+	}
+}
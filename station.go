@@ -15,8 +15,7 @@
 package openweathermap
 
 import (
-	"fmt"
-	"net/http"
+	"context"
 	"net/url"
 )
 
@@ -69,12 +68,23 @@ func ConvertToURLValues(data map[string]string) string {
 }
 
 // SendStationData will send an instance the provided url.Values to the
-// provided URL.
-func SendStationData(data url.Values) {
-	resp, err := http.PostForm(dataPostURL, data)
-	if err != nil {
-		fmt.Println(err)
+// provided URL. Options such as WithHttpClient, WithRetry or
+// WithRateLimiter configure the request. Responses signaling failure are
+// returned as an *APIError.
+func SendStationData(data url.Values, options ...Option) error {
+	return SendStationDataCtx(context.Background(), data, options...)
+}
+
+// SendStationDataCtx is like SendStationData but binds the request to ctx.
+func SendStationDataCtx(ctx context.Context, data url.Values, options ...Option) error {
+	s := NewSettings()
+	if err := setOptions(s, options); err != nil {
+		return err
 	}
 
-	fmt.Println(resp.Body)
+	response, err := s.post(ctx, EndpointStation, dataPostURL, data)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
 }
@@ -15,8 +15,15 @@
 package openweathermap
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
 	"reflect"
+	"strconv"
 	"testing"
+	"time"
 )
 
 // TestValidateStationDataParameter will make sure that a parameter passed
@@ -50,7 +57,7 @@ func TestConvertToURLValues(t *testing.T) {
 	var urlData = make(map[string]string)
 
 	for _, s := range StationDataParameters {
-		urlData[s] = string(count)
+		urlData[s] = strconv.Itoa(count)
 		count++
 	}
 
@@ -61,4 +68,43 @@ func TestConvertToURLValues(t *testing.T) {
 
 // TestSendStationData will make sure that weather data will be sent to
 // the OpenWeatherMap API.
-func TestSendStationData(t *testing.T) {}
+func TestSendStationData(t *testing.T) {
+	calls := 0
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Method != http.MethodPost || r.FormValue("temp") != "21.5" {
+			t.Errorf("unexpected request %s %v", r.Method, r.Form)
+		}
+		if r.FormValue("name") == "broken" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if r.FormValue("name") == "unknown" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"cod":401,"message":"Invalid API key"}`)
+		}
+	})
+	defer ts.Close()
+
+	data := url.Values{"temp": {"21.5"}, "name": {"garden"}}
+	if err := SendStationData(data, WithHttpClient(hc)); err != nil {
+		t.Fatal(err)
+	}
+
+	data.Set("name", "unknown")
+	var apiErr *APIError
+	if err := SendStationData(data, WithHttpClient(hc)); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected the API error, got %v", err)
+	}
+
+	calls = 0
+	data.Set("name", "broken")
+	policy := RetryPolicy{MaxAttempts: 2, Jitter: func(d time.Duration) time.Duration { return 0 }}
+	if err := SendStationDataCtx(context.Background(), data, WithHttpClient(hc), WithRetry(policy)); err == nil || calls != 2 {
+		t.Errorf("expected the retried failure, got %v after %d calls", err, calls)
+	}
+
+	if err := SendStationData(data, WithHttpClient(nil)); err != errInvalidHttpClient {
+		t.Errorf("expected %v, got %v", errInvalidHttpClient, err)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// StatsD sends weather values as gauges to a StatsD or DogStatsD agent
+// over UDP, e.g. weather.temp:14.2|g. Emitting is fire and forget: a
+// missing agent doesn't make weather requests fail.
+type StatsD struct {
+	conn   net.Conn
+	prefix string
+	tags   []string
+	dog    bool
+}
+
+// NewStatsD returns an emitter sending plain StatsD gauges to addr, each
+// metric name starting with prefix.
+func NewStatsD(addr, prefix string) (*StatsD, error) {
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return &StatsD{conn: conn, prefix: prefix}, nil
+}
+
+// NewDogStatsD returns an emitter sending DogStatsD gauges to addr. The
+// given tags, like "env:prod", are added to every metric along with a
+// location tag naming the city.
+func NewDogStatsD(addr, prefix string, tags ...string) (*StatsD, error) {
+	s, err := NewStatsD(addr, prefix)
+	if err != nil {
+		return nil, err
+	}
+	s.dog = true
+	s.tags = tags
+	return s, nil
+}
+
+// Close closes the connection to the agent.
+func (s *StatsD) Close() error { return s.conn.Close() }
+
+// Gauge sends a single gauge. Tags are ignored by plain StatsD emitters.
+func (s *StatsD) Gauge(name string, value float64, tags ...string) error {
+	var b bytes.Buffer
+	s.write(&b, name, value, tags)
+	_, err := s.conn.Write(b.Bytes())
+	return err
+}
+
+// EmitCurrent sends the temperature, feels like temperature, humidity,
+// pressure and wind of the current weather in one packet.
+func (s *StatsD) EmitCurrent(w *CurrentWeatherData) error {
+	tags := s.locationTags(w.Name)
+	metrics := []struct {
+		name  string
+		value float64
+	}{
+		{"temp", w.Main.Temp},
+		{"feels_like", w.Main.FeelsLike},
+		{"humidity", float64(w.Main.Humidity)},
+		{"pressure", w.Main.Pressure},
+		{"wind.speed", w.Wind.Speed},
+		{"wind.gust", w.Wind.Gust},
+	}
+
+	var b bytes.Buffer
+	for i, m := range metrics {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		s.write(&b, m.name, m.value, tags)
+	}
+	_, err := s.conn.Write(b.Bytes())
+	return err
+}
+
+// EmitPollution sends the air quality index of the latest pollution entry.
+func (s *StatsD) EmitPollution(p *Pollution) error {
+	if len(p.List) == 0 {
+		return nil
+	}
+	return s.Gauge("aqi", p.List[len(p.List)-1].Main.Aqi)
+}
+
+// Stage returns a pipeline stage emitting every current weather and
+// pollution result as it's decoded. Send errors are ignored so an
+// unreachable agent doesn't fail requests.
+func (s *StatsD) Stage() Stage {
+	return Stage{
+		Name: "statsd",
+		Run: func(result interface{}) error {
+			switch r := result.(type) {
+			case *CurrentWeatherData:
+				s.EmitCurrent(r)
+			case *CurrentWeatherGroup:
+				for _, w := range r.List {
+					s.EmitCurrent(w)
+				}
+			case *Pollution:
+				s.EmitPollution(r)
+			}
+			return nil
+		},
+	}
+}
+
+// locationTags returns the tags identifying the city in DogStatsD mode.
+func (s *StatsD) locationTags(name string) []string {
+	if !s.dog || name == "" {
+		return nil
+	}
+	return []string{"location:" + statsdName(name)}
+}
+
+// write appends a gauge line to the buffer.
+func (s *StatsD) write(b *bytes.Buffer, name string, value float64, tags []string) {
+	if s.prefix != "" {
+		b.WriteString(s.prefix)
+		b.WriteByte('.')
+	}
+	fmt.Fprintf(b, "%s:%s|g", statsdName(name), strconv.FormatFloat(value, 'f', -1, 64))
+
+	if !s.dog {
+		return
+	}
+	all := append(append([]string{}, s.tags...), tags...)
+	if len(all) > 0 {
+		b.WriteString("|#")
+		b.WriteString(strings.Join(all, ","))
+	}
+}
+
+// statsdName replaces characters with a meaning in the StatsD protocol.
+func statsdName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ':', '|', '@', '#', ',', ' ', '\n':
+			return '_'
+		}
+		return r
+	}, strings.ToLower(name))
+}
@@ -0,0 +1,99 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenUDP returns a local UDP listener and a function reading the next
+// packet from it.
+func listenUDP(t *testing.T) (net.PacketConn, func() string) {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pc, func() string {
+		buf := make([]byte, 1024)
+		pc.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := pc.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(buf[:n])
+	}
+}
+
+// TestStatsD will verify gauges are sent in the plain StatsD format.
+func TestStatsD(t *testing.T) {
+	pc, read := listenUDP(t)
+	defer pc.Close()
+
+	s, err := NewStatsD(pc.LocalAddr().String(), "weather")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	if err := s.Gauge("temp", 14.2, "ignored:tag"); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(); got != "weather.temp:14.2|g" {
+		t.Errorf("unexpected packet %q", got)
+	}
+
+	w := &CurrentWeatherData{Name: "New York", Main: Main{Temp: -3.5, Humidity: 60}}
+	if err := s.EmitCurrent(w); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(read(), "\n")
+	if len(lines) != 6 || lines[0] != "weather.temp:-3.5|g" || lines[2] != "weather.humidity:60|g" {
+		t.Errorf("unexpected packet %q", lines)
+	}
+}
+
+// TestDogStatsD will verify tags and the location tag are appended.
+func TestDogStatsD(t *testing.T) {
+	pc, read := listenUDP(t)
+	defer pc.Close()
+
+	s, err := NewDogStatsD(pc.LocalAddr().String(), "", "env:test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	stage := s.Stage()
+	if err := stage.Run(&CurrentWeatherData{Name: "New York", Main: Main{Temp: 20}}); err != nil {
+		t.Fatal(err)
+	}
+	first := strings.Split(read(), "\n")[0]
+	if first != "temp:20|g|#env:test,location:new_york" {
+		t.Errorf("unexpected line %q", first)
+	}
+
+	p := &Pollution{List: []PollutionData{{}}}
+	p.List[0].Main.Aqi = 3
+	if err := stage.Run(p); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(); got != "aqi:3|g|#env:test" {
+		t.Errorf("unexpected packet %q", got)
+	}
+}
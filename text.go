@@ -0,0 +1,120 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// spokenUnit holds the spelled out names used when rendering values for
+// screen readers along with the factor needed to turn the API's wind speed
+// into the spoken speed unit.
+type spokenUnit struct {
+	Temperature string
+	Speed       string
+	SpeedFactor float64
+}
+
+// spokenUnits maps the OWM unit system to the words read out for it. The
+// metric and internal systems report wind in meters per second which is
+// converted to kilometers per hour since it's what most listeners expect.
+var spokenUnits = map[Unit]spokenUnit{
+	Metric:   {Temperature: "degrees Celsius", Speed: "kilometers per hour", SpeedFactor: 3.6},
+	Imperial: {Temperature: "degrees Fahrenheit", Speed: "miles per hour", SpeedFactor: 1},
+	Standard: {Temperature: "kelvin", Speed: "kilometers per hour", SpeedFactor: 3.6},
+}
+
+// compassPoints holds the spelled out names of the 16 point compass rose
+// starting at north and moving clockwise.
+var compassPoints = []string{
+	"north", "north-northeast", "northeast", "east-northeast",
+	"east", "east-southeast", "southeast", "south-southeast",
+	"south", "south-southwest", "southwest", "west-southwest",
+	"west", "west-northwest", "northwest", "north-northwest",
+}
+
+// CompassDirection converts a meteorological wind direction in degrees
+// into the spelled out name of the nearest point on a 16 point compass.
+func CompassDirection(deg float64) string {
+	deg = math.Mod(deg, 360)
+	if deg < 0 {
+		deg += 360
+	}
+	return compassPoints[int(math.Floor(deg/22.5+0.5))%len(compassPoints)]
+}
+
+// spokenNumber rounds the given value and spells out the sign so it reads
+// naturally, e.g. "minus 3".
+func spokenNumber(v float64) string {
+	n := int(math.Round(v))
+	if n < 0 {
+		return fmt.Sprintf("minus %d", -n)
+	}
+	return fmt.Sprintf("%d", n)
+}
+
+// spokenTemperature renders a temperature with its unit spelled out.
+func spokenTemperature(v float64, u spokenUnit) string {
+	unit := u.Temperature
+	if math.Abs(math.Round(v)) == 1 {
+		unit = strings.Replace(unit, "degrees", "degree", 1)
+	}
+	return fmt.Sprintf("%s %s", spokenNumber(v), unit)
+}
+
+// spokenWind renders the wind speed and direction, e.g. "wind from the
+// northwest at 12 kilometers per hour".
+func spokenWind(w Wind, u spokenUnit) string {
+	speed := int(math.Round(w.Speed * u.SpeedFactor))
+	if speed == 0 {
+		return "calm wind"
+	}
+	unit := u.Speed
+	if speed == 1 {
+		unit = strings.Replace(unit, "kilometers", "kilometer", 1)
+		unit = strings.Replace(unit, "miles", "mile", 1)
+	}
+	return fmt.Sprintf("wind from the %s at %d %s", CompassDirection(w.Deg), speed, unit)
+}
+
+// AccessibleText renders the current conditions as plain text suited for
+// screen readers. No symbols or abbreviations are used and units and wind
+// direction are spelled out, e.g. "Philadelphia: clear sky, 14 degrees
+// Celsius, humidity 40 percent, wind from the northwest at 12 kilometers
+// per hour."
+func (w *CurrentWeatherData) AccessibleText() string {
+	u, ok := spokenUnits[w.Unit]
+	if !ok {
+		u = spokenUnits[Standard]
+	}
+
+	parts := make([]string, 0, 4)
+	if len(w.Weather) > 0 {
+		parts = append(parts, w.Weather[0].Description)
+	}
+	parts = append(parts,
+		spokenTemperature(w.Main.Temp, u),
+		fmt.Sprintf("humidity %d percent", w.Main.Humidity),
+		spokenWind(w.Wind, u),
+	)
+
+	text := strings.Join(parts, ", ") + "."
+	if w.Name != "" {
+		text = w.Name + ": " + text
+	}
+	return text
+}
@@ -0,0 +1,69 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"testing"
+)
+
+// TestCompassDirection will verify that degrees are mapped to the nearest
+// spelled out compass point.
+func TestCompassDirection(t *testing.T) {
+	tests := map[float64]string{
+		0:      "north",
+		11:     "north",
+		12:     "north-northeast",
+		90:     "east",
+		315:    "northwest",
+		350:    "north",
+		360:    "north",
+		-45:    "northwest",
+		202.5:  "south-southwest",
+		720.25: "north",
+	}
+
+	for deg, expected := range tests {
+		if got := CompassDirection(deg); got != expected {
+			t.Errorf("expected %s for %v degrees, got %s", expected, deg, got)
+		}
+	}
+}
+
+// TestAccessibleText will verify that current conditions are rendered
+// without symbols and with units spelled out.
+func TestAccessibleText(t *testing.T) {
+	w := &CurrentWeatherData{
+		Name:    "Philadelphia",
+		Weather: []Weather{{Description: "clear sky"}},
+		Main:    Main{Temp: 14.2, Humidity: 40},
+		Wind:    Wind{Speed: 3.3, Deg: 315},
+		Unit:    "metric",
+	}
+
+	expected := "Philadelphia: clear sky, 14 degrees Celsius, humidity 40 percent, wind from the northwest at 12 kilometers per hour."
+	if got := w.AccessibleText(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+
+	w = &CurrentWeatherData{
+		Main: Main{Temp: -1.1, Humidity: 80},
+		Unit: "imperial",
+	}
+
+	expected = "minus 1 degree Fahrenheit, humidity 80 percent, calm wind."
+	if got := w.AccessibleText(); got != expected {
+		t.Errorf("expected %q, got %q", expected, got)
+	}
+}
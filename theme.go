@@ -0,0 +1,123 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"os"
+	"strconv"
+)
+
+// ANSI SGR color codes usable in a Palette.
+const (
+	ColorRed     = "31"
+	ColorGreen   = "32"
+	ColorYellow  = "33"
+	ColorBlue    = "34"
+	ColorMagenta = "35"
+	ColorCyan    = "36"
+	ColorWhite   = "37"
+	ColorMaroon  = "38;5;88"
+)
+
+// Band assigns a color to every value below Max. Bands are expected to be
+// sorted by Max in ascending order; values above the last band use the
+// color of the last band.
+type Band struct {
+	Max   float64
+	Color string
+}
+
+// Palette holds the color bands used by a Theme. Temperature bands are
+// expressed in degrees Celsius regardless of the unit the data was
+// requested in. AQI bands follow the OWM 1 (good) to 5 (very poor) index.
+type Palette struct {
+	Temperature []Band
+	AQI         []Band
+}
+
+// DefaultPalette goes from blue for freezing temperatures to red for hot
+// ones and from green to maroon for the air quality index.
+var DefaultPalette = Palette{
+	Temperature: []Band{
+		{Max: 0, Color: ColorBlue},
+		{Max: 10, Color: ColorCyan},
+		{Max: 20, Color: ColorGreen},
+		{Max: 30, Color: ColorYellow},
+		{Max: 35, Color: ColorRed},
+		{Max: 100, Color: ColorMagenta},
+	},
+	AQI: []Band{
+		{Max: 2, Color: ColorGreen},
+		{Max: 3, Color: ColorYellow},
+		{Max: 4, Color: ColorRed},
+		{Max: 5, Color: ColorMagenta},
+		{Max: 6, Color: ColorMaroon},
+	},
+}
+
+// Theme colors values written to a terminal so output can be scanned at a
+// glance on status dashboards.
+type Theme struct {
+	Palette  Palette
+	Disabled bool // when set no escape sequences are written
+}
+
+// NewTheme returns a Theme using the given palette. Following the
+// https://no-color.org convention, coloring is disabled when the NO_COLOR
+// environment variable is set to a non empty value.
+func NewTheme(p Palette) *Theme {
+	return &Theme{
+		Palette:  p,
+		Disabled: os.Getenv("NO_COLOR") != "",
+	}
+}
+
+// Paint wraps s in the escape sequence for the given color code.
+func (t *Theme) Paint(color, s string) string {
+	if t.Disabled || color == "" {
+		return s
+	}
+	return "\x1b[" + color + "m" + s + "\x1b[0m"
+}
+
+// bandColor finds the color of the band the value falls in.
+func bandColor(bands []Band, v float64) string {
+	if len(bands) == 0 {
+		return ""
+	}
+	for _, b := range bands {
+		if v < b.Max {
+			return b.Color
+		}
+	}
+	return bands[len(bands)-1].Color
+}
+
+// toCelsius converts a temperature in the given OWM unit system to
+// degrees Celsius.
+func toCelsius(v float64, unit Unit) float64 {
+	return Degrees{Value: v, Unit: unit}.ToCelsius()
+}
+
+// Temperature renders the temperature, given in the OWM unit system the
+// data was requested in, colored by the temperature band it falls in.
+func (t *Theme) Temperature(v float64, unit Unit) string {
+	return t.Paint(bandColor(t.Palette.Temperature, toCelsius(v, unit)), strconv.FormatFloat(v, 'f', -1, 64))
+}
+
+// AQI renders the air quality index colored by its band.
+func (t *Theme) AQI(aqi float64) string {
+	return t.Paint(bandColor(t.Palette.AQI, aqi), strconv.FormatFloat(aqi, 'f', -1, 64))
+}
@@ -0,0 +1,71 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"os"
+	"testing"
+)
+
+// TestThemeTemperature will verify temperatures are colored by their
+// Celsius band regardless of the requested unit.
+func TestThemeTemperature(t *testing.T) {
+	th := &Theme{Palette: DefaultPalette}
+
+	tests := []struct {
+		value    float64
+		unit     Unit
+		expected string
+	}{
+		{-5, "metric", "\x1b[34m-5\x1b[0m"},
+		{15.5, "metric", "\x1b[32m15.5\x1b[0m"},
+		{90, "imperial", "\x1b[31m90\x1b[0m"},
+		{273.15, "internal", "\x1b[36m273.15\x1b[0m"},
+		{150, "metric", "\x1b[35m150\x1b[0m"},
+	}
+
+	for _, test := range tests {
+		if got := th.Temperature(test.value, test.unit); got != test.expected {
+			t.Errorf("expected %q, got %q", test.expected, got)
+		}
+	}
+}
+
+// TestThemeAQI will verify the air quality index is colored by band.
+func TestThemeAQI(t *testing.T) {
+	th := &Theme{Palette: DefaultPalette}
+
+	if got := th.AQI(1); got != "\x1b[32m1\x1b[0m" {
+		t.Errorf("unexpected AQI rendering %q", got)
+	}
+	if got := th.AQI(5); got != "\x1b[38;5;88m5\x1b[0m" {
+		t.Errorf("unexpected AQI rendering %q", got)
+	}
+}
+
+// TestNewThemeNoColor will verify the NO_COLOR convention is honored.
+func TestNewThemeNoColor(t *testing.T) {
+	defer os.Setenv("NO_COLOR", os.Getenv("NO_COLOR"))
+
+	os.Setenv("NO_COLOR", "1")
+	if th := NewTheme(DefaultPalette); th.Temperature(20, "metric") != "20" {
+		t.Error("expected no color when NO_COLOR is set")
+	}
+
+	os.Setenv("NO_COLOR", "")
+	if th := NewTheme(DefaultPalette); th.Disabled {
+		t.Error("expected color when NO_COLOR is empty")
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+var (
+	errLayerUnavailable = errors.New("map layer unavailable")
+	errInvalidTile      = errors.New("invalid tile coordinates")
+)
+
+// TileLayer names a weather map layer.
+type TileLayer string
+
+// Weather map layers available as tiles.
+const (
+	LayerClouds        TileLayer = "clouds_new"
+	LayerPrecipitation TileLayer = "precipitation_new"
+	LayerPressure      TileLayer = "pressure_new"
+	LayerWind          TileLayer = "wind_new"
+	LayerTemp          TileLayer = "temp_new"
+)
+
+// TileLayers holds every supported map layer.
+var TileLayers = []TileLayer{
+	LayerClouds,
+	LayerPrecipitation,
+	LayerPressure,
+	LayerWind,
+	LayerTemp,
+}
+
+// maxZoom is the deepest zoom level tiles are served for.
+const maxZoom = 18
+
+// Tiles builds and downloads weather map tiles to overlay on slippy maps.
+type Tiles struct {
+	Key string
+	*Settings
+}
+
+// NewTiles creates a new reference to Tiles
+func NewTiles(key string, options ...Option) (*Tiles, error) {
+	c, err := NewClient(key, options...)
+	if err != nil {
+		return nil, err
+	}
+	return c.Tiles(), nil
+}
+
+// ValidTileLayer makes sure the layer given is a supported one.
+func ValidTileLayer(layer TileLayer) bool {
+	for _, l := range TileLayers {
+		if l == layer {
+			return true
+		}
+	}
+	return false
+}
+
+// URL returns the URL of the PNG tile of the layer at zoom level z and
+// tile coordinates x and y, which embeds the API key.
+func (t *Tiles) URL(layer TileLayer, z, x, y int) (string, error) {
+	if !ValidTileLayer(layer) {
+		return "", errLayerUnavailable
+	}
+	if z < 0 || z > maxZoom || x < 0 || y < 0 || x >= 1<<uint(z) || y >= 1<<uint(z) {
+		return "", errInvalidTile
+	}
+	return fmt.Sprintf(tileURL, layer, z, x, y, t.Key), nil
+}
+
+// Fetch downloads the PNG tile of the layer at z, x and y.
+func (t *Tiles) Fetch(layer TileLayer, z, x, y int) ([]byte, error) {
+	return t.FetchCtx(context.Background(), layer, z, x, y)
+}
+
+// FetchCtx is like Fetch but the request is bound to ctx, which cancels
+// it or sets its deadline.
+func (t *Tiles) FetchCtx(ctx context.Context, layer TileLayer, z, x, y int) ([]byte, error) {
+	uri, err := t.URL(layer, z, x, y)
+	if err != nil {
+		return nil, err
+	}
+	response, err := t.get(ctx, EndpointTiles, uri)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	return ioutil.ReadAll(response.Body)
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+// TestTilesURL will verify tile URLs are built and validated.
+func TestTilesURL(t *testing.T) {
+	tiles, err := NewTiles("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := tiles.URL(LayerWind, 3, 4, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "https://tile.openweathermap.org/map/wind_new/3/4/2.png?appid=key"; u != expected {
+		t.Errorf("expected %s, got %s", expected, u)
+	}
+
+	if _, err := tiles.URL("snow_new", 1, 0, 0); err != errLayerUnavailable {
+		t.Errorf("expected %v, got %v", errLayerUnavailable, err)
+	}
+	for _, c := range [][3]int{{-1, 0, 0}, {19, 0, 0}, {2, 4, 0}, {2, 0, -1}} {
+		if _, err := tiles.URL(LayerTemp, c[0], c[1], c[2]); err != errInvalidTile {
+			t.Errorf("%v: expected %v, got %v", c, errInvalidTile, err)
+		}
+	}
+}
+
+// TestTilesFetch will verify tiles are downloaded with the configured
+// client.
+func TestTilesFetch(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n")
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/map/clouds_new/0/0/0.png":
+			w.Write(png)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer ts.Close()
+
+	tiles, err := NewTiles("key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := tiles.Fetch(LayerClouds, 0, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b, png) {
+		t.Errorf("unexpected tile %q", b)
+	}
+	if _, err := tiles.Fetch(LayerTemp, 0, 0, 0); err == nil {
+		t.Error("expected an error for a missing tile")
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// TimeMachineData holds the weather at a past time from the one call
+// timemachine endpoint. It's separate from HistoricalWeatherData, which
+// holds results of the history endpoint.
+//
+// The One Call API 2.5 returns the conditions at the requested time in
+// Current along with the Hourly data of that day, while 3.0 returns them
+// in Data.
+type TimeMachineData struct {
+	Latitude       float64              `json:"lat"`
+	Longitude      float64              `json:"lon"`
+	Timezone       string               `json:"timezone"`
+	TimezoneOffset int                  `json:"timezone_offset"`
+	Current        *OneCallCurrentData  `json:"current,omitempty"`
+	Hourly         []OneCallHourlyData  `json:"hourly,omitempty"`
+	Data           []OneCallCurrentData `json:"data,omitempty"`
+
+	Unit Unit
+	Lang string
+	Key  string
+	*Settings
+}
+
+// NewTimeMachine returns a new TimeMachineData pointer with the supplied
+// parameters. It honors WithOneCall3 like NewOneCall.
+func NewTimeMachine(unit, lang, key string, options ...Option) (*TimeMachineData, error) {
+	c, err := newClient(unit, lang, key, options)
+	if err != nil {
+		return nil, err
+	}
+	return c.TimeMachine(), nil
+}
+
+// TimeMachineByCoordinates will provide the weather at the provided
+// location coordinates at the given past time.
+func (t *TimeMachineData) TimeMachineByCoordinates(location *Coordinates, at time.Time) error {
+	return t.TimeMachineByCoordinatesCtx(context.Background(), location, at)
+}
+
+// TimeMachineByCoordinatesCtx is like TimeMachineByCoordinates but the
+// request is bound to ctx, which cancels it or sets its deadline.
+func (t *TimeMachineData) TimeMachineByCoordinatesCtx(ctx context.Context, location *Coordinates, at time.Time) error {
+	uri := timemachineURL
+	if t.oneCall3 {
+		uri = timemachine3URL
+	}
+	v := url.Values{
+		"appid": {t.Key},
+		"lat":   {strconv.FormatFloat(location.Latitude, 'f', -1, 64)},
+		"lon":   {strconv.FormatFloat(location.Longitude, 'f', -1, 64)},
+		"dt":    {strconv.FormatInt(at.Unix(), 10)},
+		"units": {string(t.Unit)},
+		"lang":  {t.Lang},
+	}
+	response, err := t.get(ctx, EndpointOneCall, fmt.Sprintf(uri, v.Encode()))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	t.Current, t.Hourly, t.Data = nil, nil, nil
+	if err = json.NewDecoder(response.Body).Decode(&t); err != nil {
+		return err
+	}
+
+	return t.postDecode(t)
+}
+
+// Conditions returns the conditions at the requested time whichever API
+// version answered, or false if the response held none.
+func (t *TimeMachineData) Conditions() (OneCallCurrentData, bool) {
+	if t.Current != nil {
+		return *t.Current, true
+	}
+	if len(t.Data) > 0 {
+		return t.Data[0], true
+	}
+	return OneCallCurrentData{}, false
+}
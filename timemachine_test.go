@@ -0,0 +1,78 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+// TestTimeMachineByCoordinates will verify both API versions are requested
+// and decoded.
+func TestTimeMachineByCoordinates(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("dt") != "1699990000" || r.URL.Query().Get("units") != "metric" {
+			t.Errorf("unexpected query %s", r.URL.RawQuery)
+		}
+		switch r.URL.Path {
+		case "/data/2.5/onecall/timemachine":
+			w.Write([]byte(`{"lat":51.5,"lon":-0.12,"timezone":"Europe/London","timezone_offset":0,"current":{"dt":1699990000,"temp":9.5,"weather":[{"id":500}]},"hourly":[{"dt":1699974000,"temp":8},{"dt":1699977600,"temp":8.5}]}`))
+		case "/data/3.0/onecall/timemachine":
+			w.Write([]byte(`{"lat":51.5,"lon":-0.12,"timezone":"Europe/London","timezone_offset":0,"data":[{"dt":1699990000,"temp":9.7,"weather":[{"id":500}]}]}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer ts.Close()
+
+	coord := &Coordinates{Latitude: 51.5, Longitude: -0.12}
+	at := time.Unix(1699990000, 0)
+
+	tm, err := NewTimeMachine("C", "EN", "key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tm.TimeMachineByCoordinates(coord, at); err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := tm.Conditions(); !ok || c.Temp != 9.5 || len(tm.Hourly) != 2 {
+		t.Errorf("unexpected 2.5 result %+v", tm)
+	}
+
+	tm3, err := NewTimeMachine("C", "EN", "key", WithHttpClient(hc), WithOneCall3())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tm3.TimeMachineByCoordinates(coord, at); err != nil {
+		t.Fatal(err)
+	}
+	if c, ok := tm3.Conditions(); !ok || c.Temp != 9.7 || tm3.Current != nil {
+		t.Errorf("unexpected 3.0 result %+v", tm3)
+	}
+}
+
+// TestNewTimeMachineInvalid will verify the parameters are validated.
+func TestNewTimeMachineInvalid(t *testing.T) {
+	if _, err := NewTimeMachine("X", "EN", "key"); err != errUnitUnavailable {
+		t.Errorf("expected %v, got %v", errUnitUnavailable, err)
+	}
+	if _, err := NewTimeMachine("C", "XX", "key"); err != errLangUnavailable {
+		t.Errorf("expected %v, got %v", errLangUnavailable, err)
+	}
+	if _, err := NewTimeMachine("C", "EN", "key", nil); err != errInvalidOption {
+		t.Errorf("expected %v, got %v", errInvalidOption, err)
+	}
+}
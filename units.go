@@ -0,0 +1,115 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+// Unit is the unit system results are returned in, as set with the "C",
+// "F" or "K" unit of a constructor.
+type Unit string
+
+// The unit systems of the API.
+const (
+	Metric   Unit = "metric"
+	Imperial Unit = "imperial"
+	// Standard, requested with "K", has temperatures in Kelvin and speeds
+	// in meters per second.
+	Standard Unit = "internal"
+)
+
+// Degrees is a temperature in the unit system it was returned in.
+type Degrees struct {
+	Value float64
+	Unit  Unit
+}
+
+// ToCelsius returns the temperature in degrees Celsius.
+func (t Degrees) ToCelsius() float64 {
+	switch t.Unit {
+	case Imperial:
+		return (t.Value - 32) * 5 / 9
+	case Metric:
+		return t.Value
+	default:
+		return t.Value - 273.15
+	}
+}
+
+// ToFahrenheit returns the temperature in degrees Fahrenheit.
+func (t Degrees) ToFahrenheit() float64 {
+	if t.Unit == Imperial {
+		return t.Value
+	}
+	return t.ToCelsius()*9/5 + 32
+}
+
+// ToKelvin returns the temperature in Kelvin.
+func (t Degrees) ToKelvin() float64 {
+	if t.Unit != Imperial && t.Unit != Metric {
+		return t.Value
+	}
+	return t.ToCelsius() + 273.15
+}
+
+// Speed is a wind speed in the unit system it was returned in.
+type Speed struct {
+	Value float64
+	Unit  Unit
+}
+
+// ToMs returns the speed in meters per second.
+func (s Speed) ToMs() float64 {
+	if s.Unit == Imperial {
+		return s.Value * 0.44704
+	}
+	return s.Value
+}
+
+// ToKmh returns the speed in kilometers per hour.
+func (s Speed) ToKmh() float64 { return s.ToMs() * 3.6 }
+
+// ToMph returns the speed in miles per hour.
+func (s Speed) ToMph() float64 {
+	if s.Unit == Imperial {
+		return s.Value
+	}
+	return s.Value / 0.44704
+}
+
+// ToKnots returns the speed in knots.
+func (s Speed) ToKnots() float64 { return s.ToMs() * 1.943844 }
+
+// TempIn returns the temperature, returned in the unit system u.
+func (m Main) TempIn(u Unit) Degrees { return Degrees{Value: m.Temp, Unit: u} }
+
+// FeelsLikeIn returns the perceived temperature, returned in the unit
+// system u.
+func (m Main) FeelsLikeIn(u Unit) Degrees { return Degrees{Value: m.FeelsLike, Unit: u} }
+
+// SpeedIn returns the wind speed, returned in the unit system u.
+func (w Wind) SpeedIn(u Unit) Speed { return Speed{Value: w.Speed, Unit: u} }
+
+// GustIn returns the wind gust speed, returned in the unit system u.
+func (w Wind) GustIn(u Unit) Speed { return Speed{Value: w.Gust, Unit: u} }
+
+// Temp returns the temperature along with the unit system of the data,
+// e.g. to display it in Fahrenheit with w.Temp().ToFahrenheit().
+func (w *CurrentWeatherData) Temp() Degrees { return w.Main.TempIn(w.Unit) }
+
+// FeelsLike returns the perceived temperature along with the unit system
+// of the data.
+func (w *CurrentWeatherData) FeelsLike() Degrees { return w.Main.FeelsLikeIn(w.Unit) }
+
+// WindSpeed returns the wind speed along with the unit system of the
+// data, e.g. to display it in km/h with w.WindSpeed().ToKmh().
+func (w *CurrentWeatherData) WindSpeed() Speed { return w.Wind.SpeedIn(w.Unit) }
@@ -0,0 +1,42 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"math"
+	"testing"
+)
+
+// TestUnitConversions will verify temperatures and speeds convert from
+// whichever unit system they were returned in.
+func TestUnitConversions(t *testing.T) {
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+	for _, d := range []Degrees{{20, Metric}, {68, Imperial}, {293.15, Standard}} {
+		if !near(d.ToCelsius(), 20) || !near(d.ToFahrenheit(), 68) || !near(d.ToKelvin(), 293.15) {
+			t.Errorf("unexpected conversions of %+v: %v %v %v", d, d.ToCelsius(), d.ToFahrenheit(), d.ToKelvin())
+		}
+	}
+	for _, s := range []Speed{{10, Metric}, {10, Standard}, {10 / 0.44704, Imperial}} {
+		if !near(s.ToMs(), 10) || !near(s.ToKmh(), 36) || !near(s.ToMph(), 10/0.44704) || !near(s.ToKnots(), 19.43844) {
+			t.Errorf("unexpected conversions of %+v: %v %v %v %v", s, s.ToMs(), s.ToKmh(), s.ToMph(), s.ToKnots())
+		}
+	}
+
+	w := &CurrentWeatherData{Unit: Imperial, Main: Main{Temp: 50}, Wind: Wind{Speed: 5}}
+	if !near(w.Temp().ToCelsius(), 10) || !near(w.WindSpeed().ToMph(), 5) {
+		t.Errorf("unexpected conversions of the current weather %v %v", w.Temp().ToCelsius(), w.WindSpeed().ToMph())
+	}
+}
@@ -1,14 +1,23 @@
 package openweathermap
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
+	"io"
+	"io/ioutil"
 	"time"
 )
 
-var errInvalidUVIndex = errors.New("invalid UV index value")
+var (
+	errInvalidUVIndex = errors.New("invalid UV index value")
+	errUVForecastDays = errors.New("count of forecast days should be between 1 and 8")
+)
+
+// maxUVForecastDays is the most days of UV index forecast available.
+const maxUVForecastDays = 8
 
 // UVDataPoints holds the UV specific data
 type UVDataPoints struct {
@@ -32,59 +41,119 @@ type UV struct {
 
 // NewUV creates a new reference to UV
 func NewUV(key string, options ...Option) (*UV, error) {
-	k, err := setKey(key)
+	c, err := NewClient(key, options...)
 	if err != nil {
 		return nil, err
 	}
-	u := &UV{
-		Key:      k,
-		Settings: NewSettings(),
-	}
-
-	if err := setOptions(u.Settings, options); err != nil {
-		return nil, err
-	}
-	return u, nil
+	return c.UV(), nil
 }
 
 // Current gets the current UV data for the given coordinates
 func (u *UV) Current(coord *Coordinates) error {
-	response, err := u.client.Get(fmt.Sprintf("%suvi?lat=%f&lon=%f&appid=%s", uvURL, coord.Latitude, coord.Longitude, u.Key))
+	return u.CurrentCtx(context.Background(), coord)
+}
+
+// CurrentCtx is like Current but the request is bound to ctx, which
+// cancels it or sets its deadline.
+func (u *UV) CurrentCtx(ctx context.Context, coord *Coordinates) error {
+	response, err := u.get(ctx, EndpointUV, fmt.Sprintf("%suvi?lat=%f&lon=%f&appid=%s", uvURL, coord.Latitude, coord.Longitude, u.Key))
 	if err != nil {
 		return err
 	}
 
 	defer response.Body.Close()
 
-	if response.StatusCode == http.StatusUnauthorized {
-		return errInvalidKey
-	}
+	return u.decode(response.Body)
+}
+
+// Historical gets the historical UV data for the coordinates and times
+func (u *UV) Historical(coord *Coordinates, start, end time.Time) error {
+	return u.HistoricalCtx(context.Background(), coord, start, end)
+}
 
-	if err = json.NewDecoder(response.Body).Decode(&u); err != nil {
+// HistoricalCtx is like Historical but the request is bound to ctx,
+// which cancels it or sets its deadline.
+func (u *UV) HistoricalCtx(ctx context.Context, coord *Coordinates, start, end time.Time) error {
+	response, err := u.get(ctx, EndpointUV, fmt.Sprintf("%suvi/history?lat=%f&lon=%f&start=%d&end=%d&appid=%s", uvURL, coord.Latitude, coord.Longitude, start.Unix(), end.Unix(), u.Key))
+	if err != nil {
 		return err
 	}
 
-	return nil
+	defer response.Body.Close()
+
+	return u.decode(response.Body)
 }
 
-// Historical gets the historical UV data for the coordinates and times
-func (u *UV) Historical(coord *Coordinates, start, end time.Time) error {
-	response, err := u.client.Get(fmt.Sprintf("%shistory?lat=%f&lon=%f&start=%d&end=%d&appid=%s", uvURL, coord.Latitude, coord.Longitude, start.Unix(), end.Unix(), u.Key))
+// Forecast gets the daily UV index forecast for the coordinates, for 1
+// to 8 days ahead, into Data.
+func (u *UV) Forecast(coord *Coordinates, cnt int) error {
+	return u.ForecastCtx(context.Background(), coord, cnt)
+}
+
+// ForecastCtx is like Forecast but the request is bound to ctx, which
+// cancels it or sets its deadline.
+func (u *UV) ForecastCtx(ctx context.Context, coord *Coordinates, cnt int) error {
+	if cnt < 1 || cnt > maxUVForecastDays {
+		return errUVForecastDays
+	}
+	response, err := u.get(ctx, EndpointUV, fmt.Sprintf("%suvi/forecast?lat=%f&lon=%f&cnt=%d&appid=%s", uvURL, coord.Latitude, coord.Longitude, cnt, u.Key))
 	if err != nil {
 		return err
 	}
 
 	defer response.Body.Close()
 
-	if response.StatusCode == http.StatusUnauthorized {
-		return errInvalidKey
-	}
+	return u.decode(response.Body)
+}
+
+// uviValue is a single value of the uvi endpoints.
+type uviValue struct {
+	Lat   float64 `json:"lat"`
+	Lon   float64 `json:"lon"`
+	Date  int64   `json:"date"`
+	Value float64 `json:"value"`
+}
 
-	if err = json.NewDecoder(response.Body).Decode(&u); err != nil {
+// decode unmarshals a uvi response into u. Current values are objects,
+// setting DT and Value, while forecasts and history are arrays of them,
+// setting Data. Coord is set to the latitude and longitude either way.
+func (u *UV) decode(r io.Reader) error {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	if b = bytes.TrimSpace(b); len(b) > 0 && b[0] == '[' {
+		var values []uviValue
+		if err := json.Unmarshal(b, &values); err != nil {
+			return err
+		}
+		u.Data = make([]UVDataPoints, 0, len(values))
+		for _, v := range values {
+			u.Data = append(u.Data, UVDataPoints{DT: v.Date, Value: v.Value})
+		}
+		u.DT, u.Value = 0, 0
+		if len(values) > 0 {
+			u.Coord = []float64{values[0].Lat, values[0].Lon}
+		}
+		return u.postDecode(u)
+	}
+
+	var v uviValue
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, &u); err != nil {
+		return err
+	}
+	u.Data = nil
+	if v.Date != 0 {
+		u.DT = v.Date
+	}
+	if v.Lat != 0 || v.Lon != 0 {
+		u.Coord = []float64{v.Lat, v.Lon}
+	}
+	return u.postDecode(u)
 }
 
 // UVIndexInfo
@@ -106,33 +175,33 @@ type UVIndexInfo struct {
 // UVData contains data in regards to UV index ranges, rankings, and steps for protection
 var UVData = []UVIndexInfo{
 	{
-		UVIndex: []float64{0, 2.9},
-		MGC:     "Green",
-		Risk:    "Low",
+		UVIndex:               []float64{0, 2.9},
+		MGC:                   "Green",
+		Risk:                  "Low",
 		RecommendedProtection: "Wear sunglasses on bright days; use sunscreen if there is snow on the ground, which reflects UV radiation, or if you have particularly fair skin.",
 	},
 	{
-		UVIndex: []float64{3, 5.9},
-		MGC:     "Yellow",
-		Risk:    "Moderate",
+		UVIndex:               []float64{3, 5.9},
+		MGC:                   "Yellow",
+		Risk:                  "Moderate",
 		RecommendedProtection: "Take precautions, such as covering up, if you will be outside. Stay in shade near midday when the sun is strongest.",
 	},
 	{
-		UVIndex: []float64{6, 7.9},
-		MGC:     "Orange",
-		Risk:    "High",
+		UVIndex:               []float64{6, 7.9},
+		MGC:                   "Orange",
+		Risk:                  "High",
 		RecommendedProtection: "Cover the body with sun protective clothing, use SPF 30+ sunscreen, wear a hat, reduce time in the sun within three hours of solar noon, and wear sunglasses.",
 	},
 	{
-		UVIndex: []float64{8, 10.9},
-		MGC:     "Red",
-		Risk:    "Very high",
+		UVIndex:               []float64{8, 10.9},
+		MGC:                   "Red",
+		Risk:                  "Very high",
 		RecommendedProtection: "Wear SPF 30+ sunscreen, a shirt, sunglasses, and a wide-brimmed hat. Do not stay in the sun for too long.",
 	},
 	{
-		UVIndex: []float64{11},
-		MGC:     "Violet",
-		Risk:    "Extreme",
+		UVIndex:               []float64{11},
+		MGC:                   "Violet",
+		Risk:                  "Extreme",
 		RecommendedProtection: "Take all precautions: Wear SPF 30+ sunscreen, a long-sleeved shirt and trousers, sunglasses, and a very broad hat. Avoid the sun within three hours of solar noon.",
 	},
 }
@@ -142,40 +211,42 @@ var UVData = []UVIndexInfo{
 func (u *UV) UVInformation() ([]UVIndexInfo, error) {
 	switch {
 	case u.Value != 0:
-		switch {
-		case u.Value < 2.9:
-			return []UVIndexInfo{UVData[0]}, nil
-		case u.Value > 3 && u.Value < 5.9:
-			return []UVIndexInfo{UVData[1]}, nil
-		case u.Value > 6 && u.Value < 7.9:
-			return []UVIndexInfo{UVData[2]}, nil
-		case u.Value > 8 && u.Value < 10.9:
-			return []UVIndexInfo{UVData[3]}, nil
-		case u.Value >= 11:
-			return []UVIndexInfo{UVData[4]}, nil
-		default:
-			return nil, errInvalidUVIndex
+		info, err := uvIndexInfo(u.Value)
+		if err != nil {
+			return nil, err
 		}
+		return []UVIndexInfo{info}, nil
 
 	case len(u.Data) > 0:
 		var uvi []UVIndexInfo
 		for _, i := range u.Data {
-			switch {
-			case i.Value < 2.9:
-				uvi = append(uvi, UVData[0])
-			case i.Value > 3 && u.Value < 5.9:
-				uvi = append(uvi, UVData[1])
-			case i.Value > 6 && u.Value < 7.9:
-				uvi = append(uvi, UVData[2])
-			case i.Value > 8 && u.Value < 10.9:
-				uvi = append(uvi, UVData[3])
-			case i.Value >= 11:
-				uvi = append(uvi, UVData[4])
-			default:
-				return nil, errInvalidUVIndex
+			info, err := uvIndexInfo(i.Value)
+			if err != nil {
+				return nil, err
 			}
+			uvi = append(uvi, info)
 		}
+		return uvi, nil
 	}
 
 	return nil, nil
 }
+
+// uvIndexInfo returns the UVData range the index falls in. Each range
+// starts at the lower bound of its UVIndex, so fractional values between
+// the listed bounds, e.g. 2.95, belong to the range below.
+func uvIndexInfo(v float64) (UVIndexInfo, error) {
+	switch {
+	case v < 3:
+		return UVData[0], nil
+	case v < 6:
+		return UVData[1], nil
+	case v < 8:
+		return UVData[2], nil
+	case v < 11:
+		return UVData[3], nil
+	case v >= 11:
+		return UVData[4], nil
+	}
+	return UVIndexInfo{}, errInvalidUVIndex
+}
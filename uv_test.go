@@ -1,6 +1,8 @@
 package openweathermap
 
 import (
+	"fmt"
+	"math"
 	"net/http"
 	"os"
 	"reflect"
@@ -138,3 +140,80 @@ func TestUVInformation(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+// TestUVEndpoints will verify current, forecast and historical uvi
+// responses are decoded.
+func TestUVEndpoints(t *testing.T) {
+	ts, hc := newTestServer(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/data/2.5/uvi":
+			fmt.Fprint(w, `{"lat":37.75,"lon":-122.37,"date_iso":"2017-06-23T12:00:00Z","date":1498219200,"value":10.16}`)
+		case "/data/2.5/uvi/forecast":
+			if r.URL.Query().Get("cnt") != "2" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `[
+				{"lat":37.75,"lon":-122.37,"date_iso":"2017-06-24T12:00:00Z","date":1498305600,"value":4.5},
+				{"lat":37.75,"lon":-122.37,"date_iso":"2017-06-25T12:00:00Z","date":1498392000,"value":9.1}]`)
+		case "/data/2.5/uvi/history":
+			if r.URL.Query().Get("start") != "1498049953" {
+				t.Errorf("unexpected query %s", r.URL.RawQuery)
+			}
+			fmt.Fprint(w, `[{"lat":37.75,"lon":-122.37,"date_iso":"2017-06-22T12:00:00Z","date":1498132800,"value":1.2}]`)
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	})
+	defer ts.Close()
+
+	uv, err := NewUV("key", WithHttpClient(hc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	location := &Coordinates{Latitude: 37.75, Longitude: -122.37}
+
+	if err := uv.Current(location); err != nil {
+		t.Fatal(err)
+	}
+	if uv.DT != 1498219200 || uv.Value != 10.16 || uv.Coord[0] != 37.75 {
+		t.Errorf("unexpected current UV %+v", uv)
+	}
+
+	if err := uv.Forecast(location, 9); err != errUVForecastDays {
+		t.Errorf("expected %v, got %v", errUVForecastDays, err)
+	}
+	if err := uv.Forecast(location, 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(uv.Data) != 2 || uv.Data[1].DT != 1498392000 || uv.Value != 0 {
+		t.Fatalf("unexpected forecast %+v", uv.Data)
+	}
+	info, err := uv.UVInformation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(info) != 2 || info[0].Risk != "Moderate" || info[1].Risk != "Very high" {
+		t.Errorf("unexpected forecast information %+v", info)
+	}
+
+	if err := uv.Historical(location, time.Unix(1498049953, 0), time.Unix(1498481991, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if len(uv.Data) != 1 || uv.Data[0].Value != 1.2 {
+		t.Errorf("unexpected history %+v", uv.Data)
+	}
+}
+
+// TestUVIndexInfo will verify every index, fractional ones between the
+// listed bounds included, falls in a range.
+func TestUVIndexInfo(t *testing.T) {
+	for v, risk := range map[float64]string{0.5: "Low", 2.95: "Low", 3: "Moderate", 5.95: "Moderate", 7.95: "High", 8: "Very high", 10.95: "Very high", 11: "Extreme"} {
+		info, err := uvIndexInfo(v)
+		if err != nil || info.Risk != risk {
+			t.Errorf("expected %v to be %s, got %q (%v)", v, risk, info.Risk, err)
+		}
+	}
+	if _, err := uvIndexInfo(math.NaN()); err != errInvalidUVIndex {
+		t.Errorf("expected %v, got %v", errInvalidUVIndex, err)
+	}
+}
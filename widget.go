@@ -0,0 +1,72 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Widget is the compact, flat JSON shape commonly consumed by embeddable
+// web weather widgets. Values are rounded for display.
+type Widget struct {
+	Location    string `json:"location"`
+	Country     string `json:"country,omitempty"`
+	Temp        int    `json:"temp"`
+	TempMin     int    `json:"temp_min"`
+	TempMax     int    `json:"temp_max"`
+	FeelsLike   int    `json:"feels_like"`
+	Humidity    int    `json:"humidity"`
+	WindSpeed   int    `json:"wind_speed"`
+	WindDeg     int    `json:"wind_deg"`
+	Description string `json:"description,omitempty"`
+	Icon        string `json:"icon,omitempty"`
+	IconURL     string `json:"icon_url,omitempty"`
+	Units       Unit   `json:"units"`
+	Updated     int    `json:"updated"`
+}
+
+// Widget converts the current conditions into the compact widget shape.
+func (w *CurrentWeatherData) Widget() *Widget {
+	wd := &Widget{
+		Location:  w.Name,
+		Country:   w.Sys.Country,
+		Temp:      int(math.Round(w.Main.Temp)),
+		TempMin:   int(math.Round(w.Main.TempMin)),
+		TempMax:   int(math.Round(w.Main.TempMax)),
+		FeelsLike: int(math.Round(w.Main.FeelsLike)),
+		Humidity:  w.Main.Humidity,
+		WindSpeed: int(math.Round(w.Wind.Speed)),
+		WindDeg:   int(math.Round(w.Wind.Deg)),
+		Units:     w.Unit,
+		Updated:   w.Dt,
+	}
+
+	if len(w.Weather) > 0 {
+		wd.Description = w.Weather[0].Description
+		wd.Icon = w.Weather[0].Icon
+		if wd.Icon != "" {
+			wd.IconURL = fmt.Sprintf(iconURL, wd.Icon+".png")
+		}
+	}
+
+	return wd
+}
+
+// WidgetJSON renders the current conditions as widget JSON.
+func (w *CurrentWeatherData) WidgetJSON() ([]byte, error) {
+	return json.Marshal(w.Widget())
+}
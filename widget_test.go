@@ -0,0 +1,43 @@
+// Copyright 2022 Brian J. Downs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openweathermap
+
+import (
+	"testing"
+)
+
+// TestWidgetJSON will verify the compact widget shape is rendered from the
+// current conditions.
+func TestWidgetJSON(t *testing.T) {
+	w := &CurrentWeatherData{
+		Name:    "London",
+		Sys:     Sys{Country: "GB"},
+		Weather: []Weather{{Description: "light rain", Icon: "10d"}},
+		Main:    Main{Temp: 14.6, TempMin: 12.2, TempMax: 16.5, FeelsLike: 13.9, Humidity: 81},
+		Wind:    Wind{Speed: 4.1, Deg: 240},
+		Dt:      1690000000,
+		Unit:    "metric",
+	}
+
+	b, err := w.WidgetJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"location":"London","country":"GB","temp":15,"temp_min":12,"temp_max":17,"feels_like":14,"humidity":81,"wind_speed":4,"wind_deg":240,"description":"light rain","icon":"10d","icon_url":"https://openweathermap.org/img/w/10d.png","units":"metric","updated":1690000000}`
+	if string(b) != expected {
+		t.Errorf("expected %s, got %s", expected, b)
+	}
+}